@@ -0,0 +1,618 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// errBinaryFormula defined the error message on the formula tokens of the
+// binary workbook can not be converted to the formula string.
+var errBinaryFormula = errors.New("unsupported binary formula")
+
+// binaryErrorCodes defined the formula error values in the binary workbook.
+var binaryErrorCodes = map[byte]string{
+	0x00: formulaErrorNULL,
+	0x07: formulaErrorDIV,
+	0x0F: formulaErrorVALUE,
+	0x17: formulaErrorREF,
+	0x1D: formulaErrorNAME,
+	0x24: formulaErrorNUM,
+	0x2A: formulaErrorNA,
+	0x2B: formulaErrorGETTINGDATA,
+}
+
+// binaryFormulaOperators defined the binary operators of the formula tokens
+// in the binary workbook.
+var binaryFormulaOperators = map[byte]string{
+	0x03: "+", 0x04: "-", 0x05: "*", 0x06: "/", 0x07: "^", 0x08: "&",
+	0x09: "<", 0x0A: "<=", 0x0B: "=", 0x0C: ">=", 0x0D: ">", 0x0E: "<>",
+	0x0F: " ", 0x10: ",", 0x11: ":",
+}
+
+// binaryFormulaFuncs defined the built-in function names by the function
+// index of the formula tokens in the binary workbook.
+var binaryFormulaFuncs = map[uint16]string{
+	0: "COUNT", 1: "IF", 2: "ISNA", 3: "ISERROR", 4: "SUM", 5: "AVERAGE",
+	6: "MIN", 7: "MAX", 8: "ROW", 9: "COLUMN", 10: "NA", 11: "NPV",
+	12: "STDEV", 13: "DOLLAR", 14: "FIXED", 15: "SIN", 16: "COS", 17: "TAN",
+	18: "ATAN", 19: "PI", 20: "SQRT", 21: "EXP", 22: "LN", 23: "LOG10",
+	24: "ABS", 25: "INT", 26: "SIGN", 27: "ROUND", 28: "LOOKUP", 29: "INDEX",
+	30: "REPT", 31: "MID", 32: "LEN", 33: "VALUE", 34: "TRUE", 35: "FALSE",
+	36: "AND", 37: "OR", 38: "NOT", 39: "MOD", 40: "DCOUNT", 41: "DSUM",
+	42: "DAVERAGE", 43: "DMIN", 44: "DMAX", 45: "DSTDEV", 46: "VAR",
+	47: "DVAR", 48: "TEXT", 49: "LINEST", 50: "TREND", 51: "LOGEST",
+	52: "GROWTH", 56: "PV", 57: "FV", 58: "NPER", 59: "PMT", 60: "RATE",
+	61: "MIRR", 62: "IRR", 63: "RAND", 64: "MATCH", 65: "DATE", 66: "TIME",
+	67: "DAY", 68: "MONTH", 69: "YEAR", 70: "WEEKDAY", 71: "HOUR",
+	72: "MINUTE", 73: "SECOND", 74: "NOW", 75: "AREAS", 76: "ROWS",
+	77: "COLUMNS", 78: "OFFSET", 82: "SEARCH", 83: "TRANSPOSE", 86: "TYPE",
+	97: "ATAN2", 98: "ASIN", 99: "ACOS", 100: "CHOOSE", 101: "HLOOKUP",
+	102: "VLOOKUP", 105: "ISREF", 109: "LOG", 111: "CHAR", 112: "LOWER",
+	113: "UPPER", 114: "PROPER", 115: "LEFT", 116: "RIGHT", 117: "EXACT",
+	118: "TRIM", 119: "REPLACE", 120: "SUBSTITUTE", 121: "CODE", 124: "FIND",
+	125: "CELL", 126: "ISERR", 127: "ISTEXT", 128: "ISNUMBER", 129: "ISBLANK",
+	130: "T", 131: "N", 140: "DATEVALUE", 141: "TIMEVALUE", 142: "SLN",
+	143: "SYD", 144: "DDB", 148: "INDIRECT", 162: "CLEAN", 163: "MDETERM",
+	164: "MINVERSE", 165: "MMULT", 167: "IPMT", 168: "PPMT", 169: "COUNTA",
+	183: "PRODUCT", 184: "FACT", 189: "DPRODUCT", 190: "ISNONTEXT",
+	193: "STDEVP", 194: "VARP", 195: "DSTDEVP", 196: "DVARP", 197: "TRUNC",
+	198: "ISLOGICAL", 199: "DCOUNTA", 204: "USDOLLAR", 205: "FINDB",
+	206: "SEARCHB", 207: "REPLACEB", 208: "LEFTB", 209: "RIGHTB", 210: "MIDB",
+	211: "LENB", 212: "ROUNDUP", 213: "ROUNDDOWN", 214: "ASC", 215: "DBCS",
+	216: "RANK", 219: "ADDRESS", 220: "DAYS360", 221: "TODAY", 222: "VDB",
+	227: "MEDIAN", 228: "SUMPRODUCT", 229: "SINH", 230: "COSH", 231: "TANH",
+	232: "ASINH", 233: "ACOSH", 234: "ATANH", 235: "DGET", 244: "INFO",
+	247: "DB", 252: "FREQUENCY", 261: "ERROR.TYPE", 269: "AVEDEV",
+	270: "BETADIST", 271: "GAMMALN", 272: "BETAINV", 273: "BINOMDIST",
+	274: "CHIDIST", 275: "CHIINV", 276: "COMBIN", 277: "CONFIDENCE",
+	278: "CRITBINOM", 279: "EVEN", 280: "EXPONDIST", 281: "FDIST",
+	282: "FINV", 283: "FISHER", 284: "FISHERINV", 285: "FLOOR",
+	286: "GAMMADIST", 287: "GAMMAINV", 288: "CEILING", 289: "HYPGEOMDIST",
+	290: "LOGNORMDIST", 291: "LOGINV", 292: "NEGBINOMDIST", 293: "NORMDIST",
+	294: "NORMSDIST", 295: "NORMINV", 296: "NORMSINV", 297: "STANDARDIZE",
+	298: "ODD", 299: "PERMUT", 300: "POISSON", 301: "TDIST", 302: "WEIBULL",
+	303: "SUMXMY2", 304: "SUMX2MY2", 305: "SUMX2PY2", 306: "CHITEST",
+	307: "CORREL", 308: "COVAR", 309: "FORECAST", 310: "FTEST",
+	311: "INTERCEPT", 312: "PEARSON", 313: "RSQ", 314: "STEYX", 315: "SLOPE",
+	316: "TTEST", 317: "PROB", 318: "DEVSQ", 319: "GEOMEAN", 320: "HARMEAN",
+	321: "SUMSQ", 322: "KURT", 323: "SKEW", 324: "ZTEST", 325: "LARGE",
+	326: "SMALL", 327: "QUARTILE", 328: "PERCENTILE", 329: "PERCENTRANK",
+	330: "MODE", 331: "TRIMMEAN", 332: "TINV", 336: "CONCATENATE",
+	337: "POWER", 342: "RADIANS", 343: "DEGREES", 344: "SUBTOTAL",
+	345: "SUMIF", 346: "COUNTIF", 347: "COUNTBLANK", 350: "ISPMT",
+	351: "DATEDIF", 354: "ROMAN", 358: "GETPIVOTDATA", 359: "HYPERLINK",
+	360: "PHONETIC", 361: "AVERAGEA", 362: "MAXA", 363: "MINA",
+	364: "STDEVPA", 365: "VARPA", 366: "STDEVA", 367: "VARA",
+	368: "BAHTTEXT", 379: "RTD", 380: "CUBEVALUE", 381: "CUBEMEMBER",
+	382: "CUBEMEMBERPROPERTY", 383: "CUBERANKEDMEMBER", 384: "HEX2BIN",
+	385: "HEX2DEC", 386: "HEX2OCT", 387: "DEC2BIN", 388: "DEC2HEX",
+	389: "DEC2OCT", 390: "OCT2BIN", 391: "OCT2HEX", 392: "OCT2DEC",
+	393: "BIN2DEC", 394: "BIN2OCT", 395: "BIN2HEX", 396: "IMSUB",
+	397: "IMDIV", 398: "IMPOWER", 399: "IMABS", 400: "IMSQRT", 401: "IMLN",
+	402: "IMLOG2", 403: "IMLOG10", 404: "IMSIN", 405: "IMCOS", 406: "IMEXP",
+	407: "IMARGUMENT", 408: "IMCONJUGATE", 409: "IMAGINARY", 410: "IMREAL",
+	411: "COMPLEX", 412: "IMSUM", 413: "IMPRODUCT", 414: "SERIESSUM",
+	415: "FACTDOUBLE", 416: "SQRTPI", 417: "QUOTIENT", 418: "DELTA",
+	419: "GESTEP", 420: "ISEVEN", 421: "ISODD", 422: "MROUND", 423: "ERF",
+	424: "ERFC", 425: "BESSELJ", 426: "BESSELK", 427: "BESSELY",
+	428: "BESSELI", 429: "XIRR", 430: "XNPV", 431: "PRICEMAT",
+	432: "YIELDMAT", 433: "INTRATE", 434: "RECEIVED", 435: "DISC",
+	436: "PRICEDISC", 437: "YIELDDISC", 438: "TBILLEQ", 439: "TBILLPRICE",
+	440: "TBILLYIELD", 441: "PRICE", 442: "YIELD", 443: "DOLLARDE",
+	444: "DOLLARFR", 445: "NOMINAL", 446: "EFFECT", 447: "CUMPRINC",
+	448: "CUMIPMT", 449: "EDATE", 450: "EOMONTH", 451: "YEARFRAC",
+	452: "COUPDAYBS", 453: "COUPDAYS", 454: "COUPDAYSNC", 455: "COUPNCD",
+	456: "COUPNUM", 457: "COUPPCD", 458: "DURATION", 459: "MDURATION",
+	460: "ODDLPRICE", 461: "ODDLYIELD", 462: "ODDFPRICE", 463: "ODDFYIELD",
+	464: "RANDBETWEEN", 465: "WEEKNUM", 466: "AMORDEGRC", 467: "AMORLINC",
+	468: "CONVERT", 469: "ACCRINT", 470: "ACCRINTM", 471: "WORKDAY",
+	472: "NETWORKDAYS", 473: "GCD", 474: "MULTINOMIAL", 475: "LCM",
+	476: "FVSCHEDULE", 477: "CUBEKPIMEMBER", 478: "CUBESET",
+	479: "CUBESETCOUNT", 480: "IFERROR", 481: "COUNTIFS", 482: "SUMIFS",
+	483: "AVERAGEIF", 484: "AVERAGEIFS",
+}
+
+// binaryFormulaFuncArgs defined the number of arguments of the built-in
+// functions which have a fixed number of arguments, these functions are
+// stored as the function token without argument count in the binary
+// workbook.
+var binaryFormulaFuncArgs = map[uint16]int{
+	2: 1, 3: 1, 10: 0, 15: 1, 16: 1, 17: 1, 18: 1, 19: 0, 20: 1, 21: 1,
+	22: 1, 23: 1, 24: 1, 25: 1, 26: 1, 27: 2, 30: 2, 31: 3, 32: 1, 33: 1,
+	34: 0, 35: 0, 38: 1, 39: 2, 40: 3, 41: 3, 42: 3, 43: 3, 44: 3, 45: 3,
+	47: 3, 48: 2, 63: 0, 65: 3, 66: 3, 67: 1, 68: 1, 69: 1, 71: 1, 72: 1,
+	73: 1, 74: 0, 75: 1, 76: 1, 77: 1, 83: 1, 86: 1, 97: 2, 98: 1, 99: 1,
+	105: 1, 111: 1, 112: 1, 113: 1, 114: 1, 117: 2, 118: 1, 119: 4, 121: 1,
+	126: 1, 127: 1, 128: 1, 129: 1, 130: 1, 131: 1, 140: 1, 141: 1, 142: 3,
+	143: 4, 162: 1, 163: 1, 164: 1, 165: 2, 184: 1, 189: 3, 190: 1, 195: 3,
+	196: 3, 198: 1, 199: 3, 207: 4, 210: 3, 211: 1, 212: 2, 213: 2, 214: 1,
+	215: 1, 221: 0, 229: 1, 230: 1, 231: 1, 232: 1, 233: 1, 234: 1, 235: 3,
+	244: 1, 252: 2, 261: 1, 271: 1, 273: 4, 274: 2, 275: 2, 276: 2, 277: 3,
+	278: 3, 279: 1, 280: 3, 281: 3, 282: 3, 283: 1, 284: 1, 285: 2, 286: 4,
+	287: 3, 288: 2, 289: 4, 290: 3, 291: 3, 292: 3, 293: 4, 294: 1, 295: 3,
+	296: 1, 297: 3, 298: 1, 299: 2, 300: 3, 301: 3, 302: 4, 303: 2, 304: 2,
+	305: 2, 306: 2, 307: 2, 308: 2, 309: 3, 310: 2, 311: 2, 312: 2, 313: 2,
+	314: 2, 315: 2, 316: 4, 325: 2, 326: 2, 327: 2, 328: 2, 331: 2, 332: 2,
+	337: 2, 342: 1, 343: 1, 346: 2, 347: 1, 350: 4, 351: 3, 360: 1, 368: 1,
+}
+
+// binaryExternSheet directly maps the reference to the range of worksheets
+// for the 3D references in the binary workbook.
+type binaryExternSheet struct {
+	supBook, firstSheet, lastSheet int
+}
+
+// binaryFormulaParser defined the context for converting the parsed formula
+// tokens of the binary workbook to the formula string. The BIFF8 format of
+// the XLS and the BIFF12 format of the XLSB use the same formula tokens with
+// different size of the cell references and strings.
+type binaryFormulaParser struct {
+	biff8        bool
+	sheets       []string
+	names        []string
+	externNames  map[int][]string
+	externSheets []binaryExternSheet
+	selfSupBooks map[int]bool
+}
+
+// binaryReader defined the little-endian reader for the binary records.
+type binaryReader struct {
+	b   []byte
+	off int
+	err error
+}
+
+// next returns the next n bytes of the reader, the zero-filled bytes will be
+// returned if the data is out of range.
+func (r *binaryReader) next(n int) []byte {
+	if r.err != nil || n < 0 || r.off+n > len(r.b) {
+		r.err = ErrWorkbookFileFormat
+		return make([]byte, 8)
+	}
+	b := r.b[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+// u8 returns the next unsigned 8-bit integer of the reader.
+func (r *binaryReader) u8() uint8 { return r.next(1)[0] }
+
+// u16 returns the next unsigned 16-bit integer of the reader.
+func (r *binaryReader) u16() uint16 { return binary.LittleEndian.Uint16(r.next(2)) }
+
+// u32 returns the next unsigned 32-bit integer of the reader.
+func (r *binaryReader) u32() uint32 { return binary.LittleEndian.Uint32(r.next(4)) }
+
+// f64 returns the next IEEE 754 floating-point number of the reader.
+func (r *binaryReader) f64() float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(r.next(8)))
+}
+
+// utf16 returns the next n UTF-16 characters of the reader.
+func (r *binaryReader) utf16(n int) string {
+	b := r.next(n * 2)
+	if r.err != nil {
+		return ""
+	}
+	u := make([]uint16, n)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[i*2:])
+	}
+	return string(utf16.Decode(u))
+}
+
+// wideString returns the next length-prefixed UTF-16 string of the reader,
+// the nullable string with length 0xFFFFFFFF will be returned as empty string.
+func (r *binaryReader) wideString() string {
+	n := r.u32()
+	if n == math.MaxUint32 {
+		return ""
+	}
+	if int(n) > len(r.b)-r.off {
+		r.err = ErrWorkbookFileFormat
+		return ""
+	}
+	return r.utf16(int(n))
+}
+
+// biff8String returns the next BIFF8 Unicode string of the reader by given
+// length of the characters.
+func (r *binaryReader) biff8String(n int) string {
+	if r.u8()&0x01 == 0 {
+		b := r.next(n)
+		u := make([]rune, len(b))
+		for i, c := range b {
+			u[i] = rune(c)
+		}
+		return string(u)
+	}
+	return r.utf16(n)
+}
+
+// formatBinaryNumber returns the cell value string of the floating-point
+// number in the binary workbook.
+func formatBinaryNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// parse provides a function to convert the parsed formula tokens and the
+// additional data of the tokens to the formula string by given zero-based row
+// and column number of the cell, which used by the relative references in
+// the shared formulas.
+func (p *binaryFormulaParser) parse(rgce, rgcb []byte, row, col int) (string, error) {
+	var (
+		stack []string
+		r     = &binaryReader{b: rgce}
+		extra = &binaryReader{b: rgcb}
+		pop   = func(n int) ([]string, error) {
+			if len(stack) < n {
+				return nil, errBinaryFormula
+			}
+			args := make([]string, n)
+			copy(args, stack[len(stack)-n:])
+			stack = stack[:len(stack)-n]
+			return args, nil
+		}
+	)
+	for r.off < len(rgce) && r.err == nil {
+		ptg := r.u8()
+		if op, ok := binaryFormulaOperators[ptg]; ok {
+			args, err := pop(2)
+			if err != nil {
+				return "", err
+			}
+			stack = append(stack, args[0]+op+args[1])
+			continue
+		}
+		switch ptg {
+		case 0x12, 0x13, 0x14: // PtgUplus, PtgUminus, PtgPercent
+			args, err := pop(1)
+			if err != nil {
+				return "", err
+			}
+			stack = append(stack, map[byte]string{0x12: "+" + args[0], 0x13: "-" + args[0], 0x14: args[0] + "%"}[ptg])
+		case 0x15: // PtgParen
+			args, err := pop(1)
+			if err != nil {
+				return "", err
+			}
+			stack = append(stack, "("+args[0]+")")
+		case 0x16: // PtgMissArg
+			stack = append(stack, "")
+		case 0x17: // PtgStr
+			var s string
+			if p.biff8 {
+				s = r.biff8String(int(r.u8()))
+			} else {
+				s = r.utf16(int(r.u16()))
+			}
+			stack = append(stack, `"`+strings.ReplaceAll(s, `"`, `""`)+`"`)
+		case 0x19: // PtgAttr
+			attr, data := r.u8(), r.u16()
+			if attr&0x04 != 0 { // tAttrChoose
+				r.next((int(data) + 1) * 2)
+			}
+			if attr&0x10 != 0 { // tAttrSum
+				args, err := pop(1)
+				if err != nil {
+					return "", err
+				}
+				stack = append(stack, "SUM("+args[0]+")")
+			}
+		case 0x1C: // PtgErr
+			stack = append(stack, binaryErrorCodes[r.u8()])
+		case 0x1D: // PtgBool
+			stack = append(stack, map[bool]string{true: "TRUE", false: "FALSE"}[r.u8() != 0])
+		case 0x1E: // PtgInt
+			stack = append(stack, strconv.Itoa(int(r.u16())))
+		case 0x1F: // PtgNum
+			stack = append(stack, strconv.FormatFloat(r.f64(), 'G', -1, 64))
+		default:
+			if ptg < 0x20 || ptg > 0x7F {
+				return "", errBinaryFormula
+			}
+			token, err := p.parseOperand(ptg&0x1F|0x20, r, extra, row, col, pop)
+			if err != nil {
+				return "", err
+			}
+			if token != nil {
+				stack = append(stack, *token)
+			}
+		}
+	}
+	if r.err != nil || len(stack) != 1 {
+		return "", errBinaryFormula
+	}
+	return stack[0], nil
+}
+
+// parseOperand provides a function to parse the class-typed operand and
+// function tokens of the binary formula.
+func (p *binaryFormulaParser) parseOperand(ptg byte, r, extra *binaryReader, row, col int, pop func(n int) ([]string, error)) (*string, error) {
+	var token string
+	switch ptg {
+	case 0x20: // PtgArray
+		r.next(map[bool]int{true: 7, false: 14}[p.biff8])
+		token = p.parseArray(extra)
+	case 0x21, 0x22: // PtgFunc, PtgFuncVar
+		var argc int
+		if ptg == 0x22 {
+			argc = int(r.u8() & 0x7F)
+		}
+		iftab := r.u16() & 0x7FFF
+		if ptg == 0x21 {
+			n, ok := binaryFormulaFuncArgs[iftab]
+			if !ok {
+				return nil, errBinaryFormula
+			}
+			argc = n
+		}
+		args, err := pop(argc)
+		if err != nil {
+			return nil, err
+		}
+		name, ok := binaryFormulaFuncs[iftab]
+		if iftab == 0xFF && len(args) > 0 {
+			name, args, ok = args[0], args[1:], true
+		}
+		if !ok {
+			return nil, errBinaryFormula
+		}
+		token = name + "(" + strings.Join(args, ",") + ")"
+	case 0x23: // PtgName
+		idx := int(r.u32())
+		if idx < 1 || idx > len(p.names) {
+			return nil, errBinaryFormula
+		}
+		token = p.names[idx-1]
+	case 0x24, 0x2C: // PtgRef, PtgRefN
+		token = p.parseRef(r, ptg == 0x2C, row, col)
+	case 0x25, 0x2D: // PtgArea, PtgAreaN
+		token = p.parseArea(r, ptg == 0x2D, row, col)
+	case 0x26, 0x27, 0x28: // PtgMemArea, PtgMemErr, PtgMemNoMem
+		r.next(6)
+		return nil, nil
+	case 0x29: // PtgMemFunc
+		r.next(2)
+		return nil, nil
+	case 0x2A: // PtgRefErr
+		r.next(map[bool]int{true: 4, false: 6}[p.biff8])
+		token = formulaErrorREF
+	case 0x2B: // PtgAreaErr
+		r.next(map[bool]int{true: 8, false: 12}[p.biff8])
+		token = formulaErrorREF
+	case 0x39: // PtgNameX
+		ixti, idx := int(r.u16()), int(r.u32())
+		names := p.externNames[ixti]
+		if idx < 1 || idx > len(names) {
+			return nil, errBinaryFormula
+		}
+		token = names[idx-1]
+	case 0x3A, 0x3B, 0x3C, 0x3D: // PtgRef3d, PtgArea3d, PtgRefErr3d, PtgAreaErr3d
+		prefix, err := p.parseExternSheet(int(r.u16()))
+		if err != nil {
+			return nil, err
+		}
+		switch ptg {
+		case 0x3A:
+			token = prefix + p.parseRef(r, false, row, col)
+		case 0x3B:
+			token = prefix + p.parseArea(r, false, row, col)
+		case 0x3C:
+			r.next(map[bool]int{true: 4, false: 6}[p.biff8])
+			token = prefix + formulaErrorREF
+		default:
+			r.next(map[bool]int{true: 8, false: 12}[p.biff8])
+			token = prefix + formulaErrorREF
+		}
+	default:
+		return nil, errBinaryFormula
+	}
+	return &token, r.err
+}
+
+// parseExternSheet returns the worksheet name prefix of the 3D reference by
+// given index of the extern sheet.
+func (p *binaryFormulaParser) parseExternSheet(ixti int) (string, error) {
+	if ixti >= len(p.externSheets) {
+		return "", errBinaryFormula
+	}
+	xti := p.externSheets[ixti]
+	if !p.selfSupBooks[xti.supBook] || xti.firstSheet < 0 || xti.lastSheet >= len(p.sheets) || xti.firstSheet >= len(p.sheets) {
+		return "", errBinaryFormula
+	}
+	if xti.lastSheet > xti.firstSheet {
+		return escapeSheetName(p.sheets[xti.firstSheet]+":"+p.sheets[xti.lastSheet]) + "!", nil
+	}
+	return escapeSheetName(p.sheets[xti.firstSheet]) + "!", nil
+}
+
+// parseCellRef returns the cell reference by given row number, column field
+// of the token, relative reference flag and the zero-based row and column
+// number of the cell.
+func (p *binaryFormulaParser) parseCellRef(rw int, colField uint16, relative bool, row, col int) string {
+	c, rowMask, colMask := int(colField&0x3FFF), TotalRows-1, MaxColumns-1
+	if p.biff8 {
+		c, rowMask, colMask = int(colField&0xFF), 0xFFFF, 0xFF
+	}
+	colRelative, rowRelative := colField&0x4000 != 0, colField&0x8000 != 0
+	if relative && rowRelative {
+		if p.biff8 {
+			rw = int(int16(rw))
+		}
+		rw = (row + rw) & rowMask
+	}
+	if relative && colRelative {
+		if p.biff8 {
+			c = int(int8(c))
+		} else if c&0x2000 != 0 {
+			c -= 0x4000
+		}
+		c = (col + c) & colMask
+	}
+	name, _ := ColumnNumberToName(c + 1)
+	if !colRelative {
+		name = "$" + name
+	}
+	if !rowRelative {
+		return name + "$" + strconv.Itoa(rw+1)
+	}
+	return name + strconv.Itoa(rw+1)
+}
+
+// parseRef returns the cell reference of the reference token.
+func (p *binaryFormulaParser) parseRef(r *binaryReader, relative bool, row, col int) string {
+	var rw int
+	if p.biff8 {
+		rw = int(r.u16())
+	} else {
+		rw = int(int32(r.u32()))
+	}
+	return p.parseCellRef(rw, r.u16(), relative, row, col)
+}
+
+// parseArea returns the range reference of the area token.
+func (p *binaryFormulaParser) parseArea(r *binaryReader, relative bool, row, col int) string {
+	var rwFirst, rwLast int
+	if p.biff8 {
+		rwFirst, rwLast = int(r.u16()), int(r.u16())
+	} else {
+		rwFirst, rwLast = int(int32(r.u32())), int(int32(r.u32()))
+	}
+	colFirst, colLast := r.u16(), r.u16()
+	return p.parseCellRef(rwFirst, colFirst, relative, row, col) + ":" + p.parseCellRef(rwLast, colLast, relative, row, col)
+}
+
+// parseArray returns the array constant of the array token from the
+// additional data of the formula tokens.
+func (p *binaryFormulaParser) parseArray(extra *binaryReader) string {
+	var rows, cols int
+	if p.biff8 {
+		cols, rows = int(extra.u8())+1, int(extra.u16())+1
+	} else {
+		rows, cols = int(extra.u32()), int(extra.u32())
+	}
+	var rowValues []string
+	for i := 0; i < rows && extra.err == nil; i++ {
+		values := make([]string, cols)
+		for j := 0; j < cols && extra.err == nil; j++ {
+			values[j] = p.parseArrayValue(extra)
+		}
+		rowValues = append(rowValues, strings.Join(values, ","))
+	}
+	return "{" + strings.Join(rowValues, ";") + "}"
+}
+
+// parseArrayValue returns the value of the array constant element.
+func (p *binaryFormulaParser) parseArrayValue(extra *binaryReader) string {
+	typ := extra.u8()
+	if p.biff8 {
+		switch typ {
+		case 0x01:
+			return strconv.FormatFloat(extra.f64(), 'G', -1, 64)
+		case 0x02:
+			return `"` + strings.ReplaceAll(extra.biff8String(int(extra.u16())), `"`, `""`) + `"`
+		case 0x04:
+			v := extra.next(8)[0] != 0
+			return map[bool]string{true: "TRUE", false: "FALSE"}[v]
+		case 0x10:
+			return binaryErrorCodes[extra.next(8)[0]]
+		}
+		extra.next(8)
+		return ""
+	}
+	switch typ {
+	case 0x00:
+		return strconv.FormatFloat(extra.f64(), 'G', -1, 64)
+	case 0x01:
+		return `"` + strings.ReplaceAll(extra.utf16(int(extra.u16())), `"`, `""`) + `"`
+	case 0x02:
+		return map[bool]string{true: "TRUE", false: "FALSE"}[extra.u8() != 0]
+	case 0x04:
+		return binaryErrorCodes[extra.u8()]
+	}
+	extra.err = errBinaryFormula
+	return ""
+}
+
+// binaryHorizontalAlignments defined the horizontal alignment types of the cell
+// format in the binary workbook.
+var binaryHorizontalAlignments = []string{"", "left", "center", "right", "fill", "justify", "centerContinuous", "distributed"}
+
+// binaryVerticalAlignments defined the vertical alignment types of the cell
+// format in the binary workbook.
+var binaryVerticalAlignments = []string{"top", "center", "", "justify", "distributed"}
+
+// binaryPatternTypes defined the pattern fill types of the fill in the binary
+// workbook.
+var binaryPatternTypes = []string{
+	"none", "solid", "mediumGray", "darkGray", "lightGray", "darkHorizontal",
+	"darkVertical", "darkDown", "darkUp", "darkGrid", "darkTrellis",
+	"lightHorizontal", "lightVertical", "lightDown", "lightUp", "lightGrid",
+	"lightTrellis", "gray125", "gray0625",
+}
+
+// binaryBorderStyles defined the border line styles in the binary workbook.
+var binaryBorderStyles = []string{
+	"", "thin", "medium", "dashed", "dotted", "thick", "double", "hair",
+	"mediumDashed", "dashDot", "mediumDashDot", "dashDotDot",
+	"mediumDashDotDot", "slantDashDot",
+}
+
+// binaryUnderlineTypes defined the underline types of the font in the binary
+// workbook.
+var binaryUnderlineTypes = map[uint8]string{1: "single", 2: "double", 0x21: "singleAccounting", 0x22: "doubleAccounting"}
+
+// binaryFormulaCell defined the cell which formula is stored in the shared or
+// array formula record.
+type binaryFormulaCell struct {
+	row, col, rowIdx, cellIdx int
+}
+
+// binaryFormulaRef defined the shared or array formula record.
+type binaryFormulaRef struct {
+	array       bool
+	coordinates []int
+	rgce, rgcb  []byte
+}
+
+// resolveFormulaRefs provides a function to set the formulas of the cells
+// which formula is stored in the shared or array formula records.
+func (p *binaryFormulaParser) resolveFormulaRefs(ws *xlsxWorksheet, refs []binaryFormulaRef, cells []binaryFormulaCell) {
+	for _, cell := range cells {
+		for _, ref := range refs {
+			if cell.row < ref.coordinates[0] || cell.row > ref.coordinates[1] || cell.col < ref.coordinates[2] || cell.col > ref.coordinates[3] {
+				continue
+			}
+			c := &ws.SheetData.Row[cell.rowIdx].C[cell.cellIdx]
+			if ref.array {
+				if cell.row == ref.coordinates[0] && cell.col == ref.coordinates[2] {
+					if formula, err := p.parse(ref.rgce, ref.rgcb, cell.row, cell.col); err == nil {
+						rangeRef, _ := coordinatesToRangeRef([]int{ref.coordinates[2] + 1, ref.coordinates[0] + 1, ref.coordinates[3] + 1, ref.coordinates[1] + 1})
+						c.F = &xlsxF{Content: formula, T: STCellFormulaTypeArray, Ref: rangeRef}
+					}
+				}
+				break
+			}
+			if formula, err := p.parse(ref.rgce, ref.rgcb, cell.row, cell.col); err == nil {
+				c.F = &xlsxF{Content: formula}
+			}
+			break
+		}
+	}
+}
@@ -193,6 +193,12 @@ var (
 			return fmt.Sprintf("R[%d]C[%d]", row, col), nil
 		},
 	}
+	formulaErrorCodes = map[string]struct{}{
+		formulaErrorDIV: {}, formulaErrorNAME: {}, formulaErrorNA: {},
+		formulaErrorNUM: {}, formulaErrorVALUE: {}, formulaErrorREF: {},
+		formulaErrorNULL: {}, formulaErrorSPILL: {}, formulaErrorCALC: {},
+		formulaErrorGETTINGDATA: {},
+	}
 	formulaFormats = []*regexp.Regexp{
 		regexp.MustCompile(`^(\d+)$`),
 		regexp.MustCompile(`^=(.*)$`),
@@ -220,6 +226,40 @@ type calcContext struct {
 	maxCalcIterations uint
 	iterations        map[string]uint
 	iterationsCache   map[string]formulaArg
+	now               time.Time
+	rand              *rand.Rand
+	arrayResult       bool
+}
+
+// isArrayResult returns if the array result of the formula function should be
+// kept without implicit intersection by given worksheet name and cell
+// reference, only the result of the entry cell is kept as an array.
+func (ctx *calcContext) isArrayResult(sheet, cell string) bool {
+	return ctx.arrayResult && ctx.entry == fmt.Sprintf("%s!%s", sheet, cell)
+}
+
+// newCalcContext create a formula execution context by given entry cell and
+// options, the current date and time and the random number generator used by
+// the volatile functions are resolved once per context.
+func newCalcContext(entry string, opts *Options) *calcContext {
+	now, seed := opts.CalcTime, opts.CalcRandSeed
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if opts.CalcLocation != nil {
+		now = now.In(opts.CalcLocation)
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &calcContext{
+		entry:             entry,
+		maxCalcIterations: opts.MaxCalcIterations,
+		iterations:        make(map[string]uint),
+		iterationsCache:   make(map[string]formulaArg),
+		now:               now,
+		rand:              rand.New(rand.NewSource(seed)),
+	}
 }
 
 // cellRef defines the structure of a cell reference.
@@ -353,6 +393,25 @@ type formulaFuncs struct {
 	sheet, cell string
 }
 
+// now returns the current date and time of the formula execution context, or
+// the system clock if the formula functions are used without context.
+func (fn *formulaFuncs) now() time.Time {
+	if fn.ctx == nil {
+		return time.Now()
+	}
+	return fn.ctx.now
+}
+
+// rand returns the random number generator of the formula execution context,
+// or a generator seeded from the system clock if the formula functions are
+// used without context.
+func (fn *formulaFuncs) rand() *rand.Rand {
+	if fn.ctx == nil {
+		return rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return fn.ctx.rand
+}
+
 // CalcCellValue provides a function to get calculated cell value. This feature
 // is currently in working processing. Iterative calculation, implicit
 // intersection, explicit intersection, array formula, table formula and some
@@ -814,20 +873,19 @@ type formulaFuncs struct {
 //	ZTEST
 func (f *File) CalcCellValue(sheet, cell string, opts ...Options) (result string, err error) {
 	options := f.getOptions(opts...)
-	var (
-		rawCellValue = options.RawCellValue
-		styleIdx     int
-		token        formulaArg
-	)
-	if token, err = f.calcCellValue(&calcContext{
-		entry:             fmt.Sprintf("%s!%s", sheet, cell),
-		maxCalcIterations: options.MaxCalcIterations,
-		iterations:        make(map[string]uint),
-		iterationsCache:   make(map[string]formulaArg),
-	}, sheet, cell); err != nil {
+	var token formulaArg
+	if token, err = f.calcCellValue(newCalcContext(fmt.Sprintf("%s!%s", sheet, cell), options), sheet, cell); err != nil {
 		result = token.String
 		return
 	}
+	return f.formatCalcResult(sheet, cell, token, options.RawCellValue)
+}
+
+// formatCalcResult provides a function to convert the formula calculation
+// result to string by given worksheet name, cell reference, result and if
+// apply the number format of the cell.
+func (f *File) formatCalcResult(sheet, cell string, token formulaArg, rawCellValue bool) (result string, err error) {
+	var styleIdx int
 	if !rawCellValue {
 		styleIdx, _ = f.GetCellStyle(sheet, cell)
 	}
@@ -846,6 +904,182 @@ func (f *File) CalcCellValue(sheet, cell string, opts ...Options) (result string
 	return
 }
 
+// FormulaResultType is the type of formula calculation result.
+type FormulaResultType byte
+
+// Formula calculation result types enumeration.
+const (
+	FormulaResultEmpty FormulaResultType = iota
+	FormulaResultNumber
+	FormulaResultString
+	FormulaResultBool
+	FormulaResultError
+	FormulaResultArray
+)
+
+// FormulaResult directly maps the typed result of the formula calculation.
+// Only the field which corresponding to the result type is set: Number for
+// number, String for string, Bool for boolean, Error for error code (such as
+// #DIV/0!) and Array for the two-dimensional array result.
+type FormulaResult struct {
+	Type   FormulaResultType
+	Number float64
+	String string
+	Bool   bool
+	Error  string
+	Array  [][]FormulaResult
+	f      *File
+	sheet  string
+	cell   string
+	arg    formulaArg
+}
+
+// newFormulaResult create typed formula calculation result by given formula
+// argument.
+func newFormulaResult(arg formulaArg) FormulaResult {
+	result := FormulaResult{arg: arg}
+	switch arg.Type {
+	case ArgNumber:
+		if arg.Boolean {
+			result.Type, result.Bool = FormulaResultBool, arg.Number != 0
+			break
+		}
+		result.Type, result.Number = FormulaResultNumber, arg.Number
+	case ArgString:
+		result.Type, result.String = FormulaResultString, arg.String
+	case ArgError:
+		result.Type, result.Error = FormulaResultError, arg.String
+	case ArgList:
+		result.Type = FormulaResultArray
+		row := make([]FormulaResult, 0, len(arg.List))
+		for _, cell := range arg.List {
+			row = append(row, newFormulaResult(cell))
+		}
+		result.Array = [][]FormulaResult{row}
+	case ArgMatrix:
+		result.Type = FormulaResultArray
+		for _, cells := range arg.Matrix {
+			row := make([]FormulaResult, 0, len(cells))
+			for _, cell := range cells {
+				row = append(row, newFormulaResult(cell))
+			}
+			result.Array = append(result.Array, row)
+		}
+	}
+	return result
+}
+
+// Value returns the formula calculation result as float64, string, bool,
+// [][]interface{} for array results, or nil for empty results. The error code
+// will be returned as string for error results.
+func (r FormulaResult) Value() interface{} {
+	switch r.Type {
+	case FormulaResultNumber:
+		return r.Number
+	case FormulaResultString:
+		return r.String
+	case FormulaResultBool:
+		return r.Bool
+	case FormulaResultError:
+		return r.Error
+	case FormulaResultArray:
+		values := make([][]interface{}, 0, len(r.Array))
+		for _, cells := range r.Array {
+			row := make([]interface{}, 0, len(cells))
+			for _, cell := range cells {
+				row = append(row, cell.Value())
+			}
+			values = append(values, row)
+		}
+		return values
+	}
+	return nil
+}
+
+// FormattedValue returns the formula calculation result as string with the
+// number format of the calculated cell applied, which is the same as the
+// result of the CalcCellValue function.
+func (r FormulaResult) FormattedValue() (string, error) {
+	if r.Type == FormulaResultError {
+		return r.Error, nil
+	}
+	if r.f == nil {
+		return r.arg.Value(), nil
+	}
+	return r.f.formatCalcResult(r.sheet, r.cell, r.arg, false)
+}
+
+// CalcCellResult provides a function to get the typed calculated cell value,
+// the result is independent of the number format of the cell, and the
+// formatted string can be produced by the FormattedValue function of the
+// result. Formula errors such as #DIV/0! are returned as the result of the
+// FormulaResultError type instead of the error. For example, get the
+// calculated value of the cell A3 on Sheet1:
+//
+//	result, err := f.CalcCellResult("Sheet1", "A3")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	switch result.Type {
+//	case excelize.FormulaResultNumber:
+//	    fmt.Println(result.Number)
+//	case excelize.FormulaResultError:
+//	    fmt.Println(result.Error)
+//	}
+//
+// The supported formula functions are the same as the CalcCellValue function.
+func (f *File) CalcCellResult(sheet, cell string, opts ...Options) (FormulaResult, error) {
+	ctx := newCalcContext(fmt.Sprintf("%s!%s", sheet, cell), f.getOptions(opts...))
+	ctx.arrayResult = true
+	token, err := f.calcCellValue(ctx, sheet, cell)
+	return f.newCellFormulaResult(sheet, cell, token, err)
+}
+
+// EvalFormula provides a function to evaluate the formula string in the
+// context of the given worksheet name and anchor cell reference without
+// writing the formula into the cell. The references, defined names and
+// functions are resolved in the same way as the CalcCellValue function, and
+// the result will be formatted with the number format of the anchor cell by
+// the FormattedValue function of the result. For example, evaluate the
+// formula =SUMIFS(Data!C:C,Data!A:A,"East") in the context of the cell A1 on
+// Sheet1:
+//
+//	result, err := f.EvalFormula("Sheet1", "A1", `=SUMIFS(Data!C:C,Data!A:A,"East")`)
+func (f *File) EvalFormula(sheet, cell, formula string, opts ...Options) (FormulaResult, error) {
+	if _, err := f.workSheetReader(sheet); err != nil {
+		return FormulaResult{}, err
+	}
+	if _, _, err := CellNameToCoordinates(cell); err != nil {
+		return FormulaResult{}, err
+	}
+	ctx := newCalcContext(fmt.Sprintf("%s!%s", sheet, cell), f.getOptions(opts...))
+	ctx.arrayResult = true
+	ps := efp.ExcelParser()
+	tokens := ps.Parse(strings.TrimPrefix(formula, "="))
+	if tokens == nil {
+		return FormulaResult{}, ErrInvalidFormula
+	}
+	token, err := f.evalInfixExp(ctx, sheet, cell, tokens)
+	return f.newCellFormulaResult(sheet, cell, token, err)
+}
+
+// newCellFormulaResult create typed formula calculation result by given
+// worksheet name, cell reference, calculated formula argument and error.
+func (f *File) newCellFormulaResult(sheet, cell string, token formulaArg, err error) (FormulaResult, error) {
+	if err != nil {
+		if token.Type != ArgError {
+			if _, ok := formulaErrorCodes[err.Error()]; !ok {
+				return FormulaResult{}, err
+			}
+			token = newErrorFormulaArg(err.Error(), err.Error())
+		}
+	}
+	result := newFormulaResult(token)
+	result.f, result.sheet, result.cell = f, sheet, cell
+	return result, nil
+}
+
 // calcCellValue calculate cell value by given context, worksheet name and cell
 // reference.
 func (f *File) calcCellValue(ctx *calcContext, sheet, cell string) (result formulaArg, err error) {
@@ -1092,7 +1326,7 @@ func (f *File) evalInfixExpFunc(ctx *calcContext, sheet, cell string, token, nex
 		argsStack.Peek().(*list.List).PushBack(arg)
 		return newEmptyFormulaArg()
 	}
-	if arg.Type == ArgMatrix && len(arg.Matrix) > 0 && len(arg.Matrix[0]) > 0 {
+	if arg.Type == ArgMatrix && len(arg.Matrix) > 0 && len(arg.Matrix[0]) > 0 && !ctx.isArrayResult(sheet, cell) {
 		opdStack.Push(arg.Matrix[0][0])
 		return newEmptyFormulaArg()
 	}
@@ -5141,7 +5375,7 @@ func (fn *formulaFuncs) RAND(argsList *list.List) formulaArg {
 	if argsList.Len() != 0 {
 		return newErrorFormulaArg(formulaErrorVALUE, "RAND accepts no arguments")
 	}
-	return newNumberFormulaArg(rand.New(rand.NewSource(time.Now().UnixNano())).Float64())
+	return newNumberFormulaArg(fn.rand().Float64())
 }
 
 // RANDBETWEEN function generates a random integer between two supplied
@@ -5163,7 +5397,7 @@ func (fn *formulaFuncs) RANDBETWEEN(argsList *list.List) formulaArg {
 	if top.Number < bottom.Number {
 		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
 	}
-	num := rand.New(rand.NewSource(time.Now().UnixNano())).Int63n(int64(top.Number - bottom.Number + 1))
+	num := fn.rand().Int63n(int64(top.Number - bottom.Number + 1))
 	return newNumberFormulaArg(float64(num + int64(bottom.Number)))
 }
 
@@ -13215,7 +13449,7 @@ func (fn *formulaFuncs) NOW(argsList *list.List) formulaArg {
 	if argsList.Len() != 0 {
 		return newErrorFormulaArg(formulaErrorVALUE, "NOW accepts no arguments")
 	}
-	now := time.Now()
+	now := fn.now()
 	_, offset := now.Zone()
 	return newNumberFormulaArg(25569.0 + float64(now.Unix()+int64(offset))/86400)
 }
@@ -13311,7 +13545,7 @@ func (fn *formulaFuncs) TODAY(argsList *list.List) formulaArg {
 	if argsList.Len() != 0 {
 		return newErrorFormulaArg(formulaErrorVALUE, "TODAY accepts no arguments")
 	}
-	now := time.Now()
+	now := fn.now()
 	_, offset := now.Zone()
 	return newNumberFormulaArg(daysBetween(excelMinTime1900.Unix(), now.Unix()+int64(offset)) + 1)
 }
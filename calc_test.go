@@ -2,10 +2,12 @@ package excelize
 
 import (
 	"container/list"
+	"fmt"
 	"math"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/xuri/efp"
@@ -4687,6 +4689,33 @@ func TestCalcCellValue(t *testing.T) {
 		_, err := f.CalcCellValue("Sheet1", "C1")
 		assert.NoError(t, err)
 	}
+	// Test calculate volatile functions with fixed time and random seed
+	calcOpts := Options{
+		CalcTime:     time.Date(2024, 3, 15, 23, 30, 0, 0, time.UTC),
+		CalcLocation: time.FixedZone("UTC+2", 7200),
+		CalcRandSeed: 42,
+		RawCellValue: true,
+	}
+	volatileFuncsCalc := map[string]string{
+		"=NOW()":   "45367.0625",
+		"=TODAY()": "45367",
+	}
+	for formula, expected := range volatileFuncsCalc {
+		f := prepareCalcData(cellData)
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		result, err := f.CalcCellValue("Sheet1", "C1", calcOpts)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, formula)
+	}
+	for _, formula := range []string{"=RAND()", "=RANDBETWEEN(1,1000)", "=RAND()+RAND()"} {
+		f := prepareCalcData(cellData)
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		expected, err := f.CalcCellValue("Sheet1", "C1", calcOpts)
+		assert.NoError(t, err)
+		result, err := f.CalcCellValue("Sheet1", "C1", calcOpts)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, formula)
+	}
 
 	// Test get calculated cell value on not formula cell
 	f := prepareCalcData(cellData)
@@ -4747,6 +4776,122 @@ func TestCalcWithDefinedName(t *testing.T) {
 	assert.Equal(t, "YES", result, "=IF(\"B1_as_string\"=defined_name1,\"YES\",\"NO\")")
 }
 
+func TestCalcCellResult(t *testing.T) {
+	f := prepareCalcData([][]interface{}{{1, 2}, {3, 4}})
+	for formula, expected := range map[string]interface{}{
+		"=A1+B1":      float64(3),
+		"=\"TRUE\"":   "TRUE",
+		"=TRUE":       true,
+		"=1/0":        "#DIV/0!",
+		"=NA()":       "#N/A",
+		"=ABS(\"X\")": "#VALUE!",
+		"=MUNIT(2)":   [][]interface{}{{float64(1), float64(0)}, {float64(0), float64(1)}},
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		result, err := f.CalcCellResult("Sheet1", "C1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result.Value(), formula)
+	}
+	// Test get typed result with number format
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "=DATE(2024,1,1)"))
+	style, err := f.NewStyle(&Style{NumFmt: 14})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "C1", "C1", style))
+	result, err := f.CalcCellResult("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, FormulaResultNumber, result.Type)
+	assert.Equal(t, float64(45292), result.Number)
+	formatted, err := result.FormattedValue()
+	assert.NoError(t, err)
+	assert.Equal(t, "01-01-24", formatted)
+	// Test get typed result of error and boolean
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "=1/0"))
+	result, err = f.CalcCellResult("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, FormulaResultError, result.Type)
+	formatted, err = result.FormattedValue()
+	assert.NoError(t, err)
+	assert.Equal(t, "#DIV/0!", formatted)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "=1=1"))
+	result, err = f.CalcCellResult("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, FormulaResultBool, result.Type)
+	assert.True(t, result.Bool)
+	formatted, err = result.FormattedValue()
+	assert.NoError(t, err)
+	assert.Equal(t, "TRUE", formatted)
+	// Test get typed result of empty result
+	assert.Nil(t, newFormulaResult(newEmptyFormulaArg()).Value())
+	formatted, err = newFormulaResult(newStringFormulaArg("text")).FormattedValue()
+	assert.NoError(t, err)
+	assert.Equal(t, "text", formatted)
+	// Test get typed result of list result
+	assert.Equal(t, [][]interface{}{{float64(1), "A"}}, newFormulaResult(newListFormulaArg([]formulaArg{newNumberFormulaArg(1), newStringFormulaArg("A")})).Value())
+	// Test get typed result of the formula which references the array formula
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "=MUNIT(2)"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "=SUM(C1)"))
+	value, err := f.CalcCellValue("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", value)
+	result, err = f.CalcCellResult("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), result.Value())
+	// Test get typed result with invalid formula
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "=1+\"X\""))
+	_, err = f.CalcCellResult("Sheet1", "C1")
+	assert.EqualError(t, err, "strconv.ParseFloat: parsing \"X\": invalid syntax")
+	// Test get typed result on not exists worksheet
+	_, err = f.CalcCellResult("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+}
+
+func TestEvalFormula(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Data")
+	assert.NoError(t, err)
+	for r, row := range [][]interface{}{{"East", 10}, {"West", 20}, {"East", 30}} {
+		assert.NoError(t, f.SetSheetRow("Data", fmt.Sprintf("A%d", r+1), &row))
+	}
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Data!$B$1:$B$3"}))
+	for formula, expected := range map[string]interface{}{
+		`=SUMIFS(Data!B1:B3,Data!A1:A3,"East")`: float64(40),
+		"SUM(Amount)":                           float64(60),
+		"=ROW()":                                float64(5),
+		"=Data!A2":                              "West",
+		"=1/0":                                  "#DIV/0!",
+	} {
+		result, err := f.EvalFormula("Sheet1", "B5", formula)
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result.Value(), formula)
+	}
+	// Test evaluate formula which references the array formula
+	assert.NoError(t, f.SetCellFormula("Data", "D1", "=MUNIT(2)"))
+	for formula, expected := range map[string]interface{}{
+		"=SUM(Data!D1)": float64(1),
+		"=MUNIT(2)":     [][]interface{}{{float64(1), float64(0)}, {float64(0), float64(1)}},
+	} {
+		result, err := f.EvalFormula("Sheet1", "B5", formula)
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result.Value(), formula)
+	}
+	// Test evaluate formula without modify the anchor cell
+	value, err := f.GetCellValue("Sheet1", "B5")
+	assert.NoError(t, err)
+	assert.Empty(t, value)
+	formula, err := f.GetCellFormula("Sheet1", "B5")
+	assert.NoError(t, err)
+	assert.Empty(t, formula)
+	// Test evaluate formula with invalid formula
+	_, err = f.EvalFormula("Sheet1", "B5", "=")
+	assert.Equal(t, ErrInvalidFormula, err)
+	// Test evaluate formula on not exists worksheet
+	_, err = f.EvalFormula("SheetN", "B5", "=1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test evaluate formula with invalid anchor cell reference
+	_, err = f.EvalFormula("Sheet1", "B", "=1")
+	assert.Equal(t, newCellNameToCoordinatesError("B", newInvalidCellNameError("B")), err)
+}
+
 func TestCalcISBLANK(t *testing.T) {
 	argsList := list.New()
 	argsList.PushBack(formulaArg{
@@ -26,6 +26,7 @@ func (f *File) calcChainReader() (*xlsxCalcChain, error) {
 			Decode(f.CalcChain); err != nil && err != io.EOF {
 			return f.CalcChain, err
 		}
+		f.trackPart(defaultXMLPathCalcChain, f.CalcChain)
 	}
 	return f.CalcChain, nil
 }
@@ -35,7 +36,9 @@ func (f *File) calcChainReader() (*xlsxCalcChain, error) {
 func (f *File) calcChainWriter() {
 	if f.CalcChain != nil && f.CalcChain.C != nil {
 		output, _ := xml.Marshal(f.CalcChain)
-		f.saveFileList(defaultXMLPathCalcChain, output)
+		if f.partModified(defaultXMLPathCalcChain, output) {
+			f.saveFileList(defaultXMLPathCalcChain, output)
+		}
 	}
 }
 
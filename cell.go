@@ -38,6 +38,20 @@ const (
 	CellTypeSharedString
 )
 
+// StringStorage is the type of the storage policy for the string cell values.
+type StringStorage byte
+
+// String storage policy enumeration. The StringStorageDefault stores the
+// strings in the shared string table in normal mode, and stores the strings
+// as inline strings in stream mode. The StringStorageInline always stores the
+// strings as inline strings, and the StringStorageShared always stores the
+// strings in the shared string table.
+const (
+	StringStorageDefault StringStorage = iota
+	StringStorageInline
+	StringStorageShared
+)
+
 const (
 	// STCellFormulaTypeArray defined the formula is an array formula.
 	STCellFormulaTypeArray = "array"
@@ -428,25 +442,41 @@ func (f *File) SetCellStr(sheet, cell, value string) error {
 		return err
 	}
 	c.S = ws.prepareCellStyle(col, row, c.S)
-	if c.T, c.V, err = f.setCellString(value); err != nil {
+	if err = f.setCellString(c, value); err != nil {
 		return err
 	}
-	c.IS = nil
 	return f.removeFormula(c, ws, sheet)
 }
 
-// setCellString provides a function to set string type to shared string table.
-func (f *File) setCellString(value string) (t, v string, err error) {
+// setCellString provides a function to set string type value of the cell in
+// the shared string table or as an inline string by the string storage
+// policy.
+func (f *File) setCellString(c *xlsxC, value string) error {
 	if utf8.RuneCountInString(value) > TotalCellChars {
 		value = string([]rune(value)[:TotalCellChars])
 	}
-	t = "s"
-	var si int
-	if si, err = f.setSharedString(value); err != nil {
-		return
+	if policy, _ := f.getStringStorage(); policy != StringStorageInline {
+		si, ok, err := f.setSharedString(value)
+		if err != nil {
+			return err
+		}
+		if ok {
+			c.T, c.V, c.IS = "s", strconv.Itoa(si), nil
+			return nil
+		}
 	}
-	v = strconv.Itoa(si)
-	return
+	c.T, c.V, c.IS = "inlineStr", "", &xlsxSI{T: &xlsxT{}}
+	c.IS.T.Val, c.IS.T.Space = trimCellValue(value, false)
+	return nil
+}
+
+// getStringStorage returns the string storage policy and the maximum number
+// of the unique entries in the shared string table.
+func (f *File) getStringStorage() (StringStorage, int) {
+	if f.options == nil {
+		return StringStorageDefault, 0
+	}
+	return f.options.StringStorage, f.options.MaxSharedStrings
 }
 
 // sharedStringsLoader load shared string table from system temporary file to
@@ -473,29 +503,35 @@ func (f *File) sharedStringsLoader() (err error) {
 	return
 }
 
-// setSharedString provides a function to add string to the share string table.
-func (f *File) setSharedString(val string) (int, error) {
+// setSharedString provides a function to add string to the share string table,
+// and returns the index of the string in the shared string table. It returns
+// false if the string doesn't exist in the shared string table, and the
+// table has reached the maximum number of the unique entries.
+func (f *File) setSharedString(val string) (int, bool, error) {
 	if err := f.sharedStringsLoader(); err != nil {
-		return 0, err
+		return 0, false, err
 	}
 	sst, err := f.sharedStringsReader()
 	if err != nil {
-		return 0, err
+		return 0, false, err
 	}
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	if i, ok := f.sharedStringsMap[val]; ok {
-		return i, nil
+		return i, true, nil
 	}
 	sst.mu.Lock()
 	defer sst.mu.Unlock()
+	if _, limit := f.getStringStorage(); limit > 0 && len(sst.SI) >= limit {
+		return 0, false, nil
+	}
 	t := xlsxT{Val: val}
 	val, t.Space = trimCellValue(val, false)
 	sst.SI = append(sst.SI, xlsxSI{T: &t})
 	sst.Count = len(sst.SI)
 	sst.UniqueCount = sst.Count
 	f.sharedStringsMap[val] = sst.UniqueCount - 1
-	return sst.UniqueCount - 1, nil
+	return sst.UniqueCount - 1, true, nil
 }
 
 // trimCellValue provides a function to set string type to cell.
@@ -1016,16 +1052,30 @@ func (f *File) SetCellHyperLink(sheet, cell, link, linkType string, opts ...Hype
 	if len(ws.Hyperlinks.Hyperlink) > TotalSheetHyperlinks {
 		return ErrTotalSheetHyperlinks
 	}
+	if linkData, err = f.prepareHyperlink(sheet, cell, link, linkType, linkData.RID, opts...); err != nil {
+		return err
+	}
+	if idx == -1 {
+		ws.Hyperlinks.Hyperlink = append(ws.Hyperlinks.Hyperlink, linkData)
+		return err
+	}
+	ws.Hyperlinks.Hyperlink[idx] = linkData
+	return err
+}
 
+// prepareHyperlink provides a function to create the hyperlink of the cell by
+// given worksheet name, cell reference, link URL address, link type and the
+// relationship ID of the existing hyperlink.
+func (f *File) prepareHyperlink(sheet, cell, link, linkType, rID string, opts ...HyperlinkOpts) (xlsxHyperlink, error) {
+	var linkData xlsxHyperlink
 	switch linkType {
 	case "External":
 		sheetPath, _ := f.getSheetXMLPath(sheet)
 		sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(sheetPath, "xl/worksheets/") + ".rels"
-		rID := f.setRels(linkData.RID, sheetRels, SourceRelationshipHyperLink, link, linkType)
 		linkData = xlsxHyperlink{
 			Ref: cell,
+			RID: "rId" + strconv.Itoa(f.setRels(rID, sheetRels, SourceRelationshipHyperLink, link, linkType)),
 		}
-		linkData.RID = "rId" + strconv.Itoa(rID)
 		f.addSheetNameSpace(sheet, SourceRelationship)
 	case "Location":
 		linkData = xlsxHyperlink{
@@ -1033,7 +1083,7 @@ func (f *File) SetCellHyperLink(sheet, cell, link, linkType string, opts ...Hype
 			Location: link,
 		}
 	default:
-		return newInvalidLinkTypeError(linkType)
+		return linkData, newInvalidLinkTypeError(linkType)
 	}
 
 	for _, o := range opts {
@@ -1044,12 +1094,7 @@ func (f *File) SetCellHyperLink(sheet, cell, link, linkType string, opts ...Hype
 			linkData.Tooltip = *o.Tooltip
 		}
 	}
-	if idx == -1 {
-		ws.Hyperlinks.Hyperlink = append(ws.Hyperlinks.Hyperlink, linkData)
-		return err
-	}
-	ws.Hyperlinks.Hyperlink[idx] = linkData
-	return err
+	return linkData, nil
 }
 
 // getCellRichText returns rich text of cell by given string item.
@@ -1390,6 +1435,228 @@ func (f *File) setSheetCells(sheet, cell string, slice interface{}, dir adjustDi
 	return err
 }
 
+// RangeOptions directly maps the settings of the range values.
+//
+// Styles specifies the style ID matrix of the cells in row-major order, the
+// style of the cell will be set if the style ID at the corresponding position
+// is greater than zero, the style of the cell will be kept otherwise.
+type RangeOptions struct {
+	Styles [][]int
+}
+
+// SetRangeValues provides a function to set the values of the cells in
+// a range by given worksheet name, top-left cell reference and a
+// two-dimensional slice of values in row-major order. The supported data
+// types of the values are the same as the SetCellValue function, and the
+// default date and time number formats will be set for the time.Time and
+// time.Duration values on the cells without style. The optional style ID
+// matrix can be specified for setting the styles of the cells. This function
+// prepares the worksheet rows once for the whole range, so it's much faster
+// than setting the values cell by cell, but the merged cells will not be
+// resolved to the top-left cell of the merged range. For example, set a
+// header row in bold and a data row in the range B2:D3 on Sheet1:
+//
+//	style, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.SetRangeValues("Sheet1", "B2", [][]interface{}{
+//	    {"Name", "Amount", "Date"},
+//	    {"Apple", 12.5, time.Now()},
+//	}, excelize.RangeOptions{Styles: [][]int{{style, style, style}}})
+func (f *File) SetRangeValues(sheet, cell string, values [][]interface{}, opts ...RangeOptions) error {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+	var options RangeOptions
+	for _, opt := range opts {
+		options = opt
+	}
+	rows, cols := len(values), 0
+	if len(options.Styles) > rows {
+		rows = len(options.Styles)
+	}
+	for _, vals := range values {
+		if len(vals) > cols {
+			cols = len(vals)
+		}
+	}
+	for _, styles := range options.Styles {
+		if len(styles) > cols {
+			cols = len(styles)
+		}
+	}
+	f.mu.Lock()
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	s, err := f.stylesReader()
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	ct, err := f.newCellTyper()
+	f.mu.Unlock()
+	if err != nil || rows == 0 || cols == 0 {
+		return err
+	}
+	if _, err = CoordinatesToCellName(col+cols-1, row+rows-1); err != nil {
+		return err
+	}
+	for _, styles := range options.Styles {
+		for _, styleID := range styles {
+			if styleID < 0 || s.CellXfs == nil || len(s.CellXfs.Xf) <= styleID {
+				return newInvalidStyleID(styleID)
+			}
+		}
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.prepareSheetXML(col+cols-1, row+rows-1)
+	ws.makeContiguousColumns(row, row+rows-1, col+cols-1)
+	for i, styles := range options.Styles {
+		for j, styleID := range styles {
+			if styleID > 0 {
+				ws.SheetData.Row[row+i-1].C[col+j-1].S = styleID
+			}
+		}
+	}
+	timeStyles := make(map[int]int)
+	for i, vals := range values {
+		for j, val := range vals {
+			c := &ws.SheetData.Row[row+i-1].C[col+j-1]
+			c.S = ws.prepareCellStyle(col+j, row+i, c.S)
+			numFmtID, err := f.setCellValue(c, val, ct.date1904)
+			if err != nil {
+				return err
+			}
+			if err = f.removeFormula(c, ws, sheet); err != nil {
+				return err
+			}
+			if numFmtID == 0 || c.S != 0 {
+				continue
+			}
+			if _, ok := timeStyles[numFmtID]; !ok {
+				if timeStyles[numFmtID], err = f.NewStyle(&Style{NumFmt: numFmtID}); err != nil {
+					return err
+				}
+			}
+			c.S = timeStyles[numFmtID]
+		}
+	}
+	return err
+}
+
+// setCellValue provides a function to set the value of the cell by given
+// value with the data type supported by the SetCellValue function, and
+// returns the number format ID of the default style for the date and time
+// values.
+func (f *File) setCellValue(c *xlsxC, value interface{}, date1904 bool) (int, error) {
+	c.IS = nil
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return 0, setCellIntFunc(c, v)
+	case float32:
+		c.T, c.V = setCellFloat(float64(v), -1, 32)
+	case float64:
+		c.T, c.V = setCellFloat(v, -1, 64)
+	case string:
+		return 0, f.setCellString(c, v)
+	case []byte:
+		return 0, f.setCellString(c, string(v))
+	case time.Duration:
+		_, d := setCellDuration(v)
+		c.setCellDefault(d)
+		return 21, nil
+	case time.Time:
+		if isNum, err := c.setCellTime(v, date1904); isNum || err != nil {
+			return 22, err
+		}
+	case bool:
+		c.T, c.V = setCellBool(v)
+	case nil:
+		c.setCellDefault("")
+	default:
+		return 0, f.setCellString(c, fmt.Sprint(value))
+	}
+	return 0, nil
+}
+
+// GetRangeValues provides a function to get the typed values of the cells in
+// a range by given worksheet name and range reference. The values are
+// returned in row-major order from the top-left cell of the range, the
+// numbers will be returned as float64, the numbers in the date or time
+// number formats will be returned as time.Time, the booleans will be
+// returned as bool, the strings and errors will be returned as string, and
+// the blank cells will be returned as nil. The trailing blank cells of each
+// row and the trailing blank rows will be skipped. For example, get the
+// values of the range B2:D3 on Sheet1:
+//
+//	values, err := f.GetRangeValues("Sheet1", "B2:D3")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, row := range values {
+//	    for _, value := range row {
+//	        fmt.Printf("%v\t", value)
+//	    }
+//	    fmt.Println()
+//	}
+func (f *File) GetRangeValues(sheet, rangeRef string) ([][]interface{}, error) {
+	coordinates, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return nil, err
+	}
+	_ = sortCoordinates(coordinates)
+	f.mu.Lock()
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		f.mu.Unlock()
+		return nil, err
+	}
+	ct, err := f.newCellTyper()
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	sst, err := f.sharedStringsReader()
+	if err != nil {
+		return nil, err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	var results [][]interface{}
+	for row := coordinates[1]; row <= coordinates[3] && row <= len(ws.SheetData.Row); row++ {
+		var values []interface{}
+		cells := ws.SheetData.Row[row-1].C
+		for col := coordinates[0]; col <= coordinates[2] && col <= len(cells); col++ {
+			c := &cells[col-1]
+			val, err := c.getValueFrom(f, sst, true)
+			if err != nil {
+				return nil, err
+			}
+			value, _, err := ct.typedValue(&xlsxC{S: c.S, T: c.T, V: val})
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		for len(values) > 0 && values[len(values)-1] == nil {
+			values = values[:len(values)-1]
+		}
+		results = append(results, values)
+	}
+	for len(results) > 0 && len(results[len(results)-1]) == 0 {
+		results = results[:len(results)-1]
+	}
+	return results, err
+}
+
 // getCellInfo does common preparation for all set cell value functions.
 func (ws *xlsxWorksheet) prepareCell(cell string) (*xlsxC, int, int, error) {
 	var err error
@@ -1662,22 +1929,29 @@ func getSharedFormula(ws *xlsxWorksheet, si int, cell string) string {
 		for column := 0; column < len(r.C); column++ {
 			c := &r.C[column]
 			if c.F != nil && c.F.Ref != "" && c.F.T == STCellFormulaTypeShared && c.F.Si != nil && *c.F.Si == si {
-				col, row, _ := CellNameToCoordinates(cell)
-				sharedCol, sharedRow, _ := CellNameToCoordinates(c.R)
-				dCol := col - sharedCol
-				dRow := row - sharedRow
-				orig := []byte(c.F.Content)
-				res, start := parseSharedFormula(dCol, dRow, orig)
-				if start < len(orig) {
-					res += string(orig[start:])
-				}
-				return res
+				return shiftSharedFormula(c.R, c.F.Content, cell)
 			}
 		}
 	}
 	return ""
 }
 
+// shiftSharedFormula returns the formula of the cell by given the master cell
+// reference and formula of the shared formula, and the cell reference which
+// shares the formula.
+func shiftSharedFormula(masterCell, formula, cell string) string {
+	col, row, _ := CellNameToCoordinates(cell)
+	sharedCol, sharedRow, _ := CellNameToCoordinates(masterCell)
+	dCol := col - sharedCol
+	dRow := row - sharedRow
+	orig := []byte(formula)
+	res, start := parseSharedFormula(dCol, dRow, orig)
+	if start < len(orig) {
+		res += string(orig[start:])
+	}
+	return res
+}
+
 // shiftCell returns the cell shifted according to dCol and dRow taking into
 // consideration absolute references with dollar sign ($)
 func shiftCell(cellID string, dCol, dRow int) string {
@@ -1039,6 +1039,127 @@ func TestGetCustomNumFmtCode(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestStringStorage(t *testing.T) {
+	// Test set string cell values as inline strings
+	f := NewFile(Options{StringStorage: StringStorageInline})
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", " Hello <&>"))
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	c := ws.(*xlsxWorksheet).SheetData.Row[0].C[0]
+	assert.Equal(t, "inlineStr", c.T)
+	assert.Equal(t, " Hello <&>", c.IS.T.Val)
+	assert.Equal(t, "preserve", c.IS.T.Space.Value)
+	// Test overwrite inline string cell with number value
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	assert.Nil(t, ws.(*xlsxWorksheet).SheetData.Row[0].C[0].IS)
+	assert.NoError(t, f.SetCellStr("Sheet1", "A1", " Hello <&>"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestStringStorage.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err := OpenFile(filepath.Join("test", "TestStringStorage.xlsx"))
+	assert.NoError(t, err)
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, " Hello <&>", val)
+	assert.NoError(t, f.Close())
+
+	// Test set string cell values with the maximum number of the shared strings
+	f = NewFile(Options{MaxSharedStrings: 2})
+	for i, val := range []string{"A", "B", "A", "C", "B", "D"} {
+		assert.NoError(t, f.SetCellStr("Sheet1", fmt.Sprintf("A%d", i+1), val))
+	}
+	sst, err := f.sharedStringsReader()
+	assert.NoError(t, err)
+	assert.Len(t, sst.SI, 2)
+	ws, ok = f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	for i, expected := range []string{"s", "s", "s", "inlineStr", "s", "inlineStr"} {
+		assert.Equal(t, expected, ws.(*xlsxWorksheet).SheetData.Row[i].C[0].T)
+	}
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"A"}, {"B"}, {"A"}, {"C"}, {"B"}, {"D"}}, rows)
+	assert.NoError(t, f.Close())
+}
+
+func TestRangeValues(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C3", "1+1"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "E3", "Clear"))
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.NoError(t, f.SetRangeValues("Sheet1", "B2", [][]interface{}{
+		{"Name", "Amount", "Active"},
+		{"Apple", 12.5, true, nil},
+		{[]byte("Banana"), int8(-3), false, date, time.Duration(432e11)},
+		{uint16(7), float32(0.5), nil, complex64(5 + 10i)},
+	}, RangeOptions{Styles: [][]int{{style, style, style}, nil, {0, style}}}))
+	for cell, expected := range map[string]int{"B2": style, "D2": style, "B3": 0, "C4": style} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, styleID, cell)
+	}
+	// Test the default date and time styles are set for the cells without style
+	for _, cell := range []string{"E4", "F4"} {
+		styleID, err := f.GetCellStyle("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.NotZero(t, styleID, cell)
+	}
+	formula, err := f.GetCellFormula("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Empty(t, formula)
+
+	values, err := f.GetRangeValues("Sheet1", "F5:A1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]interface{}{
+		nil,
+		{nil, "Name", "Amount", "Active"},
+		{nil, "Apple", 12.5, true},
+		{nil, "Banana", -3.0, false, date, time.Date(1899, 12, 30, 12, 0, 0, 0, time.UTC)},
+		{nil, 7.0, 0.5, nil, "(5+10i)"},
+	}, values)
+	values, err = f.GetRangeValues("Sheet1", "C3:D10")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{12.5, true}, {-3.0, false}, {0.5}}, values)
+	values, err = f.GetRangeValues("Sheet1", "H1:H10")
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+
+	// Test set range values with empty values
+	assert.NoError(t, f.SetRangeValues("Sheet1", "H1", nil))
+	// Test set range values with invalid options
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")),
+		f.SetRangeValues("Sheet1", "A", [][]interface{}{{1}}))
+	assert.EqualError(t, f.SetRangeValues("SheetN", "A1", [][]interface{}{{1}}), "sheet SheetN does not exist")
+	assert.Equal(t, ErrMaxRows, f.SetRangeValues("Sheet1", fmt.Sprintf("A%d", TotalRows), [][]interface{}{{1}, {2}}))
+	assert.Equal(t, newInvalidStyleID(100), f.SetRangeValues("Sheet1", "A1", [][]interface{}{{1}}, RangeOptions{Styles: [][]int{{100}}}))
+	// Test get range values with invalid options
+	_, err = f.GetRangeValues("Sheet1", "A1")
+	assert.Equal(t, ErrParameterInvalid, err)
+	_, err = f.GetRangeValues("SheetN", "A1:B2")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+
+	// Test range values with unsupported charset workbook
+	f = NewFile()
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetRangeValues("Sheet1", "A1", [][]interface{}{{1}}), "XML syntax error on line 1: invalid UTF-8")
+	f.WorkBook = nil
+	_, err = f.GetRangeValues("Sheet1", "A1:B2")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+	// Test range values with unsupported charset shared strings table
+	f = NewFile()
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.SetRangeValues("Sheet1", "A1", [][]interface{}{{"A"}}), "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.GetRangeValues("Sheet1", "A1:B2")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
 func TestSharedStringsError(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"), Options{UnzipXMLSizeLimit: 128})
 	assert.NoError(t, err)
@@ -504,6 +504,13 @@ func (f *File) SetColWidth(sheet, startCol, endCol string, width float64) error
 	f.mu.Unlock()
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
+	ws.setColWidth(minVal, maxVal, width)
+	return err
+}
+
+// setColWidth provides a function to set the width of the columns by given
+// range of column numbers and width.
+func (ws *xlsxWorksheet) setColWidth(minVal, maxVal int, width float64) {
 	col := xlsxCol{
 		Min:         minVal,
 		Max:         maxVal,
@@ -514,7 +521,7 @@ func (f *File) SetColWidth(sheet, startCol, endCol string, width float64) error
 		cols := xlsxCols{}
 		cols.Col = append(cols.Col, col)
 		ws.Cols = &cols
-		return err
+		return
 	}
 	ws.Cols.Col = flatCols(col, ws.Cols.Col, func(fc, c xlsxCol) xlsxCol {
 		fc.BestFit = c.BestFit
@@ -525,7 +532,6 @@ func (f *File) SetColWidth(sheet, startCol, endCol string, width float64) error
 		fc.Style = c.Style
 		return fc
 	})
-	return err
 }
 
 // flatCols provides a method for the column's operation functions to flatten
@@ -746,6 +752,7 @@ func (f *File) InsertCols(sheet, col string, n int) error {
 	if n < 1 || n > MaxColumns {
 		return ErrColumnNumber
 	}
+	defer f.pinWorksheets()()
 	return f.adjustHelper(sheet, columns, num, n)
 }
 
@@ -764,6 +771,7 @@ func (f *File) RemoveCol(sheet, col string) error {
 		return err
 	}
 
+	defer f.pinWorksheets()()
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
@@ -0,0 +1,354 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvNumberPattern defined the pattern of the numeric field in the CSV.
+var csvNumberPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// defaultCSVDateLayouts defined the default date and time layouts for the
+// type inference on import CSV.
+var defaultCSVDateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+	"2006/01/02",
+	"2006/01/02 15:04:05",
+}
+
+// CSVOptions directly maps the settings of the CSV import and export.
+//
+// Delimiter specifies the field delimiter, the default value is comma (,).
+//
+// QuoteAll specifies if quote all fields on export CSV, by default only the
+// fields which contain the delimiter, quote, carriage return or line feed
+// will be quoted.
+//
+// LazyQuotes specifies if a quote may appear in an unquoted field and a
+// non-doubled quote may appear in a quoted field on import CSV.
+//
+// UseCRLF specifies if use \r\n as the line terminator on export CSV.
+//
+// Charset specifies the character encoding of the CSV, such as "gbk" or
+// "windows-1252", the default value is UTF-8. The import decodes the CSV by
+// the codepage transcoder function which set by the CharsetTranscoder
+// function, and the export encodes the CSV by the codepage encoder function
+// which set by the CharsetEncoder function.
+//
+// InferTypes specifies if convert the numeric, boolean and date fields to the
+// corresponding cell value types on import CSV, all fields will be imported
+// as strings by default. The numeric fields with leading zeros or more than
+// 15 significant digits will be kept as strings.
+//
+// DateLayouts specifies the Go time layouts for the date type inference on
+// import CSV, such as "2006-01-02" and "2006-01-02 15:04:05" by default.
+//
+// RawCellValue specifies if export the raw cell value instead of the
+// formatted value with the number format of the cell applied.
+type CSVOptions struct {
+	Delimiter    rune
+	QuoteAll     bool
+	LazyQuotes   bool
+	UseCRLF      bool
+	Charset      string
+	InferTypes   bool
+	DateLayouts  []string
+	RawCellValue bool
+}
+
+// getCSVOptions provides a function to parse the CSV options with default
+// value.
+func getCSVOptions(opts ...CSVOptions) CSVOptions {
+	var options CSVOptions
+	for _, opt := range opts {
+		options = opt
+	}
+	if options.Delimiter == 0 {
+		options.Delimiter = ','
+	}
+	if len(options.DateLayouts) == 0 {
+		options.DateLayouts = defaultCSVDateLayouts
+	}
+	return options
+}
+
+// isUTF8Charset returns if the given charset label is UTF-8.
+func isUTF8Charset(label string) bool {
+	label = strings.ToLower(strings.TrimSpace(label))
+	return label == "" || label == "utf-8" || label == "utf8"
+}
+
+// ImportCSV provides a function to import the CSV data from io.Reader into the
+// worksheet by given worksheet name and CSV options. The worksheet will be
+// created if it doesn't exist, and the data will be written by the stream
+// writer, so the existing data of the worksheet will be overwritten, and the
+// same limitations of the stream writer apply to the worksheet. For example,
+// import a semicolon-separated CSV file with type inference into a worksheet
+// named 'Sheet1':
+//
+//	file, err := os.Open("data.csv")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	if err := f.ImportCSV("Sheet1", file, excelize.CSVOptions{
+//	    Delimiter:  ';',
+//	    InferTypes: true,
+//	}); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) ImportCSV(sheet string, r io.Reader, opts ...CSVOptions) error {
+	options := getCSVOptions(opts...)
+	if err := checkSheetName(sheet); err != nil {
+		return err
+	}
+	if !isUTF8Charset(options.Charset) {
+		rdr, err := f.CharsetReader(options.Charset, r)
+		if err != nil {
+			return err
+		}
+		r = rdr
+	}
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && string(bom) == "\xef\xbb\xbf" {
+		_, _ = br.Discard(3)
+	}
+	cr := csv.NewReader(br)
+	cr.Comma, cr.LazyQuotes, cr.FieldsPerRecord, cr.ReuseRecord = options.Delimiter, options.LazyQuotes, -1, true
+	if f.getSheetID(sheet) == -1 {
+		if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+	}
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+	if err = importCSVRows(sw, cr, options); err == nil {
+		err = sw.Flush()
+	}
+	if err != nil {
+		sw.discard()
+	}
+	return err
+}
+
+// importCSVRows provides a function to read the CSV records and write them
+// into the worksheet by given stream writer, CSV reader and CSV options. The
+// empty lines which skipped by the CSV reader will be kept as empty rows.
+func importCSVRows(sw *StreamWriter, cr *csv.Reader, options CSVOptions) error {
+	inferrer := csvTypeInferrer{f: sw.file, layouts: options.DateLayouts}
+	for row, lastLine := 1, 0; ; row++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := cr.FieldPos(0)
+		row += line - lastLine - 1
+		lastLine, _ = cr.FieldPos(len(record) - 1)
+		lastLine += strings.Count(record[len(record)-1], "\n")
+		values := make([]interface{}, len(record))
+		for i, field := range record {
+			if values[i] = field; options.InferTypes {
+				if values[i], err = inferrer.infer(field); err != nil {
+					return err
+				}
+			}
+		}
+		cell, _ := CoordinatesToCellName(1, row)
+		if err = sw.SetRow(cell, values); err != nil {
+			return err
+		}
+	}
+}
+
+// csvTypeInferrer defined the type inference of the CSV field value.
+type csvTypeInferrer struct {
+	f                    *File
+	layouts              []string
+	dateStyle, timeStyle int
+}
+
+// infer provides a function to convert the CSV field value to number,
+// boolean, date or string cell value.
+func (i *csvTypeInferrer) infer(field string) (interface{}, error) {
+	if field == "" {
+		return nil, nil
+	}
+	if csvNumberPattern.MatchString(field) && !isCSVTextNumber(field) {
+		if n, err := strconv.ParseFloat(field, 64); err == nil {
+			return n, nil
+		}
+	}
+	switch strings.ToUpper(field) {
+	case "TRUE":
+		return true, nil
+	case "FALSE":
+		return false, nil
+	}
+	return i.inferDate(field)
+}
+
+// isCSVTextNumber returns if the numeric field should be kept as string to
+// avoid losing the data, such as the field with leading zeros like ZIP codes,
+// or the field with more than 15 significant digits like IDs.
+func isCSVTextNumber(field string) bool {
+	mantissa := strings.TrimLeft(field, "+-")
+	if idx := strings.IndexAny(mantissa, "eE"); idx != -1 {
+		mantissa = mantissa[:idx]
+	}
+	if len(mantissa) > 1 && mantissa[0] == '0' && mantissa[1] != '.' {
+		return true
+	}
+	digits := strings.TrimLeft(strings.Replace(mantissa, ".", "", 1), "0")
+	return len(digits) > 15
+}
+
+// inferDate provides a function to convert the field value which matches
+// the date layouts to the date cell value with the date or date time number
+// format, or returns the field value as it is.
+func (i *csvTypeInferrer) inferDate(field string) (interface{}, error) {
+	for _, layout := range i.layouts {
+		t, err := time.Parse(layout, field)
+		if err != nil {
+			continue
+		}
+		var style *int
+		numFmt := 22
+		if style = &i.timeStyle; t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+			style, numFmt = &i.dateStyle, 14
+		}
+		if *style == 0 {
+			if *style, err = i.f.NewStyle(&Style{NumFmt: numFmt}); err != nil {
+				return nil, err
+			}
+		}
+		return Cell{StyleID: *style, Value: t}, nil
+	}
+	return field, nil
+}
+
+// ExportCSV provides a function to export the worksheet to io.Writer as CSV
+// by given worksheet name and CSV options. The rows are read by the rows
+// iterator, the blank rows between the rows will be exported as empty
+// records, and the continually blank rows in the tail of the worksheet will
+// be skipped. For example, export the raw cell values of the worksheet named
+// 'Sheet1' as tab-separated values:
+//
+//	file, err := os.Create("data.tsv")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	if err := f.ExportCSV("Sheet1", file, excelize.CSVOptions{
+//	    Delimiter:    '\t',
+//	    RawCellValue: true,
+//	}); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) ExportCSV(sheet string, w io.Writer, opts ...CSVOptions) error {
+	options := getCSVOptions(opts...)
+	if options.Delimiter == '"' || options.Delimiter == '\r' || options.Delimiter == '\n' {
+		return ErrCSVDelimiter
+	}
+	var tw io.WriteCloser
+	if !isUTF8Charset(options.Charset) {
+		wtr, err := f.CharsetWriter(options.Charset, w)
+		if err != nil {
+			return err
+		}
+		tw, w = wtr, wtr
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	readOpts := *f.options
+	readOpts.RawCellValue = options.RawCellValue
+	bw := bufio.NewWriter(w)
+	emptyRows := 0
+	for rows.Next() {
+		row, err := rows.Columns(readOpts)
+		if err != nil {
+			_ = rows.Close()
+			return err
+		}
+		if len(row) == 0 {
+			emptyRows++
+			continue
+		}
+		for ; emptyRows > 0; emptyRows-- {
+			writeCSVLineTerminator(bw, options.UseCRLF)
+		}
+		writeCSVRecord(bw, row, options)
+	}
+	if err = rows.Close(); err != nil {
+		return err
+	}
+	if err = bw.Flush(); err != nil || tw == nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// writeCSVRecord provides a function to write a CSV record to the buffered
+// writer by given fields and CSV options.
+func writeCSVRecord(bw *bufio.Writer, fields []string, options CSVOptions) {
+	for i, field := range fields {
+		if i > 0 {
+			_, _ = bw.WriteRune(options.Delimiter)
+		}
+		if !options.QuoteAll && !csvFieldNeedsQuotes(field, options.Delimiter) {
+			_, _ = bw.WriteString(field)
+			continue
+		}
+		_ = bw.WriteByte('"')
+		_, _ = bw.WriteString(strings.ReplaceAll(field, `"`, `""`))
+		_ = bw.WriteByte('"')
+	}
+	writeCSVLineTerminator(bw, options.UseCRLF)
+}
+
+// writeCSVLineTerminator provides a function to write the CSV line
+// terminator to the buffered writer.
+func writeCSVLineTerminator(bw *bufio.Writer, useCRLF bool) {
+	if useCRLF {
+		_, _ = bw.WriteString("\r\n")
+		return
+	}
+	_ = bw.WriteByte('\n')
+}
+
+// csvFieldNeedsQuotes returns if the CSV field must be quoted.
+func csvFieldNeedsQuotes(field string, delimiter rune) bool {
+	if field == "" {
+		return false
+	}
+	if field == `\.` || field[0] == ' ' || field[0] == '\t' {
+		return true
+	}
+	return strings.ContainsRune(field, delimiter) || strings.ContainsAny(field, "\"\r\n")
+}
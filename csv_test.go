@@ -0,0 +1,170 @@
+package excelize
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/transform"
+)
+
+func TestImportCSV(t *testing.T) {
+	f := NewFile()
+	data := "\xef\xbb\xbfName;Amount;Active;Date\n\"Smith; John\";12.5;TRUE;2024-01-02\nDoe;007;false;2024-01-02 15:04:05\nRoe;-0.5e2;;\nPoe;12345678901234567;;\n;;;\n"
+	assert.NoError(t, f.ImportCSV("Sheet2", strings.NewReader(data), CSVOptions{Delimiter: ';', InferTypes: true}))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestImportCSV.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err := OpenFile(filepath.Join("test", "TestImportCSV.xlsx"))
+	assert.NoError(t, err)
+	rows, err := f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Name", "Amount", "Active", "Date"},
+		{"Smith; John", "12.5", "TRUE", "01-02-24"},
+		{"Doe", "007", "FALSE", "1/2/24 15:04"},
+		{"Roe", "-50"},
+		{"Poe", "12345678901234567"},
+	}, rows)
+	cellType, err := f.GetCellType("Sheet2", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, CellTypeUnset, cellType)
+	// Test the numeric fields with leading zeros or over 15 significant
+	// digits are kept as strings
+	for _, cell := range []string{"B3", "B5"} {
+		cellType, err = f.GetCellType("Sheet2", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, CellTypeInlineString, cellType, cell)
+	}
+	cellType, err = f.GetCellType("Sheet2", "C2")
+	assert.NoError(t, err)
+	assert.Equal(t, CellTypeBool, cellType)
+	assert.NoError(t, f.Close())
+
+	// Test import CSV without type inference
+	f = NewFile()
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader("007,TRUE\n")))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestImportCSV.xlsx")))
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestImportCSV.xlsx"))
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"007", "TRUE"}}, rows)
+	assert.NoError(t, f.Close())
+
+	// Test import CSV into the worksheet which contains data
+	f = NewFile()
+	assert.NoError(t, f.SetSheetCol("Sheet1", "A1", &[]interface{}{"A", "B", "C"}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "D5", "D"))
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader("1,2\n3\n")))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"1", "2"}, {"3"}}, rows)
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader("4\n")))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"4"}}, rows)
+	// Test import CSV with empty lines and multi-line fields
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader("\nA\n\"B\r\nC\",D\n\n\nE\n")))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{nil, {"A"}, {"B\nC", "D"}, nil, nil, {"E"}}, rows)
+	assert.NoError(t, f.Close())
+
+	// Test import CSV with charset
+	f = NewFile()
+	encoded, err := charmap.Windows1252.NewEncoder().String("Café\n")
+	assert.NoError(t, err)
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader(encoded), CSVOptions{Charset: "windows-1252"}))
+	assert.NoError(t, f.Close())
+	// Test import CSV with unsupported charset
+	f = NewFile()
+	assert.EqualError(t, f.ImportCSV("Sheet1", strings.NewReader("A"), CSVOptions{Charset: "unknown"}), "unsupported charset: \"unknown\"")
+	// Test import CSV with invalid sheet name
+	assert.Equal(t, ErrSheetNameInvalid, f.ImportCSV("Sheet:1", strings.NewReader("A")))
+	// Test import CSV with invalid CSV data
+	assert.Error(t, f.ImportCSV("Sheet1", strings.NewReader("A\n\"B\"C")))
+	// Test the stream writer has been discarded on error
+	_, ok := f.streams.Load("xl/worksheets/sheet1.xml")
+	assert.False(t, ok)
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	f2, err := OpenReader(buf)
+	assert.NoError(t, err)
+	rows, err = f2.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, rows)
+	assert.NoError(t, f2.Close())
+	// Test import CSV with invalid delimiter
+	assert.Error(t, f.ImportCSV("Sheet1", strings.NewReader("A"), CSVOptions{Delimiter: '"'}))
+	// Test import CSV with unsupported charset transcoder
+	errCharset := errors.New("unsupported charset")
+	f.CharsetTranscoder(func(charset string, input io.Reader) (io.Reader, error) { return nil, errCharset })
+	assert.Equal(t, errCharset, f.ImportCSV("Sheet1", strings.NewReader("A"), CSVOptions{Charset: "gbk"}))
+	assert.NoError(t, f.Close())
+}
+
+func TestExportCSV(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Note", "Amount"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Smith, John", "say \"hi\"", 1234.5}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A4", &[]interface{}{"Café", nil, 0.25}))
+	style, err := f.NewStyle(&Style{NumFmt: 4})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "C2", "C4", style))
+
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportCSV("Sheet1", &buf))
+	assert.Equal(t, "Name,Note,Amount\n\"Smith, John\",\"say \"\"hi\"\"\",\"1,234.50\"\n\nCafé,,0.25\n", buf.String())
+	// Test export CSV with raw value, quote all fields, delimiter and CRLF
+	buf.Reset()
+	assert.NoError(t, f.ExportCSV("Sheet1", &buf, CSVOptions{Delimiter: '\t', QuoteAll: true, UseCRLF: true, RawCellValue: true}))
+	assert.Equal(t, "\"Name\"\t\"Note\"\t\"Amount\"\r\n\"Smith, John\"\t\"say \"\"hi\"\"\"\t\"1234.5\"\r\n\r\n\"Café\"\t\"\"\t\"0.25\"\r\n", buf.String())
+	// Test export CSV with charset
+	buf.Reset()
+	assert.NoError(t, f.ExportCSV("Sheet1", &buf, CSVOptions{Charset: "windows-1252", RawCellValue: true}))
+	decoded, err := charmap.Windows1252.NewDecoder().String(buf.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "Name,Note,Amount\n\"Smith, John\",\"say \"\"hi\"\"\",1234.5\n\nCafé,,0.25\n", decoded)
+	// Test round trip export and import CSV
+	assert.NoError(t, f.ImportCSV("Sheet2", bytes.NewReader(buf.Bytes()), CSVOptions{Charset: "windows-1252", InferTypes: true}))
+	expected, err := f.GetRows("Sheet1", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	rows, err := f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, rows)
+	for cell, expectedType := range map[string]CellType{
+		"A1": CellTypeInlineString, "B2": CellTypeInlineString, "C2": CellTypeUnset,
+		"A3": CellTypeUnset, "A4": CellTypeInlineString, "B4": CellTypeUnset, "C4": CellTypeUnset,
+	} {
+		cellType, err := f.GetCellType("Sheet2", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedType, cellType, cell)
+	}
+	// Test export CSV with unsupported charset
+	assert.EqualError(t, f.ExportCSV("Sheet1", &buf, CSVOptions{Charset: "unknown"}), "unsupported charset: \"unknown\"")
+	// Test export CSV with user defined codepage encoder
+	buf.Reset()
+	f.CharsetEncoder(func(charset string, output io.Writer) (io.WriteCloser, error) {
+		assert.Equal(t, "x-custom", charset)
+		return transform.NewWriter(output, charmap.Windows1252.NewEncoder()), nil
+	})
+	assert.NoError(t, f.ExportCSV("Sheet1", &buf, CSVOptions{Charset: "x-custom", RawCellValue: true}))
+	decoded, err = charmap.Windows1252.NewDecoder().String(buf.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "Name,Note,Amount\n\"Smith, John\",\"say \"\"hi\"\"\",1234.5\n\nCafé,,0.25\n", decoded)
+	errCharset := errors.New("unsupported charset")
+	f.CharsetEncoder(func(charset string, output io.Writer) (io.WriteCloser, error) { return nil, errCharset })
+	assert.Equal(t, errCharset, f.ExportCSV("Sheet1", &buf, CSVOptions{Charset: "gbk"}))
+	// Test export CSV with invalid delimiter
+	assert.Equal(t, ErrCSVDelimiter, f.ExportCSV("Sheet1", &buf, CSVOptions{Delimiter: '"'}))
+	// Test export CSV on not exists worksheet
+	assert.EqualError(t, f.ExportCSV("SheetN", &buf), "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+}
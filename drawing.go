@@ -1337,6 +1337,7 @@ func (f *File) drawingParser(path string) (*xlsxWsDr, int, error) {
 					GraphicFrame: v.Content,
 				})
 			}
+			f.trackPart(path, &content)
 		}
 		f.Drawings.Store(path, &content)
 	}
@@ -37,6 +37,9 @@ var (
 	// ErrCoordinates defined the error message on invalid coordinates tuples
 	// length.
 	ErrCoordinates = errors.New("coordinates length must be 4")
+	// ErrCSVDelimiter defined the error message on receive the invalid CSV
+	// field delimiter.
+	ErrCSVDelimiter = errors.New("invalid CSV field delimiter")
 	// ErrCustomNumFmt defined the error message on receive the empty custom number format.
 	ErrCustomNumFmt = errors.New("custom number format can not be empty")
 	// ErrDataValidationFormulaLength defined the error message for receiving a
@@ -71,6 +74,13 @@ var (
 	// ErrInvalidFormula defined the error message on receive an invalid
 	// formula.
 	ErrInvalidFormula = errors.New("formula not valid")
+	// ErrJSONObject defined the error message on receive the JSON value which
+	// is not an object on import JSON.
+	ErrJSONObject = errors.New("JSON value must be an object or an array of objects")
+	// ErrMarshalRowsSource defined the error message on receiving the invalid
+	// source for marshaling rows, which should be a slice of the structs or
+	// the pointers to the structs.
+	ErrMarshalRowsSource = errors.New("marshal rows source must be a slice of structs")
 	// ErrMaxFilePathLength defined the error message on receive the file path
 	// length overflow.
 	ErrMaxFilePathLength = fmt.Errorf("file path length exceeds maximum limit %d characters", MaxFilePathLength)
@@ -129,18 +139,33 @@ var (
 	// ErrSparklineType defined the error message on receive the invalid
 	// sparkline Type parameters.
 	ErrSparklineType = errors.New("parameter 'Type' must be 'line', 'column' or 'win_loss'")
+	// ErrStreamOutput defined the error message on create stream writer which
+	// writes to the output directly, while another one has not been flushed or
+	// the output is different with the previous stream writers.
+	ErrStreamOutput = errors.New("the stream writers which write to the output directly must use the same output and be flushed one by one")
+	// ErrStreamSave defined the error message on save the workbook while the
+	// stream writers write the workbook to the output directly.
+	ErrStreamSave = errors.New("the workbook which the stream writers write to the output directly can't be saved, use the Close function instead")
 	// ErrStreamSetColWidth defined the error message on set column width in
 	// stream writing mode.
 	ErrStreamSetColWidth = errors.New("must call the SetColWidth function before the SetRow function")
 	// ErrStreamSetPanes defined the error message on set panes in stream
 	// writing mode.
 	ErrStreamSetPanes = errors.New("must call the SetPanes function before the SetRow function")
+	// ErrStreamTableHeader defined the error message on add table in stream
+	// writing mode, while the header row of the table has been written to the
+	// output.
+	ErrStreamTableHeader = errors.New("the header row of the table has been written to the output, must call the AddTable function before the header row is flushed")
 	// ErrTotalSheetHyperlinks defined the error message on hyperlinks count
 	// overflow.
 	ErrTotalSheetHyperlinks = errors.New("over maximum limit hyperlinks in a worksheet")
 	// ErrUnknownEncryptMechanism defined the error message on unsupported
 	// encryption mechanism.
 	ErrUnknownEncryptMechanism = errors.New("unknown encryption mechanism")
+	// ErrUnmarshalRowsTarget defined the error message on receiving the
+	// invalid target for unmarshaling rows, which should be a pointer to a
+	// slice of the structs or the pointers to the structs.
+	ErrUnmarshalRowsTarget = errors.New("unmarshal rows target must be a pointer to a slice of structs")
 	// ErrUnprotectSheet defined the error message on worksheet has set no
 	// protection.
 	ErrUnprotectSheet = errors.New("worksheet has set no protect")
@@ -264,6 +289,24 @@ func newInvalidStyleID(styleID int) error {
 	return fmt.Errorf("invalid style ID %d", styleID)
 }
 
+// newMarshalFieldError defined the error message on marshaling the struct
+// field with the given header name failed.
+func newMarshalFieldError(header string, err error) error {
+	return fmt.Errorf("cannot marshal field %q: %v", header, err)
+}
+
+// newNoExistChartError defined the error message on receiving the cell
+// reference which no chart anchored at.
+func newNoExistChartError(cell string) error {
+	return fmt.Errorf("chart at cell %s does not exist", cell)
+}
+
+// newNoExistFontError defined the error message on receiving the font family
+// name which TrueType font data not been specified.
+func newNoExistFontError(family string) error {
+	return fmt.Errorf("font data of font family %s does not exist", family)
+}
+
 // newNoExistTableError defined the error message on receiving the non existing
 // table name.
 func newNoExistTableError(name string) error {
@@ -294,18 +337,42 @@ func newStreamSetRowError(row int) error {
 	return fmt.Errorf("row %d has already been written", row)
 }
 
+// newTemplateRangeTypeError defined the error message on receiving the range
+// action of the template with the value which is not a slice or array.
+func newTemplateRangeTypeError(cell, typ string) error {
+	return fmt.Errorf("template range action in cell %s can't iterate over %s", cell, typ)
+}
+
+// newUnclosedTemplateRangeError defined the error message on receiving the
+// range action of the template without the corresponding end action.
+func newUnclosedTemplateRangeError(cell string) error {
+	return fmt.Errorf("template range action in cell %s is not closed", cell)
+}
+
 // newUnknownFilterTokenError defined the error message on receiving a unknown
 // filter operator token.
 func newUnknownFilterTokenError(token string) error {
 	return fmt.Errorf("unknown operator: %s", token)
 }
 
+// newUnmarshalCellError defined the error message on converting the cell value
+// to the struct field with the given header name failed.
+func newUnmarshalCellError(cell, header string, err error) error {
+	return fmt.Errorf("cannot unmarshal cell %s into field %q: %v", cell, header, err)
+}
+
 // newUnsupportedChartType defined the error message on receiving the chart
 // type are unsupported.
 func newUnsupportedChartType(chartType ChartType) error {
 	return fmt.Errorf("unsupported chart type %d", chartType)
 }
 
+// newUnsupportedFieldTypeError defined the error message on receiving the
+// struct field type which is unsupported for marshaling or unmarshaling rows.
+func newUnsupportedFieldTypeError(typ string) error {
+	return fmt.Errorf("unsupported field type %s", typ)
+}
+
 // newUnzipSizeLimitError defined the error message on unzip size exceeds the
 // limit.
 func newUnzipSizeLimitError(unzipSizeLimit int64) error {
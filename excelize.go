@@ -16,14 +16,17 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/xml"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/net/html/charset"
+	"golang.org/x/text/transform"
 )
 
 // File define a populated spreadsheet file struct.
@@ -32,15 +35,21 @@ type File struct {
 	checked          sync.Map
 	formulaChecked   bool
 	options          *Options
+	partHashes       sync.Map
 	sharedStringItem [][]uint
 	sharedStringsMap map[string]int
 	sharedStringTemp *os.File
 	sheetMap         map[string]string
-	streams          map[string]*StreamWriter
+	sheetPager       worksheetPager
+	sourceParts      sync.Map
+	streamMu         sync.Mutex
+	streamZip        *streamZip
+	streams          sync.Map
 	tempFiles        sync.Map
 	xmlAttr          sync.Map
 	CalcChain        *xlsxCalcChain
 	CharsetReader    charsetTranscoderFn
+	CharsetWriter    charsetEncoderFn
 	Comments         map[string]*xlsxComments
 	ContentTypes     *xlsxTypes
 	DecodeVMLDrawing map[string]*decodeVmlDrawing
@@ -63,6 +72,10 @@ type File struct {
 // the spreadsheet from non-UTF-8 encoding.
 type charsetTranscoderFn func(charset string, input io.Reader) (rdr io.Reader, err error)
 
+// charsetEncoderFn set user-defined codepage encoder function for write the
+// UTF-8 data to the output in non-UTF-8 encoding.
+type charsetEncoderFn func(charset string, output io.Writer) (wtr io.WriteCloser, err error)
+
 // Options define the options for opening and reading the spreadsheet.
 //
 // MaxCalcIterations specifies the maximum iterations for iterative
@@ -97,16 +110,75 @@ type charsetTranscoderFn func(charset string, input io.Reader) (rdr io.Reader, e
 //
 // CultureInfo specifies the country code for applying built-in language number
 // format code these effect by the system's local language settings.
+//
+// CalcTime specifies a fixed current date and time used by the volatile
+// formula functions NOW and TODAY, the system clock will be used if this value
+// is zero.
+//
+// CalcLocation specifies the time zone used by the volatile formula functions
+// NOW and TODAY, the time zone of the CalcTime or the local time zone will be
+// used if this value is nil.
+//
+// CalcRandSeed specifies the seed of the random number generator used by the
+// volatile formula functions RAND and RANDBETWEEN, a seed derived from the
+// system clock will be used if this value is zero. Each formula calculation
+// starts with a new generator, so the same seed produces the same sequence of
+// random numbers.
+//
+// StringStorage specifies the storage policy of the string cell values which
+// set by the normal mode functions such as 'SetCellStr' and 'SetCellValue',
+// and the stream writer. The strings will be stored in the shared string table
+// in normal mode and as inline strings in stream mode by default.
+//
+// MaxSharedStrings specifies the maximum number of the unique entries in the
+// shared string table, the strings will be deduplicated in the shared string
+// table until the limit is reached, and the new strings over the limit will be
+// stored as inline strings. The number of entries is unlimited if this value
+// is zero.
+//
+// WorksheetMemoryLimit specifies the memory budget in bytes of the decoded
+// worksheets, measured by the size of the worksheet XML parts when they were
+// decoded. The worksheets are decoded on first access, and the least recently
+// used worksheets will be unloaded from memory when the limit is exceeded. The
+// modified worksheets will be serialized to the temporary files on unloading,
+// and the XML parts of the worksheets which haven't been modified will be
+// moved to the temporary files as they are. The worksheets used by the
+// function which accesses multiple worksheets will be unloaded after the
+// function returns. The decoded worksheets will be kept in memory if this
+// value is zero.
+// Note that the worksheets should not be accessed concurrently when this limit
+// was set.
+//
+// IncrementalSave specifies if save the spreadsheet incrementally. The parts
+// which haven't been modified will be copied from the source spreadsheet
+// without recompression on saving, and the decoded parts such as worksheets,
+// styles, shared strings and drawings will be serialized only if they have
+// been modified. Note that the source spreadsheet will be kept in memory until
+// the file was closed when this option was enabled.
+//
+// StrictConformance specifies if save the spreadsheet in the Strict Open XML
+// Spreadsheet (ISO/IEC 29500 Strict) conformance, the Strict namespaces will
+// be used in the saved parts, and the workbook will be marked as Strict
+// conformance. The Strict and Transitional spreadsheets are both supported on
+// opening, and the Transitional conformance is used on saving by default.
 type Options struct {
-	MaxCalcIterations uint
-	Password          string
-	RawCellValue      bool
-	UnzipSizeLimit    int64
-	UnzipXMLSizeLimit int64
-	ShortDatePattern  string
-	LongDatePattern   string
-	LongTimePattern   string
-	CultureInfo       CultureName
+	MaxCalcIterations    uint
+	Password             string
+	RawCellValue         bool
+	UnzipSizeLimit       int64
+	UnzipXMLSizeLimit    int64
+	ShortDatePattern     string
+	LongDatePattern      string
+	LongTimePattern      string
+	CultureInfo          CultureName
+	CalcTime             time.Time
+	CalcLocation         *time.Location
+	CalcRandSeed         int64
+	StringStorage        StringStorage
+	MaxSharedStrings     int
+	WorksheetMemoryLimit int64
+	IncrementalSave      bool
+	StrictConformance    bool
 }
 
 // OpenFile take the name of a spreadsheet file and returns a populated
@@ -115,6 +187,29 @@ type Options struct {
 //
 //	f, err := excelize.OpenFile("Book1.xlsx", excelize.Options{Password: "password"})
 //
+// The binary workbook (XLSB) and the Excel 97-2003 workbook (XLS) can be
+// opened for reading, and be saved as the XLSX workbook by the SaveAs
+// function, for example:
+//
+//	f, err := excelize.OpenFile("Book1.xls")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := f.SaveAs("Book1.xlsx"); err != nil {
+//	    fmt.Println(err)
+//	}
+//
+// The OpenDocument Spreadsheet (ODS) can be opened in the same way, the cell
+// values, formulas, styles, merged cells, column widths and worksheets will be
+// mapped onto the workbook. Use the SaveAs function with the ".ods" file
+// extension to save the workbook as the OpenDocument Spreadsheet.
+//
+// The XML Spreadsheet 2003 (SpreadsheetML 2003) exported by the legacy
+// systems can be opened in the same way, the worksheets, cell values,
+// formulas, styles, named ranges, merged cells, hyperlinks and comments will
+// be mapped onto the workbook, which can be saved as the XLSX workbook.
+//
 // Close the file by Close function after opening the spreadsheet.
 func OpenFile(filename string, opts ...Options) (*File, error) {
 	file, err := os.Open(filepath.Clean(filename))
@@ -148,6 +243,7 @@ func newFile() *File {
 		VMLDrawing:       make(map[string]*vmlDrawing),
 		Relationships:    sync.Map{},
 		CharsetReader:    charset.NewReaderLabel,
+		CharsetWriter:    newCharsetWriter,
 	}
 }
 
@@ -184,26 +280,45 @@ func OpenReader(r io.Reader, opts ...Options) (*File, error) {
 	if err = f.checkOpenReaderOptions(); err != nil {
 		return nil, err
 	}
+	var (
+		file       map[string][]byte
+		sheetCount int
+	)
 	if bytes.Contains(b, oleIdentifier) {
-		if b, err = Decrypt(b, f.options); err != nil {
-			return nil, ErrWorkbookFileFormat
+		if file, sheetCount, err = f.readXls(b); err != nil {
+			return nil, err
 		}
-	}
-	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
-	if err != nil {
-		if len(f.options.Password) > 0 {
-			return nil, ErrWorkbookPassword
+		if file == nil {
+			if b, err = Decrypt(b, f.options); err != nil {
+				return nil, ErrWorkbookFileFormat
+			}
 		}
-		return nil, err
 	}
-	file, sheetCount, err := f.ReadZipReader(zr)
-	if err != nil {
-		return nil, err
+	if file == nil && !bytes.HasPrefix(b, []byte("PK")) && bytes.Contains(b, []byte(NameSpaceSpreadSheetML2003)) {
+		return openXML2003(b, *f.options)
+	}
+	if file == nil {
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			if len(f.options.Password) > 0 {
+				return nil, ErrWorkbookPassword
+			}
+			return nil, err
+		}
+		if file, sheetCount, err = f.ReadZipReader(zr); err != nil {
+			return nil, err
+		}
+		if string(file["mimetype"]) == ContentTypeODS {
+			return openODS(file, *f.options)
+		}
 	}
 	f.SheetCount = sheetCount
 	for k, v := range file {
 		f.Pkg.Store(k, v)
 	}
+	if err = f.readXlsb(); err != nil {
+		return f, err
+	}
 	if f.CalcChain, err = f.calcChainReader(); err != nil {
 		return f, err
 	}
@@ -231,6 +346,20 @@ func (f *File) getOptions(opts ...Options) *Options {
 // XLSX from non UTF-8 encoding.
 func (f *File) CharsetTranscoder(fn charsetTranscoderFn) *File { f.CharsetReader = fn; return f }
 
+// CharsetEncoder Set user defined codepage encoder function for export data
+// in non UTF-8 encoding, such as export CSV.
+func (f *File) CharsetEncoder(fn charsetEncoderFn) *File { f.CharsetWriter = fn; return f }
+
+// newCharsetWriter provides a function to create the writer which encodes the
+// UTF-8 data to the output in the character encoding by given charset label.
+func newCharsetWriter(label string, output io.Writer) (io.WriteCloser, error) {
+	enc, _ := charset.Lookup(label)
+	if enc == nil {
+		return nil, fmt.Errorf("unsupported charset: %q", label)
+	}
+	return transform.NewWriter(output, enc.NewEncoder()), nil
+}
+
 // Creates new XML decoder with charset reader.
 func (f *File) xmlNewDecoder(rdr io.Reader) (ret *xml.Decoder) {
 	ret = xml.NewDecoder(rdr)
@@ -269,6 +398,7 @@ func (f *File) workSheetReader(sheet string) (ws *xlsxWorksheet, err error) {
 	}
 	if worksheet, ok := f.Sheet.Load(name); ok && worksheet != nil {
 		ws = worksheet.(*xlsxWorksheet)
+		f.trackWorksheet(name, ws, -1)
 		return
 	}
 	for _, sheetType := range []string{"xl/chartsheets", "xl/dialogsheet", "xl/macrosheet"} {
@@ -278,15 +408,16 @@ func (f *File) workSheetReader(sheet string) (ws *xlsxWorksheet, err error) {
 		}
 	}
 	ws = new(xlsxWorksheet)
+	content := f.readBytes(name)
 	if attrs, ok := f.xmlAttr.Load(name); !ok {
-		d := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readBytes(name))))
+		d := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content)))
 		if attrs == nil {
 			attrs = []xml.Attr{}
 		}
 		attrs = append(attrs.([]xml.Attr), getRootElement(d)...)
 		f.xmlAttr.Store(name, attrs)
 	}
-	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(f.readBytes(name)))).
+	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content))).
 		Decode(ws); err != nil && err != io.EOF {
 		return
 	}
@@ -299,6 +430,7 @@ func (f *File) workSheetReader(sheet string) (ws *xlsxWorksheet, err error) {
 		f.checked.Store(name, true)
 	}
 	f.Sheet.Store(name, ws)
+	f.trackWorksheet(name, ws, int64(len(content)))
 	return
 }
 
@@ -1539,6 +1539,114 @@ func TestWorkSheetReader(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestWorksheetMemoryLimit(t *testing.T) {
+	f := NewFile()
+	for i := 1; i <= 4; i++ {
+		sheet := fmt.Sprintf("Sheet%d", i)
+		_, err := f.NewSheet(sheet)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow(sheet, "A1", &[]interface{}{sheet, i}))
+	}
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestWorksheetMemoryLimit.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err := OpenFile(filepath.Join("test", "TestWorksheetMemoryLimit.xlsx"), Options{WorksheetMemoryLimit: 1})
+	assert.NoError(t, err)
+	original := f.readBytes("xl/worksheets/sheet1.xml")
+	isLoaded := func(name string) bool {
+		_, ok := f.Sheet.Load(name)
+		return ok
+	}
+	// Test unload the worksheet which hasn't been modified
+	val, err := f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet1", val)
+	assert.True(t, isLoaded("xl/worksheets/sheet1.xml"))
+	val, err = f.GetCellValue("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet2", val)
+	assert.False(t, isLoaded("xl/worksheets/sheet1.xml"))
+	assert.True(t, isLoaded("xl/worksheets/sheet2.xml"))
+	_, ok := f.tempFiles.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	assert.Empty(t, f.readXML("xl/worksheets/sheet1.xml"))
+	assert.Equal(t, original, f.readBytes("xl/worksheets/sheet1.xml"))
+	assert.Empty(t, f.readXML("xl/worksheets/sheet1.xml"))
+	// Test unload the modified worksheet to the temporary file
+	assert.NoError(t, f.SetCellValue("Sheet3", "B2", "Modified"))
+	assert.NoError(t, f.SetCellValue("Sheet4", "B2", "Modified"))
+	assert.False(t, isLoaded("xl/worksheets/sheet3.xml"))
+	_, ok = f.tempFiles.Load("xl/worksheets/sheet3.xml")
+	assert.True(t, ok)
+	assert.Empty(t, f.readXML("xl/worksheets/sheet3.xml"))
+	val, err = f.GetCellValue("Sheet3", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Modified", val)
+	assert.False(t, isLoaded("xl/worksheets/sheet4.xml"))
+	// Test the worksheet in use will not be unloaded
+	ws, err := f.workSheetReader("Sheet3")
+	assert.NoError(t, err)
+	ws.mu.Lock()
+	_, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	ws.mu.Unlock()
+	assert.True(t, isLoaded("xl/worksheets/sheet3.xml"))
+	assert.True(t, isLoaded("xl/worksheets/sheet1.xml"))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestWorksheetMemoryLimit2.xlsx")))
+	assert.Equal(t, original, f.readBytes("xl/worksheets/sheet1.xml"))
+	assert.Empty(t, f.sheetPager.order)
+	// Test delete the unloaded worksheet
+	assert.NoError(t, f.DeleteSheet("Sheet4"))
+	_, ok = f.tempFiles.Load("xl/worksheets/sheet4.xml")
+	assert.False(t, ok)
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestWorksheetMemoryLimit2.xlsx"))
+	assert.NoError(t, err)
+	for sheet, expected := range map[string][][]string{
+		"Sheet1": {{"Sheet1", "1"}},
+		"Sheet3": {{"Sheet3", "3"}, {"", "Modified"}},
+		"Sheet4": {{"Sheet4", "4"}, {"", "Modified"}},
+	} {
+		rows, err := f.GetRows(sheet)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, rows)
+	}
+	assert.NoError(t, f.Close())
+
+	// Test the worksheet which is held by the operation will not be unloaded
+	f = NewFile()
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	assert.NoError(t, f.SetCellValue("Sheet2", "A1", 2))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestWorksheetMemoryLimit3.xlsx")))
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestWorksheetMemoryLimit3.xlsx"), Options{WorksheetMemoryLimit: 500})
+	assert.NoError(t, err)
+	assert.NoError(t, f.InsertRows("Sheet1", 1, 1))
+	for cell, expected := range map[string]string{"A1": "", "A2": "1"} {
+		val, err = f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+	for _, c := range []struct {
+		fn       func() error
+		expected [][]string
+	}{
+		{func() error { return f.DuplicateRowTo("Sheet1", 2, 1) }, [][]string{{"1"}, nil, {"1"}}},
+		{func() error { return f.InsertCols("Sheet1", "A", 1) }, [][]string{{"", "1"}, nil, {"", "1"}}},
+		{func() error { return f.RemoveRow("Sheet1", 1) }, [][]string{nil, {"", "1"}}},
+		{func() error { return f.RemoveCol("Sheet1", "A") }, [][]string{nil, {"1"}}},
+	} {
+		assert.NoError(t, c.fn())
+		rows, err := f.GetRows("Sheet1")
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, rows)
+	}
+	assert.NoError(t, f.Close())
+}
+
 func TestRelsReader(t *testing.T) {
 	// Test unsupported charset
 	f := NewFile()
@@ -14,10 +14,13 @@ package excelize
 import (
 	"archive/zip"
 	"bytes"
+	"compress/flate"
 	"encoding/xml"
+	"hash/crc32"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
@@ -66,15 +69,24 @@ func (f *File) Save(opts ...Options) error {
 }
 
 // SaveAs provides a function to create or update to a spreadsheet at the
-// provided path.
+// provided path. The workbook will be saved as the OpenDocument Spreadsheet if
+// the file extension of the path is ".ods". Save the spreadsheet in the
+// Strict Open XML Spreadsheet conformance with the StrictConformance option,
+// for example:
+//
+//	err := f.SaveAs("Book1.xlsx", excelize.Options{StrictConformance: true})
 func (f *File) SaveAs(name string, opts ...Options) error {
 	if len(name) > MaxFilePathLength {
 		return ErrMaxFilePathLength
 	}
 	f.Path = name
-	if _, ok := supportedContentTypes[strings.ToLower(filepath.Ext(f.Path))]; !ok {
+	ext := strings.ToLower(filepath.Ext(f.Path))
+	if _, ok := supportedContentTypes[ext]; !ok && ext != ".ods" {
 		return ErrWorkbookFileFormat
 	}
+	if f.isStreamZip() {
+		return ErrStreamSave
+	}
 	file, err := os.OpenFile(filepath.Clean(name), os.O_WRONLY|os.O_TRUNC|os.O_CREATE, os.ModePerm)
 	if err != nil {
 		return err
@@ -86,6 +98,7 @@ func (f *File) SaveAs(name string, opts ...Options) error {
 // Close closes and cleanup the open temporary file for the spreadsheet.
 func (f *File) Close() error {
 	var err error
+	streamErr := f.closeStreamZip()
 	if f.sharedStringTemp != nil {
 		if err := f.sharedStringTemp.Close(); err != nil {
 			return err
@@ -97,10 +110,14 @@ func (f *File) Close() error {
 		}
 		return true
 	})
-	for _, stream := range f.streams {
-		_ = stream.rawData.Close()
+	f.streams.Range(func(k, v interface{}) bool {
+		_ = v.(*StreamWriter).rawData.Close()
+		return true
+	})
+	if err != nil {
+		return err
 	}
-	return err
+	return streamErr
 }
 
 // Write provides a function to write to an io.Writer.
@@ -111,9 +128,15 @@ func (f *File) Write(w io.Writer, opts ...Options) error {
 
 // WriteTo implements io.WriterTo to write the file.
 func (f *File) WriteTo(w io.Writer, opts ...Options) (int64, error) {
+	if f.isStreamZip() {
+		return 0, ErrStreamSave
+	}
 	for i := range opts {
 		f.options = &opts[i]
 	}
+	if strings.EqualFold(filepath.Ext(f.Path), ".ods") {
+		return 0, f.writeODS(w)
+	}
 	if len(f.Path) != 0 {
 		contentType, ok := supportedContentTypes[strings.ToLower(filepath.Ext(f.Path))]
 		if !ok {
@@ -140,6 +163,9 @@ func (f *File) WriteTo(w io.Writer, opts ...Options) (int64, error) {
 // and it allocates space in memory. Be careful when the file size is large.
 func (f *File) WriteToBuffer() (*bytes.Buffer, error) {
 	buf := new(bytes.Buffer)
+	if f.isStreamZip() {
+		return buf, ErrStreamSave
+	}
 	zw := zip.NewWriter(buf)
 
 	if err := f.writeToZip(zw); err != nil {
@@ -179,6 +205,7 @@ func (f *File) writeToZip(zw *zip.Writer) error {
 	f.drawingsWriter()
 	f.volatileDepsWriter()
 	f.vmlDrawingWriter()
+	f.setConformance()
 	f.workBookWriter()
 	f.workSheetWriter()
 	f.relsWriter()
@@ -187,26 +214,15 @@ func (f *File) writeToZip(zw *zip.Writer) error {
 	f.styleSheetWriter()
 	f.themeWriter()
 
-	for path, stream := range f.streams {
-		fi, err := zw.Create(path)
-		if err != nil {
-			return err
-		}
-		var from io.Reader
-		if from, err = stream.rawData.Reader(); err != nil {
-			_ = stream.rawData.Close()
-			return err
-		}
-		if _, err = io.Copy(fi, from); err != nil {
-			return err
-		}
+	if err := f.writeStreams(zw); err != nil {
+		return err
 	}
 	var (
 		err              error
 		files, tempFiles []string
 	)
 	f.Pkg.Range(func(path, content interface{}) bool {
-		if _, ok := f.streams[path.(string)]; ok {
+		if _, ok := f.streams.Load(path); ok {
 			return true
 		}
 		files = append(files, path.(string))
@@ -214,12 +230,23 @@ func (f *File) writeToZip(zw *zip.Writer) error {
 	})
 	sort.Sort(sort.Reverse(sort.StringSlice(files)))
 	for _, path := range files {
-		var fi io.Writer
+		var (
+			fi     io.Writer
+			copied bool
+		)
+		content, _ := f.Pkg.Load(path)
+		if data, ok := content.([]byte); ok && data != nil {
+			if copied, err = f.writeSourcePart(zw, path, data); copied {
+				if err != nil {
+					break
+				}
+				continue
+			}
+		}
 		if fi, err = zw.Create(path); err != nil {
 			break
 		}
-		content, _ := f.Pkg.Load(path)
-		_, err = fi.Write(content.([]byte))
+		_, err = fi.Write(f.conformanceBytes(path, content.([]byte)))
 	}
 	f.tempFiles.Range(func(path, content interface{}) bool {
 		if _, ok := f.Pkg.Load(path); ok {
@@ -230,11 +257,192 @@ func (f *File) writeToZip(zw *zip.Writer) error {
 	})
 	sort.Sort(sort.Reverse(sort.StringSlice(tempFiles)))
 	for _, path := range tempFiles {
-		var fi io.Writer
+		var (
+			fi     io.Writer
+			copied bool
+		)
+		if copied, err = f.writeSourcePart(zw, path, nil); copied {
+			if err != nil {
+				break
+			}
+			continue
+		}
 		if fi, err = zw.Create(path); err != nil {
 			break
 		}
-		_, err = fi.Write(f.readBytes(path))
+		_, err = fi.Write(f.conformanceBytes(path, f.readBytes(path)))
 	}
 	return err
 }
+
+// sourcePart directly maps the part of the source spreadsheet which can be
+// copied to the output without recompression if it hasn't been modified. The
+// content is the part content which was stored in the package, and the temp is
+// the path of the temporary file which the part was unzipped to.
+type sourcePart struct {
+	file    *zip.File
+	content []byte
+	temp    string
+}
+
+// writeSourcePart provides a function to copy the compressed part of the
+// source spreadsheet by given path to the zip writer if the part hasn't been
+// modified, the content of the part should be nil if the part was stored in
+// the temporary file. It returns true if the part has been copied.
+func (f *File) writeSourcePart(zw *zip.Writer, path string, content []byte) (bool, error) {
+	val, ok := f.sourceParts.Load(path)
+	if !ok || f.isStrictConformance() {
+		return false, nil
+	}
+	part := val.(*sourcePart)
+	if content == nil {
+		if temp, _ := f.tempFiles.Load(path); part.temp == "" || temp != part.temp {
+			return false, nil
+		}
+	} else if part.content == nil || !sameBytes(content, part.content) {
+		return false, nil
+	}
+	header := part.file.FileHeader
+	header.Name = path
+	fi, err := zw.CreateRaw(&header)
+	if err != nil {
+		return true, err
+	}
+	rc, err := part.file.OpenRaw()
+	if err != nil {
+		return true, err
+	}
+	_, err = io.Copy(fi, rc)
+	return true, err
+}
+
+// deflatedStream directly maps the deflate compressed worksheet which was
+// written by the stream writer.
+type deflatedStream struct {
+	header zip.FileHeader
+	data   bufferedWriter
+	size   int64
+	err    error
+}
+
+// Write writes the compressed data to the buffer, and the buffer will be
+// written to a temp file when it has grown large enough.
+func (ds *deflatedStream) Write(p []byte) (int, error) {
+	n, _ := ds.data.Write(p)
+	ds.size += int64(n)
+	return n, ds.data.Sync()
+}
+
+// writeStreams provides a function to compress the worksheets written by the
+// stream writers in parallel, and write the compressed worksheets to the zip
+// in the order of the part names.
+func (f *File) writeStreams(zw *zip.Writer) error {
+	var (
+		wg    sync.WaitGroup
+		paths []string
+		sem   = make(chan struct{}, runtime.GOMAXPROCS(0))
+	)
+	f.streams.Range(func(path, sw interface{}) bool {
+		if sw.(*StreamWriter).rawData.out == nil {
+			paths = append(paths, path.(string))
+		}
+		return true
+	})
+	sort.Strings(paths)
+	streams := make([]*deflatedStream, len(paths))
+	for i, path := range paths {
+		sw, _ := f.streams.Load(path)
+		streams[i] = &deflatedStream{header: zip.FileHeader{Name: path, Method: zip.Deflate}}
+		wg.Add(1)
+		go func(ds *deflatedStream, sw *StreamWriter) {
+			defer wg.Done()
+			sem <- struct{}{}
+			ds.err = f.deflateStream(ds, sw)
+			<-sem
+		}(streams[i], sw.(*StreamWriter))
+	}
+	wg.Wait()
+	defer func() {
+		for _, ds := range streams {
+			_ = ds.data.Close()
+		}
+	}()
+	for _, ds := range streams {
+		if ds.err != nil {
+			return ds.err
+		}
+		fi, err := zw.CreateRaw(&ds.header)
+		if err != nil {
+			return err
+		}
+		from, err := ds.data.Reader()
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(fi, from); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deflateStream provides a function to compress the worksheet written by the
+// stream writer, and set the checksum and sizes of the zip file header.
+func (f *File) deflateStream(ds *deflatedStream, sw *StreamWriter) error {
+	from, err := sw.rawData.Reader()
+	if err != nil {
+		_ = sw.rawData.Close()
+		return err
+	}
+	if f.isStrictConformance() {
+		var content []byte
+		if content, err = io.ReadAll(from); err != nil {
+			return err
+		}
+		from = bytes.NewReader(namespaceTransitionalToStrict(content))
+	}
+	crc := crc32.NewIEEE()
+	fw, _ := flate.NewWriter(ds, flate.DefaultCompression)
+	size, err := io.Copy(io.MultiWriter(fw, crc), from)
+	if err != nil {
+		return err
+	}
+	if err = fw.Close(); err != nil {
+		return err
+	}
+	ds.header.CRC32 = crc.Sum32()
+	ds.header.UncompressedSize64 = uint64(size)
+	ds.header.CompressedSize64 = uint64(ds.size)
+	return nil
+}
+
+// isStrictConformance provides a function to check if save the spreadsheet in
+// the Strict Open XML Spreadsheet conformance.
+func (f *File) isStrictConformance() bool {
+	return f.options != nil && f.options.StrictConformance
+}
+
+// setConformance provides a function to set the conformance class of the
+// workbook on saving. The conformance of the workbook opened from the Strict
+// spreadsheet will be reset if save in the Transitional conformance.
+func (f *File) setConformance() {
+	strict := f.isStrictConformance()
+	if !strict && f.WorkBook == nil && !bytes.Contains(f.readXML(f.getWorkbookPath()), []byte("conformance")) {
+		return
+	}
+	if wb, _ := f.workbookReader(); wb != nil {
+		wb.Conformance = ""
+		if strict {
+			wb.Conformance = "strict"
+		}
+	}
+}
+
+// conformanceBytes provides a function to convert the namespaces of the XML
+// part by given path and content for saving in the Strict conformance.
+func (f *File) conformanceBytes(path string, content []byte) []byte {
+	if ext := strings.ToLower(filepath.Ext(path)); f.isStrictConformance() && (ext == ".xml" || ext == ".rels") {
+		return namespaceTransitionalToStrict(content)
+	}
+	return content
+}
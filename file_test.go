@@ -1,6 +1,7 @@
 package excelize
 
 import (
+	"archive/zip"
 	"bufio"
 	"bytes"
 	"os"
@@ -57,12 +58,21 @@ func TestWriteTo(t *testing.T) {
 	{
 		f, buf := File{Pkg: sync.Map{}}, bytes.Buffer{}
 		f.Pkg.Store("s", nil)
-		f.streams = make(map[string]*StreamWriter)
 		file, _ := os.Open("123")
-		f.streams["s"] = &StreamWriter{rawData: bufferedWriter{tmp: file}}
+		f.streams.Store("s", &StreamWriter{rawData: bufferedWriter{tmp: file}})
 		_, err := f.WriteTo(bufio.NewWriter(&buf))
 		assert.Nil(t, err)
 	}
+	// Test compress stream worksheet with closed temporary file
+	{
+		f, buf := File{Pkg: sync.Map{}}, bytes.Buffer{}
+		file, err := os.CreateTemp(os.TempDir(), "excelize-")
+		assert.NoError(t, err)
+		assert.NoError(t, file.Close())
+		f.streams.Store("s", &StreamWriter{rawData: bufferedWriter{tmp: file}})
+		_, err = f.WriteTo(bufio.NewWriter(&buf))
+		assert.EqualError(t, err, "stat "+file.Name()+": file already closed")
+	}
 	// Test write with temporary file
 	{
 		f, buf := File{tempFiles: sync.Map{}}, bytes.Buffer{}
@@ -95,3 +105,168 @@ func TestClose(t *testing.T) {
 	f.tempFiles.Store("/d/", "/d/")
 	require.Error(t, f.Close())
 }
+
+func TestStrictConformance(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Strict"))
+	assert.NoError(t, f.AddComment("Sheet1", Comment{Cell: "A1", Author: "Excelize", Text: "Comment"}))
+	assert.NoError(t, f.AddPicture("Sheet1", "B2", filepath.Join("test", "images", "excel.png"), nil))
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	sw, err := f.NewStreamWriter("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"Stream", 1}))
+	assert.NoError(t, sw.Flush())
+	buf := new(bytes.Buffer)
+	assert.NoError(t, f.Write(buf, Options{StrictConformance: true}))
+	assert.NoError(t, f.Close())
+
+	// Test the namespaces of the parts in the Strict conformance
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	parts := map[string]string{}
+	for _, file := range zr.File {
+		content, err := readFile(file)
+		assert.NoError(t, err)
+		parts[file.Name] = string(content)
+	}
+	assert.Contains(t, parts[defaultXMLPathWorkbook], `conformance="strict"`)
+	for path, namespace := range map[string]string{
+		defaultXMLPathWorkbook:                StrictNameSpaceSpreadSheet,
+		"xl/worksheets/sheet2.xml":            StrictNameSpaceSpreadSheet,
+		"_rels/.rels":                         StrictSourceRelationshipOfficeDocument,
+		"xl/drawings/drawing1.xml":            StrictNameSpaceDrawingMLSpreadSheet,
+		"xl/theme/theme1.xml":                 StrictNameSpaceDrawingMLMain,
+		"xl/worksheets/sheet1.xml":            StrictSourceRelationship,
+		"xl/drawings/_rels/drawing1.xml.rels": StrictSourceRelationshipImage,
+	} {
+		assert.Contains(t, parts[path], namespace, path)
+		assert.NotContains(t, parts[path], "http://schemas.openxmlformats.org/spreadsheetml/2006/main", path)
+		assert.NotContains(t, parts[path], "http://schemas.openxmlformats.org/officeDocument/2006/relationships", path)
+	}
+
+	// Test open the spreadsheet in the Strict conformance
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	for cell, expected := range map[string]string{"Sheet1!A1": "Strict", "Sheet2!A1": "Stream", "Sheet2!B1": "1"} {
+		ref := strings.Split(cell, "!")
+		val, err := f.GetCellValue(ref[0], ref[1])
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+	comments, err := f.GetComments("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	pics, err := f.GetPictures("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Len(t, pics, 1)
+	// Test save the Strict spreadsheet in the Transitional conformance
+	buf.Reset()
+	assert.NoError(t, f.Write(buf))
+	assert.NoError(t, f.Close())
+	f, err = OpenReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Empty(t, f.WorkBook.Conformance)
+	assert.NotContains(t, buf.String(), "purl.oclc.org")
+	wb, err := f.workbookReader()
+	assert.NoError(t, err)
+	assert.Empty(t, wb.Conformance)
+	assert.NoError(t, f.Close())
+
+}
+
+func TestIncrementalSave(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	for _, sheet := range []string{"Sheet1", "Sheet2"} {
+		assert.NoError(t, f.SetSheetRow(sheet, "A1", &[]interface{}{sheet, 1, true}))
+	}
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", style))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestIncrementalSave.xlsx")))
+	assert.NoError(t, f.Close())
+	source, err := os.ReadFile(filepath.Join("test", "TestIncrementalSave.xlsx"))
+	assert.NoError(t, err)
+
+	readRawParts := func(b []byte) map[string][]byte {
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		assert.NoError(t, err)
+		parts := make(map[string][]byte, len(zr.File))
+		for _, file := range zr.File {
+			rc, err := file.OpenRaw()
+			assert.NoError(t, err)
+			var buf bytes.Buffer
+			_, err = buf.ReadFrom(rc)
+			assert.NoError(t, err)
+			parts[file.Name] = buf.Bytes()
+		}
+		return parts
+	}
+	for _, opts := range []Options{
+		{IncrementalSave: true},
+		{IncrementalSave: true, UnzipXMLSizeLimit: 1},
+	} {
+		f, err = OpenReader(bytes.NewReader(source), opts)
+		assert.NoError(t, err)
+		val, err := f.GetCellValue("Sheet1", "A1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Sheet1", val)
+		assert.NoError(t, f.SetCellValue("Sheet2", "B2", "Modified"))
+		buf, err := f.WriteToBuffer()
+		assert.NoError(t, err)
+		// Test the unmodified decoded parts are not serialized on saving
+		for _, name := range []string{defaultXMLPathStyles, "xl/worksheets/sheet1.xml"} {
+			content, ok := f.Pkg.Load(name)
+			assert.True(t, ok)
+			part, ok := f.sourceParts.Load(name)
+			assert.True(t, ok)
+			assert.True(t, sameBytes(content.([]byte), part.(*sourcePart).content), name)
+		}
+		assert.NoError(t, f.Close())
+
+		expected, parts := readRawParts(source), readRawParts(buf.Bytes())
+		assert.Len(t, parts, len(expected))
+		for name, content := range parts {
+			if name == "xl/worksheets/sheet2.xml" || name == "xl/sharedStrings.xml" {
+				assert.NotEqual(t, expected[name], content)
+				continue
+			}
+			assert.Equal(t, expected[name], content, name)
+		}
+		f, err = OpenReader(buf)
+		assert.NoError(t, err)
+		rows, err := f.GetRows("Sheet2")
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{{"Sheet2", "1", "TRUE"}, {"", "Modified"}}, rows)
+		assert.NoError(t, f.Close())
+	}
+
+	// Test incremental save in the Strict conformance
+	f, err = OpenReader(bytes.NewReader(source), Options{IncrementalSave: true})
+	assert.NoError(t, err)
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.Equal(t, readRawParts(source), readRawParts(buf.Bytes()))
+	var strict bytes.Buffer
+	assert.NoError(t, f.Write(&strict, Options{IncrementalSave: true, StrictConformance: true}))
+	assert.NoError(t, f.Close())
+	f, err = OpenReader(&strict, Options{IncrementalSave: true})
+	assert.NoError(t, err)
+	_, ok := f.sourceParts.Load("xl/worksheets/sheet1.xml")
+	assert.False(t, ok)
+	buf, err = f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		assert.NoError(t, err)
+		var content bytes.Buffer
+		_, err = content.ReadFrom(rc)
+		assert.NoError(t, err)
+		assert.NotContains(t, content.String(), "http://purl.oclc.org/ooxml/", file.Name)
+	}
+}
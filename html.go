@@ -0,0 +1,511 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// htmlColorPattern defined the pattern of the RGB or ARGB hex color which
+// can be used in the inline CSS.
+var htmlColorPattern = regexp.MustCompile(`^[0-9A-Fa-f]{6}([0-9A-Fa-f]{2})?$`)
+
+// htmlFontFamilyReplacer defined the replacer to remove the characters which
+// can't be used in the quoted font family name of the inline CSS.
+var htmlFontFamilyReplacer = strings.NewReplacer("'", "", "\\", "", "\n", "", "\r", "")
+
+// htmlBorderStyles defined the CSS border width and style by the index of
+// the cell border styles.
+var htmlBorderStyles = map[int]string{
+	1: "1px solid", 2: "2px solid", 3: "1px dashed", 4: "1px dotted", 5: "3px solid",
+	6: "3px double", 7: "1px dotted", 8: "2px dashed", 9: "1px dashed", 10: "2px dashed",
+	11: "1px dotted", 12: "2px dotted", 13: "2px dashed",
+}
+
+// htmlImageTypes defined the media types of the embedded pictures by file
+// extension.
+var htmlImageTypes = map[string]string{
+	".bmp": "image/bmp", ".emf": "image/x-emf", ".emz": "image/x-emz", ".gif": "image/gif",
+	".jpeg": "image/jpeg", ".jpg": "image/jpeg", ".png": "image/png", ".svg": "image/svg+xml",
+	".tif": "image/tiff", ".tiff": "image/tiff", ".wmf": "image/x-wmf", ".wmz": "image/x-wmz",
+}
+
+// HTMLOptions directly maps the settings of the HTML export.
+//
+// RangeRef specifies the cell range reference to be exported, such as
+// "A1:D10", the used range of the worksheet will be exported by default.
+//
+// EmbedPictures specifies if embed the pictures which anchored in the cells
+// into the exported table as data URIs.
+type HTMLOptions struct {
+	RangeRef      string
+	EmbedPictures bool
+}
+
+// htmlMergeCell defined the span of the merged cell in the exported table.
+type htmlMergeCell struct {
+	col, row, colSpan, rowSpan int
+}
+
+// htmlExporter defined the state of the worksheet HTML export.
+type htmlExporter struct {
+	f            *File
+	sheet        string
+	sst          *xlsxSST
+	coordinates  []int
+	cells        map[int]map[int]xlsxC
+	hiddenRows   map[int]bool
+	hiddenCols   map[int]bool
+	mergeCells   map[int]map[int]htmlMergeCell
+	coveredCells map[int]map[int]bool
+	pictures     map[int]map[int][]Picture
+	styles       map[int]string
+}
+
+// ExportHTML provides a function to export the worksheet or a range of the
+// worksheet to io.Writer as an HTML table by given worksheet name and HTML
+// options. The cell values will be formatted with the number format of the
+// cells, the fonts, fills, borders and alignment of the cells will be mapped
+// to the inline CSS, the merged cells will be exported with the rowspan and
+// colspan attributes, and the hidden rows and columns will be skipped. For
+// example, export the range A1:D10 of the worksheet named 'Sheet1' with the
+// embedded pictures:
+//
+//	file, err := os.Create("report.html")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	if err := f.ExportHTML("Sheet1", file, excelize.HTMLOptions{
+//	    RangeRef:      "A1:D10",
+//	    EmbedPictures: true,
+//	}); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) ExportHTML(sheet string, w io.Writer, opts ...HTMLOptions) error {
+	var options HTMLOptions
+	for _, opt := range opts {
+		options = opt
+	}
+	e := &htmlExporter{
+		f: f, sheet: sheet, cells: make(map[int]map[int]xlsxC),
+		hiddenRows: make(map[int]bool), hiddenCols: make(map[int]bool),
+		mergeCells: make(map[int]map[int]htmlMergeCell), coveredCells: make(map[int]map[int]bool),
+		pictures: make(map[int]map[int][]Picture), styles: make(map[int]string),
+	}
+	if err := e.prepareCells(options.RangeRef); err != nil {
+		return err
+	}
+	if err := e.prepareMergeCells(); err != nil {
+		return err
+	}
+	if options.EmbedPictures {
+		if err := e.preparePictures(); err != nil {
+			return err
+		}
+	}
+	bw := bufio.NewWriter(w)
+	if err := e.writeTable(bw); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// prepareCells provides a function to copy the cells, hidden rows and hidden
+// columns of the worksheet in the exported range. The used range of the
+// worksheet will be exported if the given range reference is empty.
+func (e *htmlExporter) prepareCells(rangeRef string) error {
+	f := e.f
+	f.mu.Lock()
+	ws, err := f.workSheetReader(e.sheet)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	f.mu.Unlock()
+	if e.sst, err = f.sharedStringsReader(); err != nil {
+		return err
+	}
+	if rangeRef != "" {
+		if e.coordinates, err = rangeRefToCoordinates(rangeRef); err != nil {
+			return err
+		}
+		_ = sortCoordinates(e.coordinates)
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	used := []int{0, 0, 0, 0}
+	expand := func(col, row int) {
+		if used[0] == 0 {
+			used = []int{col, row, col, row}
+		}
+		if col < used[0] {
+			used[0] = col
+		}
+		if row < used[1] {
+			used[1] = row
+		}
+		if col > used[2] {
+			used[2] = col
+		}
+		if row > used[3] {
+			used[3] = row
+		}
+	}
+	for _, row := range ws.SheetData.Row {
+		if row.Hidden {
+			e.hiddenRows[row.R] = true
+		}
+		for _, c := range row.C {
+			if c.V == "" && c.F == nil && c.IS == nil && c.S == 0 {
+				continue
+			}
+			col, r, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return err
+			}
+			if e.cells[r] == nil {
+				e.cells[r] = make(map[int]xlsxC)
+			}
+			e.cells[r][col] = c
+			expand(col, r)
+		}
+	}
+	if ws.MergeCells != nil {
+		for _, mc := range ws.MergeCells.Cells {
+			if coordinates, err := rangeRefToCoordinates(mc.Ref); err == nil {
+				_ = sortCoordinates(coordinates)
+				expand(coordinates[0], coordinates[1])
+				expand(coordinates[2], coordinates[3])
+			}
+		}
+	}
+	if ws.Cols != nil {
+		for _, col := range ws.Cols.Col {
+			for c := col.Min; col.Hidden && c <= col.Max && c <= MaxColumns; c++ {
+				e.hiddenCols[c] = true
+			}
+		}
+	}
+	if e.coordinates == nil {
+		e.coordinates = used
+	}
+	return err
+}
+
+// prepareMergeCells provides a function to calculate the spans of the merged
+// cells in the exported range. The merged cell will be anchored at the first
+// visible row and column of the merged range, and the value and style of the
+// top-left cell of the merged range will be exported in the anchor cell.
+func (e *htmlExporter) prepareMergeCells() error {
+	mergeCells, err := e.f.GetMergeCells(e.sheet)
+	if err != nil {
+		return err
+	}
+	for _, mc := range mergeCells {
+		coordinates, err := rangeRefToCoordinates(mc[0])
+		if err != nil {
+			return err
+		}
+		_ = sortCoordinates(coordinates)
+		span := htmlMergeCell{col: coordinates[0], row: coordinates[1]}
+		for i := 0; i < 2; i++ {
+			if coordinates[i] < e.coordinates[i] {
+				coordinates[i] = e.coordinates[i]
+			}
+			if coordinates[i+2] > e.coordinates[i+2] {
+				coordinates[i+2] = e.coordinates[i+2]
+			}
+		}
+		var anchorCol, anchorRow int
+		for col := coordinates[0]; col <= coordinates[2]; col++ {
+			if !e.hiddenCols[col] {
+				if anchorCol == 0 {
+					anchorCol = col
+				}
+				span.colSpan++
+			}
+		}
+		for row := coordinates[1]; row <= coordinates[3]; row++ {
+			if !e.hiddenRows[row] {
+				if anchorRow == 0 {
+					anchorRow = row
+				}
+				span.rowSpan++
+			}
+		}
+		for row := coordinates[1]; row <= coordinates[3]; row++ {
+			for col := coordinates[0]; col <= coordinates[2]; col++ {
+				if row == anchorRow && col == anchorCol {
+					continue
+				}
+				if e.coveredCells[row] == nil {
+					e.coveredCells[row] = make(map[int]bool)
+				}
+				e.coveredCells[row][col] = true
+			}
+		}
+		if anchorCol == 0 || anchorRow == 0 {
+			continue
+		}
+		if e.mergeCells[anchorRow] == nil {
+			e.mergeCells[anchorRow] = make(map[int]htmlMergeCell)
+		}
+		e.mergeCells[anchorRow][anchorCol] = span
+	}
+	return err
+}
+
+// preparePictures provides a function to get the pictures which anchored in
+// the cells of the exported range.
+func (e *htmlExporter) preparePictures() error {
+	cells, err := e.f.GetPictureCells(e.sheet)
+	if err != nil {
+		return err
+	}
+	for _, cell := range cells {
+		col, row, err := CellNameToCoordinates(cell)
+		if err != nil {
+			return err
+		}
+		if !e.inRange(col, row) {
+			continue
+		}
+		pics, err := e.f.GetPictures(e.sheet, cell)
+		if err != nil {
+			return err
+		}
+		if e.pictures[row] == nil {
+			e.pictures[row] = make(map[int][]Picture)
+		}
+		e.pictures[row][col] = pics
+	}
+	return err
+}
+
+// inRange returns if the given cell coordinates in the exported range.
+func (e *htmlExporter) inRange(col, row int) bool {
+	return e.coordinates[0] <= col && col <= e.coordinates[2] &&
+		e.coordinates[1] <= row && row <= e.coordinates[3]
+}
+
+// writeTable provides a function to write the exported range as the HTML
+// table.
+func (e *htmlExporter) writeTable(w *bufio.Writer) error {
+	_, _ = w.WriteString(`<table style="border-collapse:collapse;table-layout:fixed">`)
+	if e.coordinates[0] == 0 {
+		_, _ = w.WriteString("</table>\n")
+		return nil
+	}
+	_, _ = w.WriteString("\n<colgroup>")
+	for col := e.coordinates[0]; col <= e.coordinates[2]; col++ {
+		if e.hiddenCols[col] {
+			continue
+		}
+		name, _ := ColumnNumberToName(col)
+		width, err := e.f.GetColWidth(e.sheet, name)
+		if err != nil {
+			return err
+		}
+		px := defaultColWidthPixels
+		if width != defaultColWidth {
+			px = convertColWidthToPixels(width)
+		}
+		fmt.Fprintf(w, `<col style="width:%spx">`, strconv.FormatFloat(px, 'f', -1, 64))
+	}
+	_, _ = w.WriteString("</colgroup>\n")
+	for row := e.coordinates[1]; row <= e.coordinates[3]; row++ {
+		if e.hiddenRows[row] {
+			continue
+		}
+		height, err := e.f.GetRowHeight(e.sheet, row)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, `<tr style="height:%spx">`, strconv.FormatFloat(math.Round(height/0.75), 'f', -1, 64))
+		for col := e.coordinates[0]; col <= e.coordinates[2]; col++ {
+			if e.hiddenCols[col] || e.coveredCells[row][col] {
+				continue
+			}
+			if err = e.writeCell(w, col, row); err != nil {
+				return err
+			}
+		}
+		_, _ = w.WriteString("</tr>\n")
+	}
+	_, _ = w.WriteString("</table>\n")
+	return nil
+}
+
+// writeCell provides a function to write the table cell by given cell
+// coordinates.
+func (e *htmlExporter) writeCell(w *bufio.Writer, col, row int) error {
+	_, _ = w.WriteString("<td")
+	c, ok := e.cells[row][col]
+	if span, merged := e.mergeCells[row][col]; merged {
+		if span.colSpan > 1 {
+			fmt.Fprintf(w, ` colspan="%d"`, span.colSpan)
+		}
+		if span.rowSpan > 1 {
+			fmt.Fprintf(w, ` rowspan="%d"`, span.rowSpan)
+		}
+		c, ok = e.cells[span.row][span.col]
+	}
+	var (
+		value string
+		err   error
+	)
+	if ok {
+		css, err := e.cellStyle(c.S)
+		if err != nil {
+			return err
+		}
+		if value, err = c.getValueFrom(e.f, e.sst, false); err != nil {
+			return err
+		}
+		if !strings.Contains(css, "text-align:") {
+			if c.T == "b" || c.T == "e" {
+				css += "text-align:center;"
+			} else if isNum, _, _ := isNumeric(c.V); isNum && c.T != "s" && c.T != "str" && c.T != "inlineStr" {
+				css += "text-align:right;"
+			}
+		}
+		if css != "" {
+			_, _ = w.WriteString(` style="` + html.EscapeString(css) + `"`)
+		}
+	}
+	_, _ = w.WriteString(">")
+	for _, pic := range e.pictures[row][col] {
+		var alt string
+		if pic.Format != nil {
+			alt = pic.Format.AltText
+		}
+		fmt.Fprintf(w, `<img src="data:%s;base64,%s" alt="%s">`, html.EscapeString(htmlImageTypes[strings.ToLower(pic.Extension)]),
+			base64.StdEncoding.EncodeToString(pic.File), html.EscapeString(alt))
+	}
+	_, _ = w.WriteString(strings.ReplaceAll(html.EscapeString(value), "\n", "<br>"))
+	_, _ = w.WriteString("</td>")
+	return err
+}
+
+// cellStyle provides a function to convert the cell style by given style
+// index to the inline CSS.
+func (e *htmlExporter) cellStyle(styleID int) (string, error) {
+	if css, ok := e.styles[styleID]; ok {
+		return css, nil
+	}
+	defaultStyle, err := e.f.GetStyle(0)
+	if err != nil || styleID == 0 {
+		return "", err
+	}
+	style, err := e.f.GetStyle(styleID)
+	if err != nil {
+		return "", err
+	}
+	var css strings.Builder
+	if font := style.Font; font != nil {
+		defaultFont := defaultStyle.Font
+		if defaultFont == nil {
+			defaultFont = &Font{}
+		}
+		if font.Family != "" && font.Family != defaultFont.Family {
+			css.WriteString("font-family:'" + htmlFontFamilyReplacer.Replace(font.Family) + "';")
+		}
+		if font.Size > 0 && font.Size != defaultFont.Size {
+			css.WriteString("font-size:" + strconv.FormatFloat(font.Size, 'f', -1, 64) + "pt;")
+		}
+		if font.Bold {
+			css.WriteString("font-weight:bold;")
+		}
+		if font.Italic {
+			css.WriteString("font-style:italic;")
+		}
+		var decorations []string
+		if font.Underline != "" && font.Underline != "none" {
+			decorations = append(decorations, "underline")
+		}
+		if font.Strike {
+			decorations = append(decorations, "line-through")
+		}
+		if len(decorations) > 0 {
+			css.WriteString("text-decoration:" + strings.Join(decorations, " ") + ";")
+		}
+		if font.Color != "" || font.ColorTheme != nil || font.ColorIndexed != 0 {
+			if color := e.color(font.Color, font.ColorIndexed, font.ColorTheme, font.ColorTint); color != "" &&
+				color != e.color(defaultFont.Color, defaultFont.ColorIndexed, defaultFont.ColorTheme, defaultFont.ColorTint) {
+				css.WriteString("color:#" + color + ";")
+			}
+		}
+	}
+	if len(style.Fill.Color) > 0 && (style.Fill.Type == "gradient" || style.Fill.Pattern > 0) {
+		if color := e.color(style.Fill.Color[0], 0, nil, 0); color != "" {
+			css.WriteString("background-color:#" + color + ";")
+		}
+	}
+	for _, border := range style.Border {
+		line, ok := htmlBorderStyles[border.Style]
+		if !ok || inStrSlice([]string{"left", "right", "top", "bottom"}, border.Type, true) == -1 {
+			continue
+		}
+		color := e.color(border.Color, 0, nil, 0)
+		if color == "" {
+			color = "000000"
+		}
+		css.WriteString("border-" + border.Type + ":" + line + " #" + color + ";")
+	}
+	if alignment := style.Alignment; alignment != nil {
+		if align, ok := map[string]string{
+			"left": "left", "center": "center", "right": "right", "fill": "left",
+			"justify": "justify", "centerContinuous": "center", "distributed": "justify",
+		}[alignment.Horizontal]; ok {
+			css.WriteString("text-align:" + align + ";")
+		}
+		if align, ok := map[string]string{
+			"top": "top", "center": "middle", "bottom": "bottom", "justify": "middle", "distributed": "middle",
+		}[alignment.Vertical]; ok {
+			css.WriteString("vertical-align:" + align + ";")
+		}
+		if alignment.WrapText {
+			css.WriteString("white-space:pre-wrap;")
+		}
+		if alignment.Indent > 0 {
+			css.WriteString("padding-left:" + strconv.Itoa(alignment.Indent*9) + "px;")
+		}
+	}
+	e.styles[styleID] = css.String()
+	return e.styles[styleID], err
+}
+
+// color returns the RGB hex color by given hex color, indexed color, theme
+// color and tint.
+func (e *htmlExporter) color(hexColor string, indexedColor int, themeColor *int, tint float64) string {
+	color := strings.TrimPrefix(e.f.GetBaseColor(strings.TrimPrefix(hexColor, "#"), indexedColor, themeColor), "#")
+	if tint != 0 && color != "" {
+		color = strings.TrimPrefix(ThemeColor(color, tint), "FF")
+	}
+	if !htmlColorPattern.MatchString(color) {
+		return ""
+	}
+	if len(color) == 8 {
+		color = color[2:]
+	}
+	return strings.ToUpper(color)
+}
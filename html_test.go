@@ -0,0 +1,95 @@
+package excelize
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportHTML(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Price", "Paid", "Note"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"<Apple>", 1234.5, true, "a\nb"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]interface{}{"Hidden", 1}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A4", &[]interface{}{"Total", 1235.5}))
+	assert.NoError(t, f.MergeCell("Sheet1", "C3", "D4"))
+	assert.NoError(t, f.SetRowVisible("Sheet1", 3, false))
+	assert.NoError(t, f.SetColVisible("Sheet1", "E", false))
+	assert.NoError(t, f.SetCellValue("Sheet1", "E1", "Hidden"))
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "A", 20))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 1, 30))
+	headerStyle, err := f.NewStyle(&Style{
+		Font:      &Font{Bold: true, Italic: true, Underline: "single", Strike: true, Color: "FFFFFF", Size: 12, Family: "Arial"},
+		Fill:      Fill{Type: "pattern", Pattern: 1, Color: []string{"4472C4"}},
+		Border:    []Border{{Type: "bottom", Color: "FF0000", Style: 6}, {Type: "top", Style: 1}, {Type: "diagonalDown", Style: 1}},
+		Alignment: &Alignment{Horizontal: "center", Vertical: "center", WrapText: true, Indent: 1},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "D1", headerStyle))
+	numberStyle, err := f.NewStyle(&Style{NumFmt: 4, Font: &Font{ColorTheme: intPtr(4), ColorTint: 0.5}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B2", "B4", numberStyle))
+	file, err := os.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.AddPictureFromBytes("Sheet1", "D2", &Picture{
+		Extension: ".png", File: file, Format: &GraphicOptions{AltText: "Excel \"Logo\""},
+	}))
+
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportHTML("Sheet1", &buf, HTMLOptions{EmbedPictures: true}))
+	header := `font-family:&#39;Arial&#39;;font-size:12pt;font-weight:bold;font-style:italic;text-decoration:underline line-through;color:#FFFFFF;background-color:#4472C4;border-top:1px solid #000000;border-bottom:3px double #FF0000;text-align:center;vertical-align:middle;white-space:pre-wrap;padding-left:9px;`
+	assert.Equal(t, `<table style="border-collapse:collapse;table-layout:fixed">
+<colgroup><col style="width:146px"><col style="width:64px"><col style="width:64px"><col style="width:64px"></colgroup>
+<tr style="height:40px"><td style="`+header+`">Name</td><td style="`+header+`">Price</td><td style="`+header+`">Paid</td><td style="`+header+`">Note</td></tr>
+<tr style="height:20px"><td>&lt;Apple&gt;</td><td style="color:#ADCDEA;text-align:right;">1,234.50</td><td style="text-align:center;">TRUE</td><td><img src="data:image/png;base64,`+
+		base64.StdEncoding.EncodeToString(file)+`" alt="Excel &#34;Logo&#34;">a<br>b</td></tr>
+<tr style="height:20px"><td>Total</td><td style="color:#ADCDEA;text-align:right;">1,235.50</td><td colspan="2"></td></tr>
+</table>
+`, buf.String())
+
+	// Test export the range of the worksheet without pictures
+	buf.Reset()
+	assert.NoError(t, f.ExportHTML("Sheet1", &buf, HTMLOptions{RangeRef: "D4:C2"}))
+	assert.Equal(t, `<table style="border-collapse:collapse;table-layout:fixed">
+<colgroup><col style="width:64px"><col style="width:64px"></colgroup>
+<tr style="height:20px"><td style="text-align:center;">TRUE</td><td>a<br>b</td></tr>
+<tr style="height:20px"><td colspan="2"></td></tr>
+</table>
+`, buf.String())
+
+	// Test export the empty worksheet
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	buf.Reset()
+	assert.NoError(t, f.ExportHTML("Sheet2", &buf))
+	assert.Equal(t, "<table style=\"border-collapse:collapse;table-layout:fixed\"></table>\n", buf.String())
+
+	// Test export the worksheet with the markup in the styles
+	style, err := f.NewStyle(&Style{
+		Font: &Font{Family: `x'"><script>`},
+		Fill: Fill{Type: "pattern", Pattern: 1, Color: []string{`"><svg`}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet2", "A1", "A"))
+	assert.NoError(t, f.SetCellStyle("Sheet2", "A1", "A1", style))
+	buf.Reset()
+	assert.NoError(t, f.ExportHTML("Sheet2", &buf))
+	assert.Contains(t, buf.String(), `<td style="font-family:&#39;x&#34;&gt;&lt;script&gt;&#39;;">A</td>`)
+
+	// Test export the worksheet with invalid range reference
+	assert.Equal(t, ErrParameterInvalid, f.ExportHTML("Sheet1", &buf, HTMLOptions{RangeRef: "A1"}))
+	// Test export the worksheet which doesn't exist
+	assert.EqualError(t, f.ExportHTML("SheetN", &buf), "sheet SheetN does not exist")
+	// Test export the worksheet with invalid style
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).SheetData.Row[0].C[0].S = 100
+	assert.Equal(t, newInvalidStyleID(100), f.ExportHTML("Sheet1", &buf))
+	ws.(*xlsxWorksheet).SheetData.Row[0].C[0].R = "A"
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), f.ExportHTML("Sheet1", &buf))
+	assert.NoError(t, f.Close())
+}
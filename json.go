@@ -0,0 +1,527 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/nfp"
+)
+
+// JSONOptions directly maps the settings of the JSON import and export.
+//
+// Table specifies the name of the table created by the AddTable function to
+// be exported, the used range of the worksheet will be exported by default.
+//
+// NDJSON specifies if export newline-delimited JSON, which write one object
+// per line, instead of a JSON array of objects.
+//
+// DateLayouts specifies the Go time layouts for the date type inference of
+// the string values on import JSON, such as "2006-01-02" and
+// "2006-01-02 15:04:05" by default.
+type JSONOptions struct {
+	Table       string
+	NDJSON      bool
+	DateLayouts []string
+}
+
+// cellTyper defined the state of converting the cell values to the typed
+// values by the cell types and the number formats.
+type cellTyper struct {
+	f           *File
+	date1904    bool
+	dateLayouts map[int]string
+}
+
+// jsonExporter defined the state of the JSON export.
+type jsonExporter struct {
+	cellTyper
+	buf bytes.Buffer
+	enc *json.Encoder
+}
+
+// getJSONOptions provides a function to parse the JSON options with default
+// value.
+func getJSONOptions(opts ...JSONOptions) JSONOptions {
+	var options JSONOptions
+	for _, opt := range opts {
+		options = opt
+	}
+	if len(options.DateLayouts) == 0 {
+		options.DateLayouts = defaultCSVDateLayouts
+	}
+	return options
+}
+
+// ExportJSON provides a function to export the worksheet or the table to
+// io.Writer as JSON by given worksheet name and JSON options. Each row will
+// be exported as an object keyed by the values of the header row, which is
+// the first non-blank row of the worksheet or the header row of the table,
+// and the blank rows will be skipped. The rows are read by the rows iterator,
+// the numeric and boolean cell values will be exported as JSON numbers and
+// booleans, the numeric cell values with date and time number format will be
+// exported as ISO 8601 date and time strings, and the blank cells will be
+// exported as null. For example, export the table named 'Table1' in the
+// worksheet named 'Sheet1' as newline-delimited JSON:
+//
+//	file, err := os.Create("data.ndjson")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	if err := f.ExportJSON("Sheet1", file, excelize.JSONOptions{
+//	    Table:  "Table1",
+//	    NDJSON: true,
+//	}); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) ExportJSON(sheet string, w io.Writer, opts ...JSONOptions) error {
+	options := getJSONOptions(opts...)
+	area, keys, err := f.getJSONArea(sheet, options.Table)
+	if err != nil {
+		return err
+	}
+	e := jsonExporter{}
+	e.enc = json.NewEncoder(&e.buf)
+	e.enc.SetEscapeHTML(false)
+	if e.cellTyper, err = f.newCellTyper(); err != nil {
+		return err
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	if !options.NDJSON {
+		_ = bw.WriteByte('[')
+	}
+	var count int
+	for rows.Next() && rows.seekRow <= area[3] {
+		if rows.seekRow < area[1] {
+			continue
+		}
+		cells, err := rows.rowCells()
+		if err != nil {
+			_ = rows.Close()
+			return err
+		}
+		values := make([]xlsxC, 0, len(cells))
+		for _, c := range cells {
+			if col, _, _ := CellNameToCoordinates(c.R); col >= area[0] && col <= area[2] {
+				values = append(values, c)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if keys == nil {
+			if options.Table == "" {
+				area[0], _, _ = CellNameToCoordinates(values[0].R)
+			}
+			keys = getJSONKeys(values, area)
+			area[2] = area[0] + len(keys) - 1
+			continue
+		}
+		if count++; count > 1 && !options.NDJSON {
+			_ = bw.WriteByte(',')
+		}
+		if err = e.writeObject(bw, keys, values, area[0]); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		if options.NDJSON {
+			_ = bw.WriteByte('\n')
+		}
+	}
+	if err = rows.Close(); err != nil {
+		return err
+	}
+	if !options.NDJSON {
+		_, _ = bw.WriteString("]\n")
+	}
+	return bw.Flush()
+}
+
+// getJSONArea provides a function to get the coordinates of the area to be
+// exported by given worksheet name and table name. It returns the object
+// keys if the table without header row.
+func (f *File) getJSONArea(sheet, table string) ([]int, []string, error) {
+	area := []int{1, 1, MaxColumns, TotalRows}
+	if table == "" {
+		return area, nil, checkSheetName(sheet)
+	}
+	tables, err := f.GetTables(sheet)
+	if err != nil {
+		return area, nil, err
+	}
+	for _, tbl := range tables {
+		if tbl.Name != table {
+			continue
+		}
+		if area, err = rangeRefToCoordinates(tbl.Range); err != nil {
+			return area, nil, err
+		}
+		_ = sortCoordinates(area)
+		content, ok := f.Pkg.Load(tbl.tableXML)
+		if !ok {
+			return area, nil, err
+		}
+		var t xlsxTable
+		if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte)))).
+			Decode(&t); err != nil && err != io.EOF {
+			return area, nil, err
+		}
+		if t.HeaderRowCount == nil || *t.HeaderRowCount != 0 || t.TableColumns == nil {
+			return area, nil, nil
+		}
+		var keys []string
+		for _, col := range t.TableColumns.TableColumn {
+			keys = append(keys, col.Name)
+		}
+		area[2] = area[0] + len(keys) - 1
+		return area, keys, nil
+	}
+	return area, nil, newNoExistTableError(table)
+}
+
+// getJSONKeys returns the object keys by given cells of the header row and
+// the area to be exported, the column name will be used as the key for the
+// blank header cell, and the duplicate keys will be suffixed with the
+// sequence number.
+func getJSONKeys(cells []xlsxC, area []int) []string {
+	last, _, _ := CellNameToCoordinates(cells[len(cells)-1].R)
+	keys := make([]string, last-area[0]+1)
+	for _, c := range cells {
+		col, _, _ := CellNameToCoordinates(c.R)
+		keys[col-area[0]] = c.V
+	}
+	exists := make(map[string]int)
+	for i := range keys {
+		if keys[i] == "" {
+			keys[i], _ = ColumnNumberToName(area[0] + i)
+		}
+		if exists[keys[i]]++; exists[keys[i]] > 1 {
+			keys[i] += "_" + strconv.Itoa(exists[keys[i]])
+		}
+	}
+	return keys
+}
+
+// writeObject provides a function to write the JSON object of the row by
+// given object keys, cells of the row and the column number of the first
+// key.
+func (e *jsonExporter) writeObject(bw *bufio.Writer, keys []string, cells []xlsxC, firstCol int) error {
+	values := make([]interface{}, len(keys))
+	for _, c := range cells {
+		col, _, _ := CellNameToCoordinates(c.R)
+		value, err := e.value(&c)
+		if err != nil {
+			return err
+		}
+		values[col-firstCol] = value
+	}
+	e.buf.Reset()
+	e.buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		_ = e.enc.Encode(key)
+		e.buf.Truncate(e.buf.Len() - 1)
+		e.buf.WriteByte(':')
+		if err := e.enc.Encode(values[i]); err != nil {
+			return err
+		}
+		e.buf.Truncate(e.buf.Len() - 1)
+	}
+	e.buf.WriteByte('}')
+	_, err := bw.Write(e.buf.Bytes())
+	return err
+}
+
+// value returns the JSON value of the cell by the cell type and the number
+// format of the cell, the date and time will be formatted in ISO 8601.
+func (e *jsonExporter) value(c *xlsxC) (interface{}, error) {
+	value, layout, err := e.typedValue(c)
+	if t, ok := value.(time.Time); ok {
+		return t.Format(layout), err
+	}
+	return value, err
+}
+
+// newCellTyper provides a function to create the cell value converter with
+// the date system of the workbook.
+func (f *File) newCellTyper() (cellTyper, error) {
+	ct := cellTyper{f: f, dateLayouts: make(map[int]string)}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return ct, err
+	}
+	if wb.WorkbookPr != nil {
+		ct.date1904 = wb.WorkbookPr.Date1904
+	}
+	return ct, err
+}
+
+// typedValue returns the typed value of the cell with the raw value by the
+// cell type and the number format of the cell. The numbers are returned as
+// float64, and the numbers in date or time number format are returned as
+// time.Time with the Go time layout of the number format.
+func (e *cellTyper) typedValue(c *xlsxC) (interface{}, string, error) {
+	if c.V == "" {
+		return nil, "", nil
+	}
+	switch c.T {
+	case "b":
+		return c.V == "1", "", nil
+	case "s", "str", "inlineStr", "e", "d":
+		return c.V, "", nil
+	}
+	n, err := strconv.ParseFloat(c.V, 64)
+	if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+		return c.V, "", nil
+	}
+	layout, err := e.dateLayout(c.S)
+	if layout != "" {
+		return timeFromExcelTime(n, e.date1904), layout, err
+	}
+	return n, layout, err
+}
+
+// dateLayout returns the Go time layout of the ISO 8601 date, time or date
+// time by given style ID, it returns empty string if the number format of the
+// style is not a date or time number format.
+func (e *cellTyper) dateLayout(styleID int) (string, error) {
+	if layout, ok := e.dateLayouts[styleID]; ok {
+		return layout, nil
+	}
+	styleSheet, err := e.f.stylesReader()
+	if err != nil || styleSheet.CellXfs == nil || styleID <= 0 || styleID >= len(styleSheet.CellXfs.Xf) {
+		return "", err
+	}
+	var numFmtID int
+	if styleSheet.CellXfs.Xf[styleID].NumFmtID != nil {
+		numFmtID = *styleSheet.CellXfs.Xf[styleID].NumFmtID
+	}
+	code, ok := styleSheet.getCustomNumFmtCode(numFmtID)
+	if !ok {
+		code, _ = e.f.getBuiltInNumFmtCode(numFmtID)
+	}
+	var hasDate, hasTime bool
+	p := nfp.NumberFormatParser()
+	if sections := p.Parse(code); len(sections) > 0 {
+		tokens := sections[0].Items
+		for i, token := range tokens {
+			if token.TType != nfp.TokenTypeDateTimes {
+				continue
+			}
+			if _, isDate := odsDateTimeElement(tokens, i); isDate {
+				hasDate = true
+				continue
+			}
+			hasTime = hasTime || strings.ContainsAny(strings.ToLower(token.TValue[:1]), "hms")
+		}
+	}
+	layout := map[[2]bool]string{
+		{true, true}: "2006-01-02T15:04:05", {true, false}: "2006-01-02", {false, true}: "15:04:05",
+	}[[2]bool{hasDate, hasTime}]
+	e.dateLayouts[styleID] = layout
+	return layout, err
+}
+
+// ImportJSON provides a function to import the JSON data from io.Reader into
+// the worksheet by given worksheet name and JSON options. The JSON data must
+// be an array of objects or the newline-delimited objects. The worksheet will
+// be created if it doesn't exist, and the data will be written by the stream
+// writer, so the existing data of the worksheet will be overwritten, and the
+// same limitations of the stream writer apply to the worksheet. The keys of
+// the objects will be written as the header row in order of first
+// appearance, and each object will be written as a row. The numbers,
+// booleans and null will be written as numeric, boolean and blank cells, the
+// strings which match the date layouts will be written as date cells, and
+// the nested objects and arrays will be written as JSON strings. For
+// example, import the newline-delimited JSON into a worksheet named 'Sheet1':
+//
+//	file, err := os.Open("data.ndjson")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	if err := f.ImportJSON("Sheet1", file); err != nil {
+//	    fmt.Println(err)
+//	}
+//
+// The JSON data will be read twice, it is stored in the memory buffer and
+// the system temporary file on the first reading for collecting the keys of
+// the objects.
+func (f *File) ImportJSON(sheet string, r io.Reader, opts ...JSONOptions) error {
+	options := getJSONOptions(opts...)
+	if err := checkSheetName(sheet); err != nil {
+		return err
+	}
+	var (
+		spool   bufferedWriter
+		keys    []string
+		columns = make(map[string]int)
+	)
+	defer spool.Close()
+	if err := readJSONObjects(io.TeeReader(r, &spool), func(names []string, _ []json.RawMessage) error {
+		for _, name := range names {
+			if _, ok := columns[name]; !ok {
+				columns[name], keys = len(keys), append(keys, name)
+			}
+		}
+		return spool.Sync()
+	}); err != nil {
+		return err
+	}
+	if f.getSheetID(sheet) == -1 {
+		if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+	}
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+	if err = importJSONRows(sw, &spool, keys, columns, options); err == nil {
+		err = sw.Flush()
+	}
+	if err != nil {
+		sw.discard()
+	}
+	return err
+}
+
+// importJSONRows provides a function to write the header row and the JSON
+// objects stored in the spool into the worksheet by given stream writer,
+// keys, column indexes of the keys and JSON options.
+func importJSONRows(sw *StreamWriter, spool *bufferedWriter, keys []string, columns map[string]int, options JSONOptions) error {
+	if len(keys) > 0 {
+		header := make([]interface{}, len(keys))
+		for i, key := range keys {
+			header[i] = key
+		}
+		if err := sw.SetRow("A1", header); err != nil {
+			return err
+		}
+	}
+	data, err := spool.Reader()
+	if err != nil {
+		return err
+	}
+	inferrer, row := csvTypeInferrer{f: sw.file, layouts: options.DateLayouts}, 1
+	return readJSONObjects(data, func(names []string, raws []json.RawMessage) error {
+		values := make([]interface{}, len(keys))
+		for i, name := range names {
+			value, err := jsonCellValue(raws[i], &inferrer)
+			if err != nil {
+				return err
+			}
+			values[columns[name]] = value
+		}
+		row++
+		cell, _ := CoordinatesToCellName(1, row)
+		return sw.SetRow(cell, values)
+	})
+}
+
+// readJSONObjects provides a function to read the JSON array of objects or
+// the newline-delimited objects from io.Reader, and call the given function
+// with the keys and the raw values of each object.
+func readJSONObjects(r io.Reader, fn func(keys []string, values []json.RawMessage) error) error {
+	dec := json.NewDecoder(r)
+	token, err := dec.Token()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	array := token == json.Delim('[')
+	for {
+		if array {
+			if !dec.More() {
+				_, err = dec.Token()
+				return err
+			}
+			if token, err = dec.Token(); err != nil {
+				return err
+			}
+		}
+		if token != json.Delim('{') {
+			return ErrJSONObject
+		}
+		var (
+			keys   []string
+			values []json.RawMessage
+		)
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			var value json.RawMessage
+			if err = dec.Decode(&value); err != nil {
+				return err
+			}
+			name, _ := key.(string)
+			keys, values = append(keys, name), append(values, value)
+		}
+		if _, err = dec.Token(); err != nil {
+			return err
+		}
+		if err = fn(keys, values); err != nil {
+			return err
+		}
+		if !array {
+			if token, err = dec.Token(); err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// jsonCellValue provides a function to convert the raw value of the object
+// member to the cell value.
+func jsonCellValue(raw json.RawMessage, inferrer *csvTypeInferrer) (interface{}, error) {
+	switch raw[0] {
+	case '{', '[':
+		return string(raw), nil
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		return inferrer.inferDate(s)
+	case 't', 'f':
+		return raw[0] == 't', nil
+	case 'n':
+		return nil, nil
+	}
+	if n, err := strconv.ParseInt(string(raw), 10, 64); err == nil {
+		return n, nil
+	}
+	return strconv.ParseFloat(string(raw), 64)
+}
@@ -0,0 +1,126 @@
+package excelize
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportJSON(t *testing.T) {
+	f := NewFile()
+	for cell, value := range map[string]interface{}{
+		"B2": "Name", "C2": "Amount", "D2": "Active", "F2": "Name",
+		"B3": "Apple", "C3": 12.5, "D3": true, "E3": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), "F3": "<a>",
+		"B5": "Banana", "C5": 0.5, "D5": false, "E5": time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "G3", "C3*2"))
+	timeStyle, err := f.NewStyle(&Style{NumFmt: 21})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "C5", "C5", timeStyle))
+
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf))
+	assert.Equal(t, `[{"Name":"Apple","Amount":12.5,"Active":true,"E":"2024-01-02T00:00:00","Name_2":"<a>"},`+
+		`{"Name":"Banana","Amount":"12:00:00","Active":false,"E":"2024-01-02T15:04:05","Name_2":null}]`+"\n", buf.String())
+
+	// Test export JSON of the table with NDJSON
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "B2:D5", Name: "Table1"}))
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Table: "Table1", NDJSON: true}))
+	assert.Equal(t, "{\"Name\":\"Apple\",\"Amount\":12.5,\"Active\":true}\n"+
+		"{\"Name\":\"Banana\",\"Amount\":\"12:00:00\",\"Active\":false}\n", buf.String())
+
+	// Test export JSON of the table without header row
+	showHeaderRow := false
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "B7:C8", Name: "Table2", ShowHeaderRow: &showHeaderRow}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B8", &[]interface{}{1, 2}))
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Table: "Table2"}))
+	assert.Equal(t, `[{"Column1":1,"Column2":2}]`+"\n", buf.String())
+
+	// Test export JSON on the empty worksheet
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet2", &buf))
+	assert.Equal(t, "[]\n", buf.String())
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet2", &buf, JSONOptions{NDJSON: true}))
+	assert.Empty(t, buf.String())
+
+	// Test export JSON with invalid options
+	assert.EqualError(t, f.ExportJSON("SheetN", &buf), "sheet SheetN does not exist")
+	assert.Equal(t, ErrSheetNameInvalid, f.ExportJSON("Sheet:1", &buf))
+	assert.EqualError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Table: "TableN"}), "table TableN does not exist")
+	assert.NoError(t, f.Close())
+
+	// Test export JSON with unsupported charset workbook
+	f = NewFile()
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ExportJSON("Sheet1", &buf), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestImportJSON(t *testing.T) {
+	f := NewFile()
+	data := `[{"Name":"Apple","Amount":12.5,"Active":true,"Date":"2024-01-02"},
+		{"Name":"Banana","Count":7,"Tags":["a","b"],"Extra":{"k":null},"Active":null}]`
+	assert.NoError(t, f.ImportJSON("Sheet2", strings.NewReader(data)))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestImportJSON.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err := OpenFile(filepath.Join("test", "TestImportJSON.xlsx"))
+	assert.NoError(t, err)
+	rows, err := f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Name", "Amount", "Active", "Date", "Count", "Tags", "Extra"},
+		{"Apple", "12.5", "TRUE", "01-02-24"},
+		{"Banana", "", "", "", "7", `["a","b"]`, `{"k":null}`},
+	}, rows)
+	// Test round trip of the imported data
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportJSON("Sheet2", &buf, JSONOptions{NDJSON: true}))
+	assert.Equal(t, `{"Name":"Apple","Amount":12.5,"Active":true,"Date":"2024-01-02","Count":null,"Tags":null,"Extra":null}`+"\n"+
+		`{"Name":"Banana","Amount":null,"Active":null,"Date":null,"Count":7,"Tags":"[\"a\",\"b\"]","Extra":"{\"k\":null}"}`+"\n", buf.String())
+	assert.NoError(t, f.Close())
+
+	// Test import newline-delimited JSON
+	f = NewFile()
+	assert.NoError(t, f.ImportJSON("Sheet1", strings.NewReader("{\"A\":1e3,\"B\":\"x\"}\n{\"A\":2}\n")))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"A", "B"}, {"1000", "x"}, {"2"}}, rows)
+	// Test import JSON into the worksheet which contains data
+	assert.NoError(t, f.SetCellValue("Sheet1", "C5", "C"))
+	assert.NoError(t, f.ImportJSON("Sheet1", strings.NewReader(`[{"B":"y"}]`)))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"B"}, {"y"}}, rows)
+	// Test import empty JSON
+	assert.NoError(t, f.ImportJSON("Sheet1", strings.NewReader("")))
+	assert.NoError(t, f.ImportJSON("Sheet1", strings.NewReader("[]")))
+	// Test import JSON with invalid sheet name
+	assert.Equal(t, ErrSheetNameInvalid, f.ImportJSON("Sheet:1", strings.NewReader("[]")))
+	// Test import JSON with invalid JSON data
+	for _, data := range []string{"[1]", "1", `{"A":1}2`} {
+		assert.Equal(t, ErrJSONObject, f.ImportJSON("Sheet1", strings.NewReader(data)), data)
+	}
+	for _, data := range []string{"]", `[{"A":}]`, `[{"A":1`, `[{"A":1}`, `{"A":1}}`} {
+		assert.Error(t, f.ImportJSON("Sheet1", strings.NewReader(data)), data)
+	}
+	// Test the stream writer has been discarded on error
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ImportJSON("Sheet1", strings.NewReader(`[{"A":"2024-01-02"}]`), JSONOptions{DateLayouts: []string{"2006-01-02"}}), "XML syntax error on line 1: invalid UTF-8")
+	_, ok := f.streams.Load("xl/worksheets/sheet1.xml")
+	assert.False(t, ok)
+	assert.NoError(t, f.Close())
+}
@@ -17,10 +17,12 @@ import (
 	"container/list"
 	"encoding/xml"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"math"
 	"math/big"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -35,8 +37,10 @@ func (f *File) ReadZipReader(r *zip.Reader) (map[string][]byte, int, error) {
 			"xl/sharedstrings.xml": defaultXMLPathSharedStrings,
 		}
 		fileList   = make(map[string][]byte, len(r.File))
+		sources    = make(map[string]*sourcePart)
 		worksheets int
 		unzipSize  int64
+		strict     bool
 	)
 	for _, v := range r.File {
 		fileSize := v.FileInfo().Size()
@@ -54,6 +58,7 @@ func (f *File) ReadZipReader(r *zip.Reader) (map[string][]byte, int, error) {
 				f.tempFiles.Store(fileName, tempFile)
 			}
 			if err == nil {
+				sources[fileName] = &sourcePart{file: v, temp: tempFile}
 				continue
 			}
 		}
@@ -65,6 +70,7 @@ func (f *File) ReadZipReader(r *zip.Reader) (map[string][]byte, int, error) {
 					f.tempFiles.Store(fileName, tempFile)
 				}
 				if err == nil {
+					sources[fileName] = &sourcePart{file: v, temp: tempFile}
 					continue
 				}
 			}
@@ -72,6 +78,22 @@ func (f *File) ReadZipReader(r *zip.Reader) (map[string][]byte, int, error) {
 		if fileList[fileName], err = readFile(v); err != nil {
 			return nil, 0, err
 		}
+		content := fileList[fileName]
+		if ext := strings.ToLower(filepath.Ext(fileName)); ext == ".xml" || ext == ".rels" {
+			fileList[fileName] = namespaceStrictToTransitional(fileList[fileName])
+		}
+		if !sameBytes(content, fileList[fileName]) {
+			strict = true
+			continue
+		}
+		sources[fileName] = &sourcePart{file: v, content: content}
+	}
+	if f.options.IncrementalSave {
+		for name, part := range sources {
+			if !strict || part.temp == "" {
+				f.sourceParts.Store(name, part)
+			}
+		}
 	}
 	return fileList, worksheets, nil
 }
@@ -101,13 +123,15 @@ func (f *File) readXML(name string) []byte {
 	if content, _ := f.Pkg.Load(name); content != nil {
 		return content.([]byte)
 	}
-	if content, ok := f.streams[name]; ok {
-		return content.rawData.buf.Bytes()
+	if sw, ok := f.streams.Load(name); ok && sw.(*StreamWriter).rawData.out == nil {
+		return sw.(*StreamWriter).rawData.buf.Bytes()
 	}
 	return []byte{}
 }
 
-// readBytes read file as bytes by given path.
+// readBytes read file as bytes by given path. The worksheet read from the
+// temporary file will not be cached in memory when the worksheet memory limit
+// was set.
 func (f *File) readBytes(name string) []byte {
 	content := f.readXML(name)
 	if len(content) != 0 {
@@ -118,8 +142,16 @@ func (f *File) readBytes(name string) []byte {
 		return content
 	}
 	content, _ = io.ReadAll(file)
-	f.Pkg.Store(name, content)
 	_ = file.Close()
+	if f.options != nil && f.options.WorksheetMemoryLimit > 0 && strings.HasPrefix(name, "xl/worksheets/") {
+		return content
+	}
+	f.Pkg.Store(name, content)
+	if part, ok := f.sourceParts.Load(name); ok {
+		if temp, _ := f.tempFiles.Load(name); temp == part.(*sourcePart).temp {
+			f.sourceParts.Store(name, &sourcePart{file: part.(*sourcePart).file, content: content, temp: part.(*sourcePart).temp})
+		}
+	}
 	return content
 }
 
@@ -139,6 +171,47 @@ func (f *File) saveFileList(name string, content []byte) {
 	f.Pkg.Store(name, append([]byte(xml.Header), content...))
 }
 
+// trackPart provides a function to record the fingerprint of the decoded part
+// by given path for saving the spreadsheet incrementally.
+func (f *File) trackPart(path string, v interface{}) {
+	if f.options != nil && f.options.IncrementalSave {
+		output, _ := xml.Marshal(v)
+		f.partHashes.Store(path, partFingerprint(output))
+	}
+}
+
+// partModified provides a function to check if the serialized part by given
+// path has been changed since it was decoded or last saved. The parts which
+// are not tracked are always considered modified.
+func (f *File) partModified(path string, output []byte) bool {
+	if f.options == nil || !f.options.IncrementalSave {
+		return true
+	}
+	if _, ok := f.Pkg.Load(path); !ok {
+		return true
+	}
+	hash := partFingerprint(output)
+	if prev, ok := f.partHashes.Load(path); ok && prev.(uint64) == hash {
+		return false
+	}
+	f.partHashes.Store(path, hash)
+	return true
+}
+
+// partFingerprint returns the fingerprint of the serialized part for detecting
+// if the part has been modified.
+func partFingerprint(content []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(content)
+	return h.Sum64()
+}
+
+// sameBytes returns if the two byte slices share the same underlying array
+// with the same length.
+func sameBytes(a, b []byte) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
 // Read file content as string in an archive file.
 func readFile(file *zip.File) ([]byte, error) {
 	rc, err := file.Open()
@@ -555,23 +628,41 @@ func (ext *xlsxExt) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
+// strictNameSpaces defined the Strict and Transitional namespaces pairs. The
+// specific relationship types are placed before the relationships namespace,
+// which is the prefix of them.
+var strictNameSpaces = [][2]string{
+	{StrictNameSpaceDocumentPropertiesVariantTypes, NameSpaceDocumentPropertiesVariantTypes.Value},
+	{StrictNameSpaceDrawingMLChart, NameSpaceDrawingMLChart.Value},
+	{StrictNameSpaceDrawingMLMain, NameSpaceDrawingMLMain},
+	{StrictNameSpaceDrawingMLSpreadSheet, NameSpaceDrawingMLSpreadSheet.Value},
+	{StrictNameSpaceExtendedProperties, NameSpaceExtendedProperties},
+	{StrictNameSpaceSpreadSheet, NameSpaceSpreadSheet.Value},
+	{StrictSourceRelationshipChart, SourceRelationshipChart},
+	{StrictSourceRelationshipComments, SourceRelationshipComments},
+	{StrictSourceRelationshipExtendProperties, SourceRelationshipExtendProperties},
+	{StrictSourceRelationshipImage, SourceRelationshipImage},
+	{StrictSourceRelationshipOfficeDocument, SourceRelationshipOfficeDocument},
+	{StrictSourceRelationship, SourceRelationship.Value},
+}
+
 // namespaceStrictToTransitional provides a method to convert Strict and
 // Transitional namespaces.
 func namespaceStrictToTransitional(content []byte) []byte {
-	namespaceTranslationDic := map[string]string{
-		StrictNameSpaceDocumentPropertiesVariantTypes: NameSpaceDocumentPropertiesVariantTypes.Value,
-		StrictNameSpaceDrawingMLMain:                  NameSpaceDrawingMLMain,
-		StrictNameSpaceExtendedProperties:             NameSpaceExtendedProperties,
-		StrictNameSpaceSpreadSheet:                    NameSpaceSpreadSheet.Value,
-		StrictSourceRelationship:                      SourceRelationship.Value,
-		StrictSourceRelationshipChart:                 SourceRelationshipChart,
-		StrictSourceRelationshipComments:              SourceRelationshipComments,
-		StrictSourceRelationshipExtendProperties:      SourceRelationshipExtendProperties,
-		StrictSourceRelationshipImage:                 SourceRelationshipImage,
-		StrictSourceRelationshipOfficeDocument:        SourceRelationshipOfficeDocument,
-	}
-	for s, n := range namespaceTranslationDic {
-		content = bytesReplace(content, []byte(s), []byte(n), -1)
+	if !bytes.Contains(content, []byte("http://purl.oclc.org/ooxml/")) {
+		return content
+	}
+	for _, ns := range strictNameSpaces {
+		content = bytesReplace(content, []byte(ns[0]), []byte(ns[1]), -1)
+	}
+	return content
+}
+
+// namespaceTransitionalToStrict provides a method to convert Transitional and
+// Strict namespaces.
+func namespaceTransitionalToStrict(content []byte) []byte {
+	for _, ns := range strictNameSpaces {
+		content = bytes.ReplaceAll(content, []byte(ns[1]), []byte(ns[0]))
 	}
 	return content
 }
@@ -390,3 +390,14 @@ func TestUnzipToTemp(t *testing.T) {
 	_, err = f.unzipToTemp(z.File[0])
 	assert.EqualError(t, err, "EOF")
 }
+
+func TestNamespaceStrictToTransitional(t *testing.T) {
+	for strict, transitional := range map[string]string{
+		StrictSourceRelationshipExtendProperties: SourceRelationshipExtendProperties,
+		StrictSourceRelationship + "/worksheet":  SourceRelationshipWorkSheet,
+		StrictNameSpaceSpreadSheet:               NameSpaceSpreadSheet.Value,
+	} {
+		assert.Equal(t, transitional, string(namespaceStrictToTransitional([]byte(strict))))
+		assert.Equal(t, strict, string(namespaceTransitionalToStrict([]byte(transitional))))
+	}
+}
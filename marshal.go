@@ -0,0 +1,432 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"encoding"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// structField defined the struct field which mapped to the worksheet column.
+type structField struct {
+	header string
+	index  []int
+}
+
+// getStructFields provides a function to get the exported fields of the given
+// struct type with the header names. The header name of the field is the
+// value of the "xlsx" tag, or the field name if the tag is not present, and
+// the fields with the "-" tag will be skipped. The fields of the embedded
+// structs and the embedded struct pointers will be promoted.
+func getStructFields(typ reflect.Type, index []int) []structField {
+	var fields []structField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, hasTag := field.Tag.Lookup("xlsx")
+		if tag == "-" {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+		if field.Anonymous && !hasTag {
+			if typ := field.Type; typ.Kind() == reflect.Struct && typ != timeType {
+				fields = append(fields, getStructFields(typ, fieldIndex)...)
+				continue
+			}
+			if typ := field.Type; typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Struct && typ.Elem() != timeType {
+				fields = append(fields, getStructFields(typ.Elem(), fieldIndex)...)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+		fields = append(fields, structField{header: tag, index: fieldIndex})
+	}
+	return fields
+}
+
+// structFieldByIndex provides a function to get the nested field of the
+// struct value by given index. The nil embedded struct pointers on the path
+// will be allocated if alloc is true, otherwise or the pointer to unexported
+// struct can't be allocated, returns false.
+func structFieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return v, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v, true
+}
+
+// getSliceElemType provides a function to get the struct type of the given
+// slice type elements, the elements could be the structs or the pointers to
+// the structs.
+func getSliceElemType(typ reflect.Type) (reflect.Type, bool) {
+	elem := typ.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem, elem.Kind() == reflect.Struct
+}
+
+// UnmarshalRows provides a function to read the worksheet by the rows
+// iterator, and store the rows into the slice of the structs which pointed
+// by v. The first row of the worksheet is the header row, and the column will
+// be mapped to the struct field by the header name with the "xlsx" tag, or by
+// the field name if the tag is not present. The fields with the "-" tag will
+// be skipped. For example, read the worksheet named 'Sheet1' into the slice
+// of the structs:
+//
+//	type Employee struct {
+//	    Name     string     `xlsx:"Name"`
+//	    Age      int        `xlsx:"Age"`
+//	    Salary   *float64   `xlsx:"Salary"`
+//	    Hired    time.Time  `xlsx:"Hire Date"`
+//	    Internal string     `xlsx:"-"`
+//	}
+//	var employees []Employee
+//	if err := f.UnmarshalRows("Sheet1", &employees); err != nil {
+//	    fmt.Println(err)
+//	}
+//
+// Supported field types are string, bool, the signed and unsigned integers,
+// float32, float64, time.Time, time.Duration, the types which implement the
+// encoding.TextUnmarshaler interface, and the pointers to them. The blank
+// cells will be stored as nil pointers or the zero values, and the blank rows
+// will be skipped. The time.Duration values will be rounded to the nearest
+// second, as the durations are stored in single precision.
+func (f *File) UnmarshalRows(sheet string, v interface{}) error {
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	if err = rows.Unmarshal(v); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	return rows.Close()
+}
+
+// Unmarshal provides a function to read the remaining rows of the rows
+// iterator and store them into the slice of the structs which pointed by v.
+// The next row of the iterator will be used as the header row. Read the
+// UnmarshalRows function for the mapping of the columns and struct fields.
+func (rows *Rows) Unmarshal(v interface{}) error {
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Slice {
+		return ErrUnmarshalRowsTarget
+	}
+	slice := ptr.Elem()
+	elemType, ok := getSliceElemType(slice.Type())
+	if !ok {
+		return ErrUnmarshalRowsTarget
+	}
+	var date1904 bool
+	wb, err := rows.f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if wb != nil && wb.WorkbookPr != nil {
+		date1904 = wb.WorkbookPr.Date1904
+	}
+	var (
+		fields  = getStructFields(elemType, nil)
+		columns []*structField
+		opts    = Options{RawCellValue: true}
+	)
+	if rows.Next() {
+		header, err := rows.Columns(opts)
+		if err != nil {
+			return err
+		}
+		columns = make([]*structField, len(header))
+		for i, name := range header {
+			for j := range fields {
+				if fields[j].header == strings.TrimSpace(name) {
+					columns[i] = &fields[j]
+					break
+				}
+			}
+		}
+	}
+	for rows.Next() {
+		row, err := rows.Columns(opts)
+		if err != nil {
+			return err
+		}
+		if len(strings.Join(row, "")) == 0 {
+			continue
+		}
+		elem := reflect.New(elemType).Elem()
+		for i, value := range row {
+			if i >= len(columns) || columns[i] == nil {
+				continue
+			}
+			field, ok := structFieldByIndex(elem, columns[i].index, true)
+			if !ok {
+				continue
+			}
+			if err = setStructField(field, value, date1904); err != nil {
+				cell, _ := CoordinatesToCellName(i+1, rows.seekRow)
+				return newUnmarshalCellError(cell, columns[i].header, err)
+			}
+		}
+		if slice.Type().Elem().Kind() == reflect.Ptr {
+			elem = elem.Addr()
+		}
+		slice.Set(reflect.Append(slice, elem))
+	}
+	return rows.Error()
+}
+
+// setStructField provides a function to convert the raw cell value to the
+// type of the given struct field, and set the field value. The blank cell
+// will be stored as a nil pointer or the zero value.
+func setStructField(field reflect.Value, value string, date1904 bool) error {
+	if value == "" {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		if err := setStructField(ptr.Elem(), value, date1904); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+	switch field.Type() {
+	case timeType:
+		if num, err := strconv.ParseFloat(value, 64); err == nil {
+			field.Set(reflect.ValueOf(timeFromExcelTime(num, date1904)))
+			return nil
+		}
+		for _, layout := range append([]string{time.RFC3339}, defaultCSVDateLayouts...) {
+			if t, err := time.Parse(layout, value); err == nil {
+				field.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return ErrParameterInvalid
+	case durationType:
+		num, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(time.Duration(num * float64(24*time.Hour)).Round(time.Second)))
+		return nil
+	}
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		num, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		if num != math.Trunc(num) || field.OverflowInt(int64(num)) {
+			return ErrParameterInvalid
+		}
+		field.SetInt(int64(num))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		num, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		if num < 0 || num != math.Trunc(num) || field.OverflowUint(uint64(num)) {
+			return ErrParameterInvalid
+		}
+		field.SetUint(uint64(num))
+	case reflect.Float32, reflect.Float64:
+		num, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(num)
+	default:
+		return newUnsupportedFieldTypeError(field.Type().String())
+	}
+	return nil
+}
+
+// MarshalRows provides a function to write the slice of the structs or the
+// pointers to the structs into the worksheet. The header row will be written
+// in the first row of the worksheet, and each element of the slice will be
+// written in the subsequent rows. Read the UnmarshalRows function for the
+// mapping of the columns and struct fields. For example:
+//
+//	employees := []Employee{
+//	    {Name: "Alice", Age: 30, Hired: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+//	    {Name: "Bob", Age: 25, Hired: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)},
+//	}
+//	if err := f.MarshalRows("Sheet1", employees); err != nil {
+//	    fmt.Println(err)
+//	}
+//
+// The nil pointers will be written as blank cells, and the nil elements of
+// the slice will be skipped without leaving blank rows.
+func (f *File) MarshalRows(sheet string, v interface{}) error {
+	return marshalRows(v, func(row int, values []interface{}) error {
+		cell, err := CoordinatesToCellName(1, row)
+		if err != nil {
+			return err
+		}
+		return f.SetSheetRow(sheet, cell, &values)
+	})
+}
+
+// MarshalRows provides a function to write the slice of the structs or the
+// pointers to the structs into the worksheet by the stream writer. The header
+// row will be written in the row of the given cell reference, and each
+// element of the slice will be written in the subsequent rows. The row
+// options will be applied to each row. Read the UnmarshalRows function for
+// the mapping of the columns and struct fields. For example:
+//
+//	sw, err := f.NewStreamWriter("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := sw.MarshalRows("A1", employees); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := sw.Flush(); err != nil {
+//	    fmt.Println(err)
+//	}
+func (sw *StreamWriter) MarshalRows(cell string, v interface{}, opts ...RowOpts) error {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+	return marshalRows(v, func(offset int, values []interface{}) error {
+		cell, err := CoordinatesToCellName(col, row+offset-1)
+		if err != nil {
+			return err
+		}
+		return sw.SetRow(cell, values, opts...)
+	})
+}
+
+// marshalRows provides a function to convert the slice of the structs into
+// the rows of the cell values, and write each row by the given function with
+// the row number starting from 1.
+func marshalRows(v interface{}, setRow func(row int, values []interface{}) error) error {
+	slice := reflect.ValueOf(v)
+	for slice.Kind() == reflect.Ptr && !slice.IsNil() {
+		slice = slice.Elem()
+	}
+	if slice.Kind() != reflect.Slice && slice.Kind() != reflect.Array {
+		return ErrMarshalRowsSource
+	}
+	elemType, ok := getSliceElemType(slice.Type())
+	if !ok {
+		return ErrMarshalRowsSource
+	}
+	fields := getStructFields(elemType, nil)
+	header := make([]interface{}, len(fields))
+	for i, field := range fields {
+		header[i] = field.header
+	}
+	if err := setRow(1, header); err != nil {
+		return err
+	}
+	row := 1
+	for i := 0; i < slice.Len(); i++ {
+		elem := slice.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				continue
+			}
+			elem = elem.Elem()
+		}
+		values := make([]interface{}, len(fields))
+		for j, field := range fields {
+			fieldValue, ok := structFieldByIndex(elem, field.index, false)
+			if !ok {
+				continue
+			}
+			value, err := getStructFieldValue(fieldValue)
+			if err != nil {
+				return newMarshalFieldError(field.header, err)
+			}
+			values[j] = value
+		}
+		row++
+		if err := setRow(row, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getStructFieldValue provides a function to convert the struct field value
+// to the cell value, the nil pointer will be converted to nil.
+func getStructFieldValue(field reflect.Value) (interface{}, error) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil, nil
+		}
+		field = field.Elem()
+	}
+	switch field.Type() {
+	case timeType, durationType:
+		return field.Interface(), nil
+	}
+	if field.Type().Implements(textMarshalerType) {
+		text, err := field.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	switch field.Kind() {
+	case reflect.String:
+		return field.String(), nil
+	case reflect.Bool:
+		return field.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), nil
+	}
+	return nil, newUnsupportedFieldTypeError(field.Type().String())
+}
@@ -0,0 +1,181 @@
+package excelize
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMarshalLevel int
+
+func (l testMarshalLevel) MarshalText() ([]byte, error) {
+	return []byte(strings.Repeat("*", int(l))), nil
+}
+
+func (l *testMarshalLevel) UnmarshalText(text []byte) error {
+	if strings.Trim(string(text), "*") != "" {
+		return ErrParameterInvalid
+	}
+	*l = testMarshalLevel(len(text))
+	return nil
+}
+
+type testMarshalBase struct {
+	ID uint `xlsx:"ID"`
+}
+
+type testMarshalEmployee struct {
+	testMarshalBase
+	Name     string           `xlsx:"Name"`
+	Age      int8             `xlsx:"Age"`
+	Salary   *float64         `xlsx:"Salary"`
+	Active   bool             `xlsx:"Active"`
+	Hired    time.Time        `xlsx:"Hire Date"`
+	Shift    time.Duration    `xlsx:"Shift"`
+	Level    testMarshalLevel `xlsx:"Level"`
+	Note     *string
+	Internal string `xlsx:"-"`
+	private  string
+}
+
+func TestMarshalRows(t *testing.T) {
+	salary := 1234.5
+	employees := []*testMarshalEmployee{
+		{
+			testMarshalBase: testMarshalBase{ID: 1}, Name: "Alice", Age: 30, Salary: &salary, Active: true,
+			Hired: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), Shift: 8 * time.Hour, Level: 3, Internal: "x", private: "y",
+		},
+		nil,
+		{testMarshalBase: testMarshalBase{ID: 2}, Name: "Bob", Age: -5, Hired: time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	f := NewFile()
+	assert.NoError(t, f.MarshalRows("Sheet1", employees))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"ID", "Name", "Age", "Salary", "Active", "Hire Date", "Shift", "Level", "Note"},
+		{"1", "Alice", "30", "1234.5", "TRUE", "1/2/20 00:00", "08:00:00", "***"},
+		{"2", "Bob", "-5", "", "FALSE", "6/1/21 12:00", "0"},
+	}, rows)
+
+	var result []testMarshalEmployee
+	assert.NoError(t, f.UnmarshalRows("Sheet1", &result))
+	assert.Equal(t, []testMarshalEmployee{
+		{
+			testMarshalBase: testMarshalBase{ID: 1}, Name: "Alice", Age: 30, Salary: &salary, Active: true,
+			Hired: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), Shift: 8 * time.Hour, Level: 3,
+		},
+		{testMarshalBase: testMarshalBase{ID: 2}, Name: "Bob", Age: -5, Hired: time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)},
+	}, result)
+
+	// Test unmarshal rows with the rows iterator, the reordered columns and
+	// the date in text
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetRow("Sheet2", "A1", &[]interface{}{"Note", " Name ", "Unknown", "Hire Date"}))
+	assert.NoError(t, f.SetSheetRow("Sheet2", "A2", &[]interface{}{"Text", "Carol", 1, "2022-03-04"}))
+	r, err := f.Rows("Sheet2")
+	assert.NoError(t, err)
+	var ptrs []*testMarshalEmployee
+	assert.NoError(t, r.Unmarshal(&ptrs))
+	assert.NoError(t, r.Close())
+	note := "Text"
+	assert.Equal(t, []*testMarshalEmployee{{Name: "Carol", Note: &note, Hired: time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC)}}, ptrs)
+
+	// Test unmarshal rows with the invalid cell values
+	for _, c := range []struct {
+		cell, header string
+		value        interface{}
+	}{
+		{"A2", "ID", "x"}, {"A2", "ID", -1}, {"C2", "Age", 1.5}, {"C2", "Age", 128},
+		{"D2", "Salary", "x"}, {"E2", "Active", "x"}, {"F2", "Hire Date", "x"},
+		{"G2", "Shift", "x"}, {"H2", "Level", "x"},
+	} {
+		f := NewFile()
+		assert.NoError(t, f.MarshalRows("Sheet1", employees[:1]))
+		assert.NoError(t, f.SetCellValue("Sheet1", c.cell, c.value))
+		err := f.UnmarshalRows("Sheet1", &result)
+		assert.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), fmt.Sprintf("cannot unmarshal cell %s into field %q", c.cell, c.header)), err.Error())
+	}
+	for _, value := range []interface{}{1.5, 256, -1} {
+		f := NewFile()
+		var v []struct{ Value uint8 }
+		assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Value"))
+		assert.NoError(t, f.SetCellValue("Sheet1", "A2", value))
+		assert.EqualError(t, f.UnmarshalRows("Sheet1", &v), newUnmarshalCellError("A2", "Value", ErrParameterInvalid).Error())
+	}
+	// Test unmarshal rows with the invalid target
+	assert.Equal(t, ErrUnmarshalRowsTarget, f.UnmarshalRows("Sheet1", result))
+	assert.Equal(t, ErrUnmarshalRowsTarget, f.UnmarshalRows("Sheet1", &[]string{}))
+	// Test unmarshal rows with the unsupported field type
+	var unsupported []struct{ Name []byte }
+	assert.EqualError(t, f.UnmarshalRows("Sheet1", &unsupported), newUnmarshalCellError("B2", "Name", newUnsupportedFieldTypeError("[]uint8")).Error())
+	// Test unmarshal rows on not exists worksheet
+	assert.EqualError(t, f.UnmarshalRows("SheetN", &result), "sheet SheetN does not exist")
+	// Test unmarshal rows with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.UnmarshalRows("Sheet1", &result), "XML syntax error on line 1: invalid UTF-8")
+
+	// Test marshal rows with the invalid source
+	assert.Equal(t, ErrMarshalRowsSource, f.MarshalRows("Sheet1", "x"))
+	assert.Equal(t, ErrMarshalRowsSource, f.MarshalRows("Sheet1", []int{1}))
+	// Test marshal rows with the unsupported field type
+	assert.EqualError(t, f.MarshalRows("Sheet1", []struct{ Name []byte }{{}}), newMarshalFieldError("Name", newUnsupportedFieldTypeError("[]uint8")).Error())
+	// Test marshal rows on not exists worksheet
+	assert.EqualError(t, f.MarshalRows("SheetN", employees), "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+
+	// Test marshal and unmarshal rows with the embedded struct pointers
+	type Address struct {
+		City string `xlsx:"City"`
+	}
+	type contact struct {
+		*Address
+		*testMarshalBase
+		Name string
+	}
+	f = NewFile()
+	assert.NoError(t, f.MarshalRows("Sheet1", []contact{
+		{Address: &Address{City: "Paris"}, testMarshalBase: &testMarshalBase{ID: 1}, Name: "Dave"},
+		{Name: "Eve"},
+	}))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"City", "ID", "Name"}, {"Paris", "1", "Dave"}, {"", "", "Eve"}}, rows)
+	var contacts []contact
+	assert.NoError(t, f.UnmarshalRows("Sheet1", &contacts))
+	assert.Equal(t, []contact{{Address: &Address{City: "Paris"}, Name: "Dave"}, {Address: &Address{}, Name: "Eve"}}, contacts)
+	assert.NoError(t, f.Close())
+}
+
+func TestStreamMarshalRows(t *testing.T) {
+	f := NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	salary := 100.25
+	employees := [2]testMarshalEmployee{
+		{Name: "Alice", Salary: &salary, Hired: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{Name: "Bob", Active: true},
+	}
+	assert.NoError(t, sw.MarshalRows("B2", &employees, RowOpts{Height: 20}))
+	assert.NoError(t, sw.Flush())
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "0", "Alice", "0", "100.25", "FALSE", "1/2/20 00:00", "0"}, rows[2])
+	height, err := f.GetRowHeight("Sheet1", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, height)
+
+	// Test stream marshal rows with the invalid cell reference
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), sw.MarshalRows("A", employees))
+	// Test stream marshal rows with the rows number exceeds the limit
+	sw, err = f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, ErrMaxRows, sw.MarshalRows(fmt.Sprintf("A%d", TotalRows), employees))
+	assert.NoError(t, f.Close())
+}
@@ -0,0 +1,1970 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/efp"
+	"github.com/xuri/nfp"
+)
+
+// Source relationship and content type of the OpenDocument Spreadsheet.
+const (
+	ContentTypeODS          = "application/vnd.oasis.opendocument.spreadsheet"
+	NameSpaceODSOffice      = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	NameSpaceODSStyle       = "urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+	NameSpaceODSText        = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	NameSpaceODSTable       = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	NameSpaceODSFo          = "urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"
+	NameSpaceODSNumber      = "urn:oasis:names:tc:opendocument:xmlns:datastyle:1.0"
+	NameSpaceODSSvg         = "urn:oasis:names:tc:opendocument:xmlns:svg-compatible:1.0"
+	NameSpaceODSOf          = "urn:oasis:names:tc:opendocument:xmlns:of:1.2"
+	NameSpaceODSManifest    = "urn:oasis:names:tc:opendocument:xmlns:manifest:1.0"
+	odsNameSpaceDeclaration = ` xmlns:office="` + NameSpaceODSOffice +
+		`" xmlns:style="` + NameSpaceODSStyle +
+		`" xmlns:text="` + NameSpaceODSText +
+		`" xmlns:table="` + NameSpaceODSTable +
+		`" xmlns:fo="` + NameSpaceODSFo +
+		`" xmlns:number="` + NameSpaceODSNumber +
+		`" xmlns:svg="` + NameSpaceODSSvg +
+		`" xmlns:of="` + NameSpaceODSOf + `" office:version="1.3"`
+)
+
+// odsDataStyleTypes defined the elements of the data styles in the
+// OpenDocument Spreadsheet.
+var odsDataStyleTypes = map[string]bool{
+	"number-style": true, "currency-style": true, "percentage-style": true,
+	"date-style": true, "time-style": true, "boolean-style": true, "text-style": true,
+}
+
+// odsStyle directly maps the style element of the OpenDocument Spreadsheet,
+// only the properties which could be mapped onto the workbook are declared.
+type odsStyle struct {
+	Name      string `xml:"name,attr"`
+	Family    string `xml:"family,attr"`
+	Parent    string `xml:"parent-style-name,attr"`
+	DataStyle string `xml:"data-style-name,attr"`
+	Cell      *struct {
+		BackgroundColor string `xml:"background-color,attr"`
+		Border          string `xml:"border,attr"`
+		BorderLeft      string `xml:"border-left,attr"`
+		BorderRight     string `xml:"border-right,attr"`
+		BorderTop       string `xml:"border-top,attr"`
+		BorderBottom    string `xml:"border-bottom,attr"`
+		WrapOption      string `xml:"wrap-option,attr"`
+		VerticalAlign   string `xml:"vertical-align,attr"`
+		RotationAngle   string `xml:"rotation-angle,attr"`
+		ShrinkToFit     string `xml:"shrink-to-fit,attr"`
+	} `xml:"table-cell-properties"`
+	Paragraph *struct {
+		TextAlign string `xml:"text-align,attr"`
+	} `xml:"paragraph-properties"`
+	Text *struct {
+		FontName         string `xml:"font-name,attr"`
+		FontFamily       string `xml:"font-family,attr"`
+		FontSize         string `xml:"font-size,attr"`
+		FontWeight       string `xml:"font-weight,attr"`
+		FontStyle        string `xml:"font-style,attr"`
+		Color            string `xml:"color,attr"`
+		UnderlineStyle   string `xml:"text-underline-style,attr"`
+		UnderlineType    string `xml:"text-underline-type,attr"`
+		LineThroughStyle string `xml:"text-line-through-style,attr"`
+	} `xml:"text-properties"`
+	Column *struct {
+		Width string `xml:"column-width,attr"`
+	} `xml:"table-column-properties"`
+	Row *struct {
+		Height string `xml:"row-height,attr"`
+	} `xml:"table-row-properties"`
+}
+
+// odsDataStyle directly maps the data style elements of the OpenDocument
+// Spreadsheet, such as number:number-style and number:date-style.
+type odsDataStyle struct {
+	XMLName  xml.Name
+	Name     string             `xml:"name,attr"`
+	Truncate string             `xml:"truncate-on-overflow,attr"`
+	Items    []odsDataStyleItem `xml:",any"`
+}
+
+// odsDataStyleItem directly maps the child element of the data style.
+type odsDataStyleItem struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Text    string     `xml:",chardata"`
+}
+
+// attr returns the value of the data style item attribute by given local
+// name.
+func (item *odsDataStyleItem) attr(name string) string {
+	for _, attr := range item.Attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// odsFontFace directly maps the style:font-face element.
+type odsFontFace struct {
+	Name   string `xml:"name,attr"`
+	Family string `xml:"font-family,attr"`
+}
+
+// odsCell defined the cell parsed from the table:table-cell element.
+type odsCell struct {
+	col, repeat, colSpan, rowSpan        int
+	covered                              bool
+	valueType, value, formula, styleName string
+	text                                 string
+}
+
+// odsColumn defined the column range parsed from the table:table-column
+// element.
+type odsColumn struct {
+	min, max  int
+	cellStyle string
+}
+
+// odsReader defined the state of the OpenDocument Spreadsheet reader.
+type odsReader struct {
+	f          *File
+	styles     map[string]*odsStyle
+	dataStyles map[string]*odsDataStyle
+	fontFaces  map[string]string
+	styleIDs   map[string]int
+	sheet      string
+	sheets     int
+	row        int
+	columns    []odsColumn
+}
+
+// openODS provides a function to create the workbook from the content.xml
+// and styles.xml parts of the OpenDocument Spreadsheet package.
+func openODS(files map[string][]byte, opts Options) (*File, error) {
+	r := &odsReader{
+		f:          NewFile(opts),
+		styles:     make(map[string]*odsStyle),
+		dataStyles: make(map[string]*odsDataStyle),
+		fontFaces:  make(map[string]string),
+		styleIDs:   make(map[string]int),
+	}
+	for _, name := range []string{"styles.xml", "content.xml"} {
+		content, ok := files[name]
+		if !ok {
+			if name == "content.xml" {
+				return nil, ErrWorkbookFileFormat
+			}
+			continue
+		}
+		if err := r.readPart(content); err != nil {
+			return nil, err
+		}
+	}
+	if r.sheets == 0 {
+		return nil, ErrWorkbookFileFormat
+	}
+	return r.f, nil
+}
+
+// readPart provides a function to parse the styles and tables in the given
+// XML part of the OpenDocument Spreadsheet package.
+func (r *odsReader) readPart(content []byte) error {
+	d := r.f.xmlNewDecoder(bytes.NewReader(content))
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		se, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch {
+		case se.Name.Space == NameSpaceODSStyle && se.Name.Local == "font-face":
+			var face odsFontFace
+			if err = d.DecodeElement(&face, &se); err != nil {
+				return err
+			}
+			r.fontFaces[face.Name] = strings.Trim(face.Family, `'"`)
+		case se.Name.Space == NameSpaceODSStyle && (se.Name.Local == "style" || se.Name.Local == "default-style"):
+			style := &odsStyle{}
+			if err = d.DecodeElement(style, &se); err != nil {
+				return err
+			}
+			if se.Name.Local == "style" {
+				r.styles[style.Name] = style
+			}
+		case se.Name.Space == NameSpaceODSNumber && odsDataStyleTypes[se.Name.Local]:
+			style := &odsDataStyle{}
+			if err = d.DecodeElement(style, &se); err != nil {
+				return err
+			}
+			r.dataStyles[style.Name] = style
+		case se.Name.Space == NameSpaceODSTable && se.Name.Local == "table":
+			if err = r.readTable(d, se); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readTable provides a function to parse the table:table element into the
+// worksheet.
+func (r *odsReader) readTable(d *xml.Decoder, se xml.StartElement) error {
+	name := odsAttr(se, "name")
+	if r.sheets == 0 {
+		if err := r.f.SetSheetName(r.f.GetSheetName(0), name); err != nil {
+			return err
+		}
+	} else if _, err := r.f.NewSheet(name); err != nil {
+		return err
+	}
+	r.sheet, r.row, r.columns = name, 1, nil
+	r.sheets++
+	col := 1
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Space != NameSpaceODSTable {
+				if err = d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			switch t.Name.Local {
+			case "table-column":
+				if col, err = r.readColumn(t, col); err != nil {
+					return err
+				}
+			case "table-row":
+				if err = r.readRow(d, t); err != nil {
+					return err
+				}
+			case "table-header-rows", "table-rows", "table-row-group",
+				"table-header-columns", "table-columns", "table-column-group":
+			default:
+				if err = d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Space == NameSpaceODSTable && t.Name.Local == "table" {
+				return nil
+			}
+		}
+	}
+}
+
+// readColumn provides a function to set the width and visibility of the
+// columns by given table:table-column element, and returns the next column
+// number.
+func (r *odsReader) readColumn(se xml.StartElement, col int) (int, error) {
+	repeat := odsRepeat(se, "number-columns-repeated")
+	if col > MaxColumns {
+		return col, nil
+	}
+	maxCol := col + repeat - 1
+	if maxCol > MaxColumns {
+		maxCol = MaxColumns
+	}
+	r.columns = append(r.columns, odsColumn{min: col, max: maxCol, cellStyle: odsAttr(se, "default-cell-style-name")})
+	start, _ := ColumnNumberToName(col)
+	end, _ := ColumnNumberToName(maxCol)
+	if style, ok := r.styles[odsAttr(se, "style-name")]; ok && style.Column != nil && style.Column.Width != "" {
+		if px := odsLengthToPixels(style.Column.Width); px > 0 {
+			if err := r.f.SetColWidth(r.sheet, start, end, odsPixelsToColWidth(px)); err != nil {
+				return col, err
+			}
+		}
+	}
+	if odsAttr(se, "visibility") == "collapse" {
+		if err := r.f.SetColVisible(r.sheet, start+":"+end, false); err != nil {
+			return col, err
+		}
+	}
+	return maxCol + 1, nil
+}
+
+// readRow provides a function to parse the table:table-row element and write
+// the cells into the worksheet.
+func (r *odsReader) readRow(d *xml.Decoder, se xml.StartElement) error {
+	var (
+		repeat    = odsRepeat(se, "number-rows-repeated")
+		rowStyle  = odsAttr(se, "default-cell-style-name")
+		cells     []odsCell
+		col       = 1
+		hasValues bool
+	)
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		if ee, ok := token.(xml.EndElement); ok && ee.Name.Space == NameSpaceODSTable && ee.Name.Local == "table-row" {
+			break
+		}
+		t, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if t.Name.Space != NameSpaceODSTable || (t.Name.Local != "table-cell" && t.Name.Local != "covered-table-cell") {
+			if err = d.Skip(); err != nil {
+				return err
+			}
+			continue
+		}
+		cell := odsCell{
+			col: col, repeat: odsRepeat(t, "number-columns-repeated"),
+			colSpan: odsRepeat(t, "number-columns-spanned"), rowSpan: odsRepeat(t, "number-rows-spanned"),
+			covered: t.Name.Local == "covered-table-cell", styleName: odsAttr(t, "style-name"),
+			formula: odsAttr(t, "formula"),
+		}
+		for _, attr := range t.Attr {
+			if attr.Name.Space != NameSpaceODSOffice {
+				continue
+			}
+			switch attr.Name.Local {
+			case "value-type":
+				cell.valueType = attr.Value
+			case "value", "date-value", "time-value", "boolean-value":
+				cell.value = attr.Value
+			case "string-value":
+				cell.text = attr.Value
+			}
+		}
+		text, err := odsCellText(d)
+		if err != nil {
+			return err
+		}
+		if cell.text == "" {
+			cell.text = text
+		}
+		if cell.styleName == "" {
+			cell.styleName = rowStyle
+		}
+		if cell.styleName == "" {
+			cell.styleName = r.columnCellStyle(col)
+		}
+		if cell.valueType != "" || cell.text != "" || cell.formula != "" || cell.colSpan > 1 || cell.rowSpan > 1 {
+			hasValues = true
+		}
+		if col <= MaxColumns {
+			cells = append(cells, cell)
+		}
+		col += cell.repeat
+	}
+	var err error
+	if style, ok := r.styles[odsAttr(se, "style-name")]; ok && style.Row != nil && style.Row.Height != "" && hasValues {
+		if px := odsLengthToPixels(style.Row.Height); px > 0 {
+			for row := r.row; row < r.row+repeat && row <= TotalRows; row++ {
+				if err = r.f.SetRowHeight(r.sheet, row, px*0.75); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for row := r.row; row < r.row+repeat && row <= TotalRows && hasValues; row++ {
+		if odsAttr(se, "visibility") == "collapse" {
+			if err = r.f.SetRowVisible(r.sheet, row, false); err != nil {
+				return err
+			}
+		}
+		for _, cell := range cells {
+			if err = r.writeCell(row, cell); err != nil {
+				return err
+			}
+		}
+	}
+	r.row += repeat
+	return err
+}
+
+// columnCellStyle returns the default cell style name of the column.
+func (r *odsReader) columnCellStyle(col int) string {
+	for _, c := range r.columns {
+		if c.min <= col && col <= c.max {
+			return c.cellStyle
+		}
+	}
+	return ""
+}
+
+// writeCell provides a function to write the value, formula, style and merge
+// range of the given cell in the row.
+func (r *odsReader) writeCell(row int, cell odsCell) error {
+	empty := cell.valueType == "" && cell.text == "" && cell.formula == "" && cell.colSpan == 1 && cell.rowSpan == 1
+	if empty && (cell.covered || cell.styleName == "" || cell.styleName == "Default" || cell.repeat > 1) {
+		return nil
+	}
+	styleID, err := r.styleID(cell.styleName, cell.valueType, cell.value)
+	if err != nil {
+		return err
+	}
+	for col := cell.col; col < cell.col+cell.repeat && col <= MaxColumns; col++ {
+		ref, _ := CoordinatesToCellName(col, row)
+		if cell.formula != "" {
+			err = r.setCellFormula(ref, cell)
+		} else {
+			err = r.setCellValue(ref, cell)
+		}
+		if err != nil {
+			return err
+		}
+		if styleID != 0 {
+			if err = r.f.SetCellStyle(r.sheet, ref, ref, styleID); err != nil {
+				return err
+			}
+		}
+		if cell.colSpan > 1 || cell.rowSpan > 1 {
+			bottomRight, _ := CoordinatesToCellName(col+cell.colSpan-1, row+cell.rowSpan-1)
+			if err = r.f.MergeCell(r.sheet, ref, bottomRight); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// setCellValue provides a function to set the cell value by the value type
+// of the table cell.
+func (r *odsReader) setCellValue(ref string, cell odsCell) error {
+	switch cell.valueType {
+	case "float", "percentage", "currency":
+		if val, err := strconv.ParseFloat(cell.value, 64); err == nil {
+			return r.f.SetCellFloat(r.sheet, ref, val, -1, 64)
+		}
+	case "date":
+		if val, ok := odsDateToExcelTime(cell.value); ok {
+			return r.f.SetCellFloat(r.sheet, ref, val, -1, 64)
+		}
+	case "time":
+		if val, ok := odsDurationToExcelTime(cell.value); ok {
+			return r.f.SetCellFloat(r.sheet, ref, val, -1, 64)
+		}
+	case "boolean":
+		return r.f.SetCellBool(r.sheet, ref, cell.value == "true")
+	}
+	if cell.text == "" {
+		return nil
+	}
+	return r.f.SetCellStr(r.sheet, ref, cell.text)
+}
+
+// setCellFormula provides a function to set the formula of the cell, and set
+// the calculated result of the table cell as the cached cell value.
+func (r *odsReader) setCellFormula(ref string, cell odsCell) error {
+	if err := r.f.SetCellFormula(r.sheet, ref, odsFormulaToExcel(cell.formula)); err != nil {
+		return err
+	}
+	ws, err := r.f.workSheetReader(r.sheet)
+	if err != nil {
+		return err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	c, _, _, err := ws.prepareCell(ref)
+	if err != nil {
+		return err
+	}
+	var (
+		val float64
+		ok  bool
+	)
+	switch cell.valueType {
+	case "float", "percentage", "currency":
+		val, err = strconv.ParseFloat(cell.value, 64)
+		ok = err == nil
+	case "date":
+		val, ok = odsDateToExcelTime(cell.value)
+	case "time":
+		val, ok = odsDurationToExcelTime(cell.value)
+	case "boolean":
+		c.T, c.V = "b", "0"
+		if cell.value == "true" {
+			c.V = "1"
+		}
+		return nil
+	}
+	if ok {
+		c.T, c.V = "", strconv.FormatFloat(val, 'f', -1, 64)
+		return nil
+	}
+	c.setStr(cell.text)
+	return nil
+}
+
+// styleID provides a function to create the style by given table cell style
+// name, and returns the style index. Dates and times without any number
+// format will be displayed with the built-in date and time number formats.
+func (r *odsReader) styleID(name, valueType, value string) (int, error) {
+	numFmt := 0
+	if valueType == "date" {
+		numFmt = 14
+		if strings.Contains(value, "T") {
+			numFmt = 22
+		}
+	}
+	if valueType == "time" {
+		numFmt = 21
+	}
+	key := name + "\x00" + strconv.Itoa(numFmt)
+	if styleID, ok := r.styleIDs[key]; ok {
+		return styleID, nil
+	}
+	style, ok := r.cellStyle(name, 0)
+	if !ok && numFmt == 0 {
+		r.styleIDs[key] = 0
+		return 0, nil
+	}
+	if style.CustomNumFmt == nil {
+		style.NumFmt = numFmt
+	}
+	styleID, err := r.f.NewStyle(style)
+	if err != nil {
+		return styleID, err
+	}
+	r.styleIDs[key] = styleID
+	return styleID, err
+}
+
+// cellStyle provides a function to resolve the table cell style with
+// inherited parent styles into the cell style definition.
+func (r *odsReader) cellStyle(name string, depth int) (*Style, bool) {
+	s, ok := r.styles[name]
+	if !ok || name == "Default" || depth > 32 {
+		return &Style{}, false
+	}
+	style, _ := r.cellStyle(s.Parent, depth+1)
+	if s.DataStyle != "" {
+		if code := r.numFmtCode(s.DataStyle, 0); code != "" {
+			style.CustomNumFmt = stringPtr(code)
+		}
+	}
+	if s.Text != nil {
+		if style.Font == nil {
+			style.Font = &Font{}
+		}
+		r.setFont(style.Font, s)
+	}
+	if s.Cell != nil {
+		if color := odsColor(s.Cell.BackgroundColor); color != "" {
+			style.Fill = Fill{Type: "pattern", Pattern: 1, Color: []string{color}}
+		}
+		for _, border := range []struct{ typ, value string }{
+			{"left", s.Cell.Border}, {"right", s.Cell.Border}, {"top", s.Cell.Border}, {"bottom", s.Cell.Border},
+			{"left", s.Cell.BorderLeft}, {"right", s.Cell.BorderRight}, {"top", s.Cell.BorderTop}, {"bottom", s.Cell.BorderBottom},
+		} {
+			style.Border = odsSetBorder(style.Border, border.typ, border.value)
+		}
+	}
+	if s.Cell != nil || s.Paragraph != nil {
+		if style.Alignment == nil {
+			style.Alignment = &Alignment{}
+		}
+		odsSetAlignment(style.Alignment, s)
+	}
+	return style, true
+}
+
+// setFont provides a function to set the font by given text properties of
+// the table cell style.
+func (r *odsReader) setFont(font *Font, s *odsStyle) {
+	if family, ok := r.fontFaces[s.Text.FontName]; ok {
+		font.Family = family
+	} else if s.Text.FontFamily != "" {
+		font.Family = strings.Trim(s.Text.FontFamily, `'"`)
+	}
+	if strings.HasSuffix(s.Text.FontSize, "pt") {
+		if size, err := strconv.ParseFloat(strings.TrimSuffix(s.Text.FontSize, "pt"), 64); err == nil {
+			font.Size = size
+		}
+	}
+	if s.Text.FontWeight != "" {
+		font.Bold = s.Text.FontWeight == "bold" || s.Text.FontWeight >= "600"
+	}
+	if s.Text.FontStyle != "" {
+		font.Italic = s.Text.FontStyle == "italic" || s.Text.FontStyle == "oblique"
+	}
+	if color := odsColor(s.Text.Color); color != "" {
+		font.Color = color
+	}
+	if s.Text.UnderlineStyle != "" {
+		font.Underline = ""
+		if s.Text.UnderlineStyle != "none" {
+			font.Underline = "single"
+			if s.Text.UnderlineType == "double" {
+				font.Underline = "double"
+			}
+		}
+	}
+	if s.Text.LineThroughStyle != "" {
+		font.Strike = s.Text.LineThroughStyle != "none"
+	}
+}
+
+// odsSetAlignment provides a function to set the alignment by given
+// paragraph and table cell properties of the table cell style.
+func odsSetAlignment(alignment *Alignment, s *odsStyle) {
+	if s.Paragraph != nil {
+		switch s.Paragraph.TextAlign {
+		case "start", "left":
+			alignment.Horizontal = "left"
+		case "end", "right":
+			alignment.Horizontal = "right"
+		case "center", "justify":
+			alignment.Horizontal = s.Paragraph.TextAlign
+		}
+	}
+	if s.Cell == nil {
+		return
+	}
+	switch s.Cell.VerticalAlign {
+	case "top", "bottom":
+		alignment.Vertical = s.Cell.VerticalAlign
+	case "middle":
+		alignment.Vertical = "center"
+	}
+	if s.Cell.WrapOption != "" {
+		alignment.WrapText = s.Cell.WrapOption == "wrap"
+	}
+	if s.Cell.ShrinkToFit != "" {
+		alignment.ShrinkToFit = s.Cell.ShrinkToFit == "true"
+	}
+	if angle, err := strconv.Atoi(s.Cell.RotationAngle); err == nil {
+		switch angle %= 360; {
+		case angle > 0 && angle <= 90:
+			alignment.TextRotation = angle
+		case angle >= 270:
+			alignment.TextRotation = 450 - angle
+		}
+	}
+}
+
+// odsSetBorder provides a function to set the border by given border type
+// and the fo:border attribute value, such as "0.74pt solid #000000".
+func odsSetBorder(borders []Border, typ, value string) []Border {
+	if value == "" {
+		return borders
+	}
+	border := Border{Type: typ}
+	var width float64
+	for _, field := range strings.Fields(value) {
+		switch {
+		case strings.HasPrefix(field, "#"):
+			border.Color = odsColor(field)
+		case field == "solid":
+			border.Style = 1
+		case field == "dashed":
+			border.Style = 3
+		case field == "dotted":
+			border.Style = 4
+		case field == "double":
+			border.Style = 6
+		default:
+			if px := odsLengthToPixels(field); px > 0 {
+				width = px
+			}
+		}
+	}
+	if border.Style == 1 && width > 2 {
+		border.Style = 2
+		if width > 3 {
+			border.Style = 5
+		}
+	}
+	for i := range borders {
+		if borders[i].Type == typ {
+			if border.Style == 0 {
+				return append(borders[:i], borders[i+1:]...)
+			}
+			borders[i] = border
+			return borders
+		}
+	}
+	if border.Style == 0 {
+		return borders
+	}
+	return append(borders, border)
+}
+
+// numFmtCode provides a function to convert the data style by given name into
+// the number format code.
+func (r *odsReader) numFmtCode(name string, depth int) string {
+	style, ok := r.dataStyles[name]
+	if !ok || depth > 4 {
+		return ""
+	}
+	var (
+		code, sections []string
+		elapsed        bool
+	)
+	for i := range style.Items {
+		item := &style.Items[i]
+		long := item.attr("style") == "long"
+		switch item.XMLName.Local {
+		case "map":
+			if section := r.numFmtCode(item.attr("apply-style-name"), depth+1); section != "" {
+				sections = append(sections, section)
+			}
+		case "text-properties":
+			if color := odsColor(item.attr("color")); color == "FF0000" {
+				code = append([]string{"[Red]"}, code...)
+			}
+		case "text":
+			code = append(code, odsNumFmtLiteral(item.Text))
+		case "currency-symbol":
+			code = append(code, "[$"+item.Text+"]")
+		case "text-content":
+			code = append(code, "@")
+		case "number", "scientific-number", "fraction":
+			code = append(code, odsNumFmtNumber(item))
+		case "boolean":
+			code = append(code, `"TRUE";"TRUE";"FALSE"`)
+		case "year":
+			code = append(code, map[bool]string{true: "yyyy", false: "yy"}[long])
+		case "month":
+			month := map[bool]string{true: "mm", false: "m"}[long]
+			if item.attr("textual") == "true" {
+				month = map[bool]string{true: "mmmm", false: "mmm"}[long]
+			}
+			code = append(code, month)
+		case "day":
+			code = append(code, map[bool]string{true: "dd", false: "d"}[long])
+		case "day-of-week":
+			code = append(code, map[bool]string{true: "dddd", false: "ddd"}[long])
+		case "hours":
+			hours := map[bool]string{true: "hh", false: "h"}[long]
+			if !elapsed && style.Truncate == "false" {
+				hours, elapsed = "["+hours+"]", true
+			}
+			code = append(code, hours)
+		case "minutes":
+			code = append(code, map[bool]string{true: "mm", false: "m"}[long])
+		case "seconds":
+			seconds := map[bool]string{true: "ss", false: "s"}[long]
+			if places, _ := strconv.Atoi(item.attr("decimal-places")); places > 0 {
+				seconds += "." + strings.Repeat("0", places)
+			}
+			code = append(code, seconds)
+		case "am-pm":
+			code = append(code, "AM/PM")
+		}
+	}
+	if len(code) == 0 {
+		return strings.Join(sections, ";")
+	}
+	return strings.Join(append(sections, strings.Join(code, "")), ";")
+}
+
+// odsNumFmtNumber provides a function to convert the number:number,
+// number:scientific-number and number:fraction elements into the number
+// format code.
+func odsNumFmtNumber(item *odsDataStyleItem) string {
+	minInt, _ := strconv.Atoi(item.attr("min-integer-digits"))
+	places, _ := strconv.Atoi(item.attr("decimal-places"))
+	if item.XMLName.Local == "number" && item.attr("decimal-places") == "" && item.attr("min-integer-digits") == "" {
+		return "General"
+	}
+	integer := strings.Repeat("0", minInt)
+	if integer == "" {
+		integer = "#"
+	}
+	if item.attr("grouping") == "true" {
+		digits := minInt
+		if digits < 4 {
+			digits = 4
+		}
+		integer = strings.Repeat("#", digits-minInt) + strings.Repeat("0", minInt)
+		integer = integer[:digits-3] + "," + integer[digits-3:]
+	}
+	switch item.XMLName.Local {
+	case "fraction":
+		numerator := strings.Repeat("?", odsDigits(item.attr("min-numerator-digits")))
+		denominator := strings.Repeat("?", odsDigits(item.attr("min-denominator-digits")))
+		if value := item.attr("denominator-value"); value != "" {
+			denominator = value
+		}
+		if minInt == 0 {
+			return numerator + "/" + denominator
+		}
+		return "# " + numerator + "/" + denominator
+	case "scientific-number":
+		integer += odsNumFmtDecimal(places)
+		return integer + "E+" + strings.Repeat("0", odsDigits(item.attr("min-exponent-digits")))
+	}
+	return integer + odsNumFmtDecimal(places)
+}
+
+// odsDigits returns the number of digits by given digits attribute value of
+// the data style, at least one digit will be returned.
+func odsDigits(s string) int {
+	if n, err := strconv.Atoi(s); err == nil && n > 1 {
+		return n
+	}
+	return 1
+}
+
+// odsNumFmtDecimal returns the decimal part of the number format code with the
+// given decimal places.
+func odsNumFmtDecimal(places int) string {
+	if places <= 0 {
+		return ""
+	}
+	return "." + strings.Repeat("0", places)
+}
+
+// odsNumFmtLiteral returns the number format code of the literal text, the
+// text will be enclosed in double quotation marks if it contains any
+// character besides the literal characters can be display without escaping.
+func odsNumFmtLiteral(text string) string {
+	if text == "" || strings.Trim(text, " -/:.,()%$+") == "" {
+		return text
+	}
+	return `"` + strings.ReplaceAll(text, `"`, `"\""`) + `"`
+}
+
+// odsCellText provides a function to read the paragraphs of the table cell
+// until the end of the cell element, and returns the cell text.
+func odsCellText(d *xml.Decoder) (string, error) {
+	var (
+		buf        strings.Builder
+		depth      int
+		paragraphs int
+		inText     int
+	)
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return buf.String(), err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Space == NameSpaceODSOffice && t.Name.Local == "annotation" ||
+				t.Name.Space != NameSpaceODSText && t.Name.Space != NameSpaceODSTable {
+				if err = d.Skip(); err != nil {
+					return buf.String(), err
+				}
+				continue
+			}
+			depth++
+			if t.Name.Space != NameSpaceODSText {
+				continue
+			}
+			switch t.Name.Local {
+			case "p", "h":
+				if paragraphs++; paragraphs > 1 {
+					buf.WriteString("\n")
+				}
+				inText++
+			case "s":
+				n := 1
+				if c, err := strconv.Atoi(odsAttr(t, "c")); err == nil && c > 0 {
+					n = c
+				}
+				buf.WriteString(strings.Repeat(" ", n))
+			case "tab":
+				buf.WriteString("\t")
+			case "line-break":
+				buf.WriteString("\n")
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				return buf.String(), err
+			}
+			depth--
+			if t.Name.Space == NameSpaceODSText && (t.Name.Local == "p" || t.Name.Local == "h") {
+				inText--
+			}
+		case xml.CharData:
+			if inText > 0 {
+				buf.Write(t)
+			}
+		}
+	}
+}
+
+// odsAttr returns the attribute value of the element by given local name.
+func odsAttr(se xml.StartElement, name string) string {
+	for _, attr := range se.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// odsRepeat returns the positive repeat or span count of the element by given
+// attribute local name.
+func odsRepeat(se xml.StartElement, name string) int {
+	if n, err := strconv.Atoi(odsAttr(se, name)); err == nil && n > 0 {
+		return n
+	}
+	return 1
+}
+
+// odsColor returns the RGB hex color without the number sign by given color
+// value in the "#RRGGBB" format, or an empty string if the color is
+// transparent or invalid.
+func odsColor(color string) string {
+	if len(color) != 7 || color[0] != '#' {
+		return ""
+	}
+	return strings.ToUpper(color[1:])
+}
+
+// odsLengthToPixels converts the length with unit in the OpenDocument, such as
+// "2.258cm", into pixels.
+func odsLengthToPixels(length string) float64 {
+	for unit, ratio := range map[string]float64{
+		"cm": 96 / 2.54, "mm": 96 / 25.4, "in": 96, "pt": 96.0 / 72, "pc": 16, "px": 1,
+	} {
+		if strings.HasSuffix(length, unit) {
+			val, err := strconv.ParseFloat(strings.TrimSuffix(length, unit), 64)
+			if err != nil {
+				return 0
+			}
+			return val * ratio
+		}
+	}
+	return 0
+}
+
+// odsPixelsToColWidth converts the width in pixels into the column width in
+// characters, which is the inverse of the convertColWidthToPixels function.
+func odsPixelsToColWidth(px float64) float64 {
+	if px <= 12 {
+		return math.Round(px/12*100) / 100
+	}
+	return math.Floor((px-5.5)/7*100) / 100
+}
+
+// odsDateToExcelTime converts the office:date-value attribute value into the
+// Excel date time serial number.
+func odsDateToExcelTime(value string) (float64, bool) {
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			val, err := timeToExcelTime(t, false)
+			return val, err == nil
+		}
+	}
+	return 0, false
+}
+
+// odsDurationToExcelTime converts the office:time-value attribute value in
+// the ISO 8601 duration format, such as "PT12H30M00S", into the Excel time
+// serial number.
+func odsDurationToExcelTime(value string) (float64, bool) {
+	var seconds float64
+	sign := 1.0
+	if strings.HasPrefix(value, "-") {
+		sign, value = -1, value[1:]
+	}
+	if !strings.HasPrefix(value, "PT") {
+		return 0, false
+	}
+	value = value[2:]
+	for unit, ratio := range map[byte]float64{'H': 3600, 'M': 60, 'S': 1} {
+		if idx := strings.IndexByte(value, unit); idx != -1 {
+			start := idx
+			for start > 0 && (value[start-1] >= '0' && value[start-1] <= '9' || value[start-1] == '.') {
+				start--
+			}
+			n, err := strconv.ParseFloat(value[start:idx], 64)
+			if err != nil {
+				return 0, false
+			}
+			seconds += n * ratio
+		}
+	}
+	return sign * seconds / 86400, true
+}
+
+// odsFormulaToExcel converts the formula in the OpenFormula syntax, such as
+// "of:=SUM([.A1:.B2];['Sheet 2'.C3])", into the Excel syntax.
+func odsFormulaToExcel(formula string) string {
+	if idx := strings.Index(formula, ":="); idx != -1 && strings.IndexFunc(formula[:idx], func(r rune) bool {
+		return !(r >= 'a' && r <= 'z')
+	}) == -1 {
+		formula = formula[idx+2:]
+	}
+	formula = strings.TrimPrefix(formula, "=")
+	var buf strings.Builder
+	for i := 0; i < len(formula); i++ {
+		switch c := formula[i]; c {
+		case '"':
+			end := i + 1
+			for end < len(formula) {
+				if formula[end] == '"' {
+					if end+1 < len(formula) && formula[end+1] == '"' {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			if end >= len(formula) {
+				end = len(formula) - 1
+			}
+			buf.WriteString(formula[i : end+1])
+			i = end
+		case '[':
+			end := i + 1
+			for quoted := false; end < len(formula) && (quoted || formula[end] != ']'); end++ {
+				if formula[end] == '\'' {
+					quoted = !quoted
+				}
+			}
+			if end > len(formula) {
+				end = len(formula)
+			}
+			buf.WriteString(odsRefToExcel(formula[i+1 : end]))
+			i = end
+		case ';':
+			buf.WriteByte(',')
+		case '|':
+			buf.WriteByte(';')
+		case '~':
+			buf.WriteByte(',')
+		case '#':
+			n := 1
+			for code := range formulaErrorCodes {
+				if strings.HasPrefix(formula[i:], code) {
+					n = len(code)
+				}
+			}
+			buf.WriteString(formula[i : i+n])
+			i += n - 1
+		case '!':
+			buf.WriteByte(' ')
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
+}
+
+// odsRefToExcel converts the cell reference in the OpenFormula syntax without
+// the brackets, such as ".A1:.B2" or "$'Sheet 2'.$C$3", into the Excel syntax.
+func odsRefToExcel(ref string) string {
+	var (
+		parts  []string
+		quoted bool
+		start  int
+	)
+	for i := 0; i < len(ref); i++ {
+		if ref[i] == '\'' {
+			quoted = !quoted
+		}
+		if ref[i] == ':' && !quoted {
+			parts, start = append(parts, ref[start:i]), i+1
+		}
+	}
+	parts = append(parts, ref[start:])
+	var result []string
+	for i, part := range parts {
+		sheet, cell := "", part
+		if idx := strings.LastIndex(part, "."); idx != -1 {
+			sheet, cell = strings.TrimPrefix(part[:idx], "$"), part[idx+1:]
+		}
+		if strings.HasPrefix(sheet, "'") && strings.HasSuffix(sheet, "'") && len(sheet) > 1 {
+			sheet = strings.ReplaceAll(sheet[1:len(sheet)-1], "''", "'")
+		}
+		if i == 0 && sheet != "" {
+			cell = escapeSheetName(sheet) + "!" + cell
+		}
+		result = append(result, cell)
+	}
+	return strings.Join(result, ":")
+}
+
+// excelFormulaToODS converts the formula in the Excel syntax into the
+// OpenFormula syntax with the "of:=" prefix.
+func excelFormulaToODS(formula string) string {
+	var (
+		ps     = efp.ExcelParser()
+		tokens = ps.Parse(formula)
+		buf    strings.Builder
+		stack  []string
+	)
+	buf.WriteString("of:=")
+	for i, token := range tokens {
+		switch token.TType {
+		case efp.TokenTypeFunction:
+			if token.TSubType == efp.TokenSubTypeStart {
+				stack = append(stack, token.TValue)
+				switch token.TValue {
+				case "ARRAY":
+					buf.WriteString("{")
+				case "ARRAYROW":
+				default:
+					buf.WriteString(strings.TrimPrefix(token.TValue, "_xlfn.") + "(")
+				}
+				continue
+			}
+			if len(stack) > 0 {
+				switch stack[len(stack)-1] {
+				case "ARRAY":
+					buf.WriteString("}")
+				case "ARRAYROW":
+				default:
+					buf.WriteString(")")
+				}
+				stack = stack[:len(stack)-1]
+			}
+		case efp.TokenTypeArgument:
+			if len(stack) > 0 && stack[len(stack)-1] == "ARRAY" {
+				buf.WriteString("|")
+				continue
+			}
+			buf.WriteString(";")
+		case efp.TokenTypeOperand:
+			switch token.TSubType {
+			case efp.TokenSubTypeText:
+				buf.WriteString(`"` + strings.ReplaceAll(token.TValue, `"`, `""`) + `"`)
+			case efp.TokenSubTypeLogical:
+				buf.WriteString(strings.ToUpper(token.TValue) + "()")
+			case efp.TokenSubTypeRange:
+				buf.WriteString(excelRefToODS(token.TValue))
+			default:
+				buf.WriteString(token.TValue)
+			}
+		case efp.TokenTypeOperatorInfix:
+			if token.TSubType == efp.TokenSubTypeIntersection {
+				buf.WriteString("!")
+				continue
+			}
+			buf.WriteString(token.TValue)
+		case efp.TokenTypeSubexpression:
+			if token.TSubType == efp.TokenSubTypeStart {
+				buf.WriteString("(")
+				continue
+			}
+			buf.WriteString(")")
+		case efp.TokenTypeWhitespace:
+			if i > 0 && i < len(tokens)-1 && tokens[i-1].TType == efp.TokenTypeOperand &&
+				tokens[i+1].TType == efp.TokenTypeOperand {
+				buf.WriteString("!")
+				continue
+			}
+			buf.WriteString(" ")
+		default:
+			buf.WriteString(token.TValue)
+		}
+	}
+	return buf.String()
+}
+
+// excelRefToODS converts the cell reference or defined name in the Excel
+// syntax, such as "Sheet 2!A1:B2", into the OpenFormula syntax.
+func excelRefToODS(ref string) string {
+	sheet, cells := "", ref
+	if idx := strings.LastIndex(ref, "!"); idx != -1 {
+		sheet, cells = ref[:idx], ref[idx+1:]
+	}
+	parts := strings.Split(cells, ":")
+	if len(parts) > 2 {
+		return ref
+	}
+	names := strings.Split(strings.ReplaceAll(cells, "$", ""), ":")
+	for i, part := range parts {
+		name := names[i]
+		switch {
+		case len(names) == 2 && isODSColumnName(name) && isODSColumnName(names[1-i]):
+			part += map[int]string{0: "1", 1: strconv.Itoa(TotalRows)}[i]
+		case len(names) == 2 && isODSRowNumber(name) && isODSRowNumber(names[1-i]):
+			part = map[int]string{0: "A", 1: "XFD"}[i] + part
+		default:
+			if _, _, err := CellNameToCoordinates(name); err != nil {
+				return ref
+			}
+		}
+		parts[i] = "." + part
+	}
+	if sheet != "" {
+		if strings.IndexFunc(sheet, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_')
+		}) != -1 {
+			sheet = "'" + strings.ReplaceAll(sheet, "'", "''") + "'"
+		}
+		parts[0] = "$" + sheet + parts[0]
+	}
+	return "[" + strings.Join(parts, ":") + "]"
+}
+
+// isODSColumnName returns if the given string is a valid column name.
+func isODSColumnName(name string) bool {
+	col, err := ColumnNameToNumber(name)
+	return err == nil && col > 0
+}
+
+// isODSRowNumber returns if the given string is a valid row number.
+func isODSRowNumber(name string) bool {
+	row, err := strconv.Atoi(name)
+	return err == nil && row > 0 && row <= TotalRows
+}
+
+// odsWriter defined the state of the OpenDocument Spreadsheet writer.
+type odsWriter struct {
+	f          *File
+	sst        *xlsxSST
+	date1904   bool
+	cellStyles map[int]string
+	dataStyles map[int]string
+	colStyles  map[string]string
+	rowStyles  map[string]string
+	fontFaces  map[string]bool
+	styleBuf   strings.Builder
+	bodyBuf    strings.Builder
+}
+
+// writeODS provides a function to write the workbook as the OpenDocument
+// Spreadsheet package to io.Writer.
+func (f *File) writeODS(w io.Writer) error {
+	ow := &odsWriter{
+		f: f, cellStyles: make(map[int]string), dataStyles: make(map[int]string),
+		colStyles: make(map[string]string), rowStyles: make(map[string]string),
+		fontFaces: make(map[string]bool),
+	}
+	var err error
+	if ow.sst, err = f.sharedStringsReader(); err != nil {
+		return err
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	if wb != nil && wb.WorkbookPr != nil {
+		ow.date1904 = wb.WorkbookPr.Date1904
+	}
+	for _, sheet := range f.GetSheetList() {
+		if err = ow.writeTable(sheet); err != nil {
+			return err
+		}
+	}
+	styles, err := ow.stylesPart()
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(w)
+	mimetype, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err = mimetype.Write([]byte(ContentTypeODS)); err != nil {
+		return err
+	}
+	for _, part := range []struct {
+		name    string
+		content string
+	}{
+		{"META-INF/manifest.xml", ow.manifestPart()},
+		{"styles.xml", styles},
+		{"content.xml", ow.contentPart()},
+	} {
+		fi, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(fi, part.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// manifestPart returns the META-INF/manifest.xml part of the package.
+func (ow *odsWriter) manifestPart() string {
+	return xml.Header + `<manifest:manifest xmlns:manifest="` + NameSpaceODSManifest + `" manifest:version="1.3">` +
+		`<manifest:file-entry manifest:full-path="/" manifest:version="1.3" manifest:media-type="` + ContentTypeODS + `"/>` +
+		`<manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/>` +
+		`<manifest:file-entry manifest:full-path="styles.xml" manifest:media-type="text/xml"/>` +
+		`</manifest:manifest>`
+}
+
+// stylesPart returns the styles.xml part of the package with the default
+// cell style of the workbook.
+func (ow *odsWriter) stylesPart() (string, error) {
+	style, err := ow.f.GetStyle(0)
+	if err != nil {
+		return "", err
+	}
+	var props string
+	if style.Font != nil {
+		if style.Font.Family != "" {
+			ow.fontFaces[style.Font.Family] = true
+			props += ` style:font-name="` + odsEscape(style.Font.Family) + `"`
+		}
+		if style.Font.Size > 0 {
+			props += ` fo:font-size="` + strconv.FormatFloat(style.Font.Size, 'f', -1, 64) + `pt"`
+		}
+	}
+	return xml.Header + `<office:document-styles` + odsNameSpaceDeclaration + `>` + ow.fontFaceDecls() +
+		`<office:styles><style:default-style style:family="table-cell"><style:text-properties` + props + `/></style:default-style>` +
+		`<style:style style:name="Default" style:family="table-cell"/></office:styles></office:document-styles>`, err
+}
+
+// contentPart returns the content.xml part of the package.
+func (ow *odsWriter) contentPart() string {
+	return xml.Header + `<office:document-content` + odsNameSpaceDeclaration + `>` + ow.fontFaceDecls() +
+		`<office:automatic-styles>` + ow.styleBuf.String() + `</office:automatic-styles>` +
+		`<office:body><office:spreadsheet>` + ow.bodyBuf.String() + `</office:spreadsheet></office:body></office:document-content>`
+}
+
+// fontFaceDecls returns the font face declarations of the used font families.
+func (ow *odsWriter) fontFaceDecls() string {
+	families := make([]string, 0, len(ow.fontFaces))
+	for family := range ow.fontFaces {
+		families = append(families, family)
+	}
+	sort.Strings(families)
+	var buf strings.Builder
+	buf.WriteString("<office:font-face-decls>")
+	for _, family := range families {
+		fmt.Fprintf(&buf, `<style:font-face style:name="%s" svg:font-family="%s"/>`, odsEscape(family), odsEscape(family))
+	}
+	buf.WriteString("</office:font-face-decls>")
+	return buf.String()
+}
+
+// odsOutCell defined the cell to be written into the table row.
+type odsOutCell struct {
+	cell             *xlsxC
+	covered          bool
+	colSpan, rowSpan int
+}
+
+// writeTable provides a function to write the worksheet as the table:table
+// element.
+func (ow *odsWriter) writeTable(sheet string) error {
+	ws, err := ow.f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	var (
+		grid    = make(map[int]map[int]*odsOutCell)
+		heights = make(map[int]*xlsxRow)
+		maxCol  int
+		get     = func(col, row int) *odsOutCell {
+			if grid[row] == nil {
+				grid[row] = make(map[int]*odsOutCell)
+			}
+			if grid[row][col] == nil {
+				grid[row][col] = &odsOutCell{colSpan: 1, rowSpan: 1}
+			}
+			if col > maxCol {
+				maxCol = col
+			}
+			return grid[row][col]
+		}
+	)
+	for i := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[i]
+		heights[row.R] = row
+		for j := range row.C {
+			col, r, err := CellNameToCoordinates(row.C[j].R)
+			if err != nil {
+				return err
+			}
+			if row.C[j].V == "" && row.C[j].F == nil && row.C[j].S == 0 && row.C[j].IS == nil {
+				continue
+			}
+			get(col, r).cell = &row.C[j]
+		}
+	}
+	if ws.MergeCells != nil {
+		for _, mc := range ws.MergeCells.Cells {
+			coordinates, err := rangeRefToCoordinates(mc.Ref)
+			if err != nil {
+				return err
+			}
+			_ = sortCoordinates(coordinates)
+			for row := coordinates[1]; row <= coordinates[3]; row++ {
+				for col := coordinates[0]; col <= coordinates[2]; col++ {
+					get(col, row).covered = row != coordinates[1] || col != coordinates[0]
+				}
+			}
+			topLeft := get(coordinates[0], coordinates[1])
+			topLeft.colSpan, topLeft.rowSpan = coordinates[2]-coordinates[0]+1, coordinates[3]-coordinates[1]+1
+		}
+	}
+	fmt.Fprintf(&ow.bodyBuf, `<table:table table:name="%s">`, odsEscape(sheet))
+	ow.writeColumns(ws, maxCol)
+	rows := make([]int, 0, len(grid))
+	for row := range grid {
+		rows = append(rows, row)
+	}
+	sort.Ints(rows)
+	next := 1
+	for _, row := range rows {
+		if row > next {
+			fmt.Fprintf(&ow.bodyBuf, `<table:table-row table:number-rows-repeated="%d"><table:table-cell/></table:table-row>`, row-next)
+		}
+		if err = ow.writeRow(sheet, heights[row], grid[row]); err != nil {
+			return err
+		}
+		next = row + 1
+	}
+	if len(rows) == 0 {
+		ow.bodyBuf.WriteString(`<table:table-row><table:table-cell/></table:table-row>`)
+	}
+	ow.bodyBuf.WriteString(`</table:table>`)
+	return err
+}
+
+// writeColumns provides a function to write the table:table-column elements
+// with the column widths and visibility of the worksheet.
+func (ow *odsWriter) writeColumns(ws *xlsxWorksheet, maxCol int) {
+	next := 1
+	if ws.Cols != nil {
+		cols := make([]xlsxCol, len(ws.Cols.Col))
+		copy(cols, ws.Cols.Col)
+		sort.Slice(cols, func(i, j int) bool { return cols[i].Min < cols[j].Min })
+		for _, col := range cols {
+			if col.Min < next {
+				continue
+			}
+			if col.Min > next {
+				ow.writeColumn(col.Min-next, defaultColWidth, false)
+			}
+			width := defaultColWidth
+			if col.Width != nil {
+				width = *col.Width
+			}
+			ow.writeColumn(col.Max-col.Min+1, width, col.Hidden)
+			next = col.Max + 1
+		}
+	}
+	if maxCol >= next || next == 1 {
+		ow.writeColumn(maxCol-next+1, defaultColWidth, false)
+	}
+}
+
+// writeColumn provides a function to write the table:table-column element
+// with the given column width in characters.
+func (ow *odsWriter) writeColumn(repeat int, width float64, hidden bool) {
+	length := strconv.FormatFloat(math.Round(convertColWidthToPixels(width)*0.75*100)/100, 'f', -1, 64) + "pt"
+	name, ok := ow.colStyles[length]
+	if !ok {
+		name = "co" + strconv.Itoa(len(ow.colStyles)+1)
+		ow.colStyles[length] = name
+		fmt.Fprintf(&ow.styleBuf, `<style:style style:name="%s" style:family="table-column"><style:table-column-properties fo:break-before="auto" style:column-width="%s"/></style:style>`, name, length)
+	}
+	fmt.Fprintf(&ow.bodyBuf, `<table:table-column table:style-name="%s"`, name)
+	if repeat > 1 {
+		fmt.Fprintf(&ow.bodyBuf, ` table:number-columns-repeated="%d"`, repeat)
+	}
+	if hidden {
+		ow.bodyBuf.WriteString(` table:visibility="collapse"`)
+	}
+	ow.bodyBuf.WriteString(` table:default-cell-style-name="Default"/>`)
+}
+
+// writeRow provides a function to write the table:table-row element with the
+// given cells.
+func (ow *odsWriter) writeRow(sheet string, row *xlsxRow, cells map[int]*odsOutCell) error {
+	ow.bodyBuf.WriteString(`<table:table-row`)
+	if row != nil && row.CustomHeight && row.Ht != nil {
+		length := strconv.FormatFloat(*row.Ht, 'f', -1, 64) + "pt"
+		name, ok := ow.rowStyles[length]
+		if !ok {
+			name = "ro" + strconv.Itoa(len(ow.rowStyles)+1)
+			ow.rowStyles[length] = name
+			fmt.Fprintf(&ow.styleBuf, `<style:style style:name="%s" style:family="table-row"><style:table-row-properties style:row-height="%s" style:use-optimal-row-height="false"/></style:style>`, name, length)
+		}
+		fmt.Fprintf(&ow.bodyBuf, ` table:style-name="%s"`, name)
+	}
+	if row != nil && row.Hidden {
+		ow.bodyBuf.WriteString(` table:visibility="collapse"`)
+	}
+	ow.bodyBuf.WriteString(`>`)
+	cols := make([]int, 0, len(cells))
+	for col := range cells {
+		cols = append(cols, col)
+	}
+	sort.Ints(cols)
+	next := 1
+	for _, col := range cols {
+		if col > next {
+			ow.writeEmptyCells(col - next)
+		}
+		if err := ow.writeCell(sheet, col, cells[col]); err != nil {
+			return err
+		}
+		next = col + 1
+	}
+	ow.bodyBuf.WriteString(`</table:table-row>`)
+	return nil
+}
+
+// writeEmptyCells provides a function to write the given number of empty
+// table cells.
+func (ow *odsWriter) writeEmptyCells(repeat int) {
+	if repeat > 1 {
+		fmt.Fprintf(&ow.bodyBuf, `<table:table-cell table:number-columns-repeated="%d"/>`, repeat)
+		return
+	}
+	ow.bodyBuf.WriteString(`<table:table-cell/>`)
+}
+
+// writeCell provides a function to write the table:table-cell or
+// table:covered-table-cell element.
+func (ow *odsWriter) writeCell(sheet string, col int, cell *odsOutCell) error {
+	elem := "table:table-cell"
+	if cell.covered {
+		elem = "table:covered-table-cell"
+	}
+	ow.bodyBuf.WriteString("<" + elem)
+	if cell.colSpan > 1 || cell.rowSpan > 1 {
+		fmt.Fprintf(&ow.bodyBuf, ` table:number-columns-spanned="%d" table:number-rows-spanned="%d"`, cell.colSpan, cell.rowSpan)
+	}
+	if cell.cell == nil {
+		ow.bodyBuf.WriteString("/>")
+		return nil
+	}
+	c := cell.cell
+	kind, err := ow.cellStyle(c.S)
+	if err != nil {
+		return err
+	}
+	if c.S != 0 {
+		fmt.Fprintf(&ow.bodyBuf, ` table:style-name="ce%d"`, c.S)
+	}
+	if c.F != nil {
+		formula, err := ow.f.GetCellFormula(sheet, c.R)
+		if err != nil {
+			return err
+		}
+		if formula != "" {
+			fmt.Fprintf(&ow.bodyBuf, ` table:formula="%s"`, odsEscape(excelFormulaToODS(formula)))
+		}
+	}
+	raw, err := c.getValueFrom(ow.f, ow.sst, true)
+	if err != nil {
+		return err
+	}
+	text, err := c.getValueFrom(ow.f, ow.sst, false)
+	if err != nil {
+		return err
+	}
+	switch c.T {
+	case "b":
+		fmt.Fprintf(&ow.bodyBuf, ` office:value-type="boolean" office:boolean-value="%t"`, raw == "1")
+		text = map[bool]string{true: "TRUE", false: "FALSE"}[raw == "1"]
+	case "s", "str", "inlineStr", "e":
+		if c.F != nil {
+			fmt.Fprintf(&ow.bodyBuf, ` office:value-type="string" office:string-value="%s"`, odsEscape(raw))
+		} else if raw != "" {
+			ow.bodyBuf.WriteString(` office:value-type="string"`)
+		}
+	default:
+		if raw != "" {
+			ow.writeNumber(kind, raw)
+		}
+	}
+	if text == "" {
+		ow.bodyBuf.WriteString("/>")
+		return nil
+	}
+	ow.bodyBuf.WriteString(">")
+	for _, paragraph := range strings.Split(text, "\n") {
+		ow.bodyBuf.WriteString("<text:p>" + odsText(paragraph) + "</text:p>")
+	}
+	ow.bodyBuf.WriteString("</" + elem + ">")
+	return nil
+}
+
+// writeNumber provides a function to write the value type and value
+// attributes of the numeric cell by given data style kind.
+func (ow *odsWriter) writeNumber(kind, raw string) {
+	val, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		fmt.Fprintf(&ow.bodyBuf, ` office:value-type="string" office:string-value="%s"`, odsEscape(raw))
+		return
+	}
+	switch kind {
+	case "date-style":
+		t := timeFromExcelTime(val, ow.date1904)
+		fmt.Fprintf(&ow.bodyBuf, ` office:value-type="date" office:date-value="%s"`, t.Format("2006-01-02T15:04:05"))
+	case "time-style":
+		seconds := math.Round(val * 86400)
+		fmt.Fprintf(&ow.bodyBuf, ` office:value-type="time" office:time-value="PT%02dH%02dM%02dS"`,
+			int(seconds)/3600, int(seconds)%3600/60, int(seconds)%60)
+	case "percentage-style":
+		fmt.Fprintf(&ow.bodyBuf, ` office:value-type="percentage" office:value="%s"`, raw)
+	case "currency-style":
+		fmt.Fprintf(&ow.bodyBuf, ` office:value-type="currency" office:value="%s"`, raw)
+	default:
+		fmt.Fprintf(&ow.bodyBuf, ` office:value-type="float" office:value="%s"`, raw)
+	}
+}
+
+// cellStyle provides a function to write the automatic table cell style by
+// given style index, and returns the kind of the data style.
+func (ow *odsWriter) cellStyle(styleID int) (string, error) {
+	if kind, ok := ow.cellStyles[styleID]; ok {
+		return kind, nil
+	}
+	style, err := ow.f.GetStyle(styleID)
+	if err != nil {
+		return "", err
+	}
+	var numFmtCode string
+	if style.CustomNumFmt != nil {
+		numFmtCode = *style.CustomNumFmt
+	} else if code, ok := ow.f.getBuiltInNumFmtCode(style.NumFmt); ok {
+		numFmtCode = code
+	}
+	kind, dataStyle := odsDataStyleFromCode("N"+strconv.Itoa(styleID), numFmtCode)
+	ow.cellStyles[styleID] = kind
+	if styleID == 0 {
+		return kind, err
+	}
+	ow.styleBuf.WriteString(dataStyle)
+	fmt.Fprintf(&ow.styleBuf, `<style:style style:name="ce%d" style:family="table-cell" style:parent-style-name="Default"`, styleID)
+	if dataStyle != "" {
+		fmt.Fprintf(&ow.styleBuf, ` style:data-style-name="N%d"`, styleID)
+	}
+	ow.styleBuf.WriteString(">")
+	ow.writeCellProperties(style)
+	ow.writeTextProperties(style)
+	ow.styleBuf.WriteString("</style:style>")
+	return kind, err
+}
+
+// writeCellProperties provides a function to write the table cell and
+// paragraph properties of the automatic table cell style.
+func (ow *odsWriter) writeCellProperties(style *Style) {
+	var props, align string
+	if style.Fill.Type == "pattern" && style.Fill.Pattern == 1 && len(style.Fill.Color) > 0 {
+		props += ` fo:background-color="#` + odsRGB(style.Fill.Color[0]) + `"`
+	}
+	for _, border := range style.Border {
+		if border.Style == 0 {
+			continue
+		}
+		line := map[int]string{2: "1.76pt solid", 3: "0.74pt dashed", 4: "0.74pt dotted", 5: "2.49pt solid", 6: "2.01pt double", 8: "1.76pt dashed"}[border.Style]
+		if line == "" {
+			line = "0.74pt solid"
+		}
+		color := odsRGB(border.Color)
+		if color == "" {
+			color = "000000"
+		}
+		if inStrSlice([]string{"left", "right", "top", "bottom"}, border.Type, true) != -1 {
+			props += fmt.Sprintf(` fo:border-%s="%s #%s"`, border.Type, line, color)
+		}
+	}
+	if style.Alignment != nil {
+		if style.Alignment.WrapText {
+			props += ` fo:wrap-option="wrap"`
+		}
+		if style.Alignment.ShrinkToFit {
+			props += ` style:shrink-to-fit="true"`
+		}
+		switch style.Alignment.Vertical {
+		case "top", "bottom":
+			props += ` style:vertical-align="` + style.Alignment.Vertical + `"`
+		case "center":
+			props += ` style:vertical-align="middle"`
+		}
+		if rotation := style.Alignment.TextRotation; rotation > 0 && rotation <= 180 {
+			if rotation > 90 {
+				rotation = 450 - rotation
+			}
+			props += fmt.Sprintf(` style:rotation-angle="%d"`, rotation)
+		}
+		align = map[string]string{"left": "start", "right": "end", "center": "center", "justify": "justify", "centerContinuous": "center", "distributed": "justify"}[style.Alignment.Horizontal]
+	}
+	if props != "" {
+		ow.styleBuf.WriteString("<style:table-cell-properties" + props + "/>")
+	}
+	if align != "" {
+		ow.styleBuf.WriteString(`<style:paragraph-properties fo:text-align="` + align + `" style:text-align-source="fix"/>`)
+	}
+}
+
+// writeTextProperties provides a function to write the text properties of
+// the automatic table cell style.
+func (ow *odsWriter) writeTextProperties(style *Style) {
+	if style.Font == nil {
+		return
+	}
+	var props string
+	if style.Font.Family != "" {
+		ow.fontFaces[style.Font.Family] = true
+		props += ` style:font-name="` + odsEscape(style.Font.Family) + `"`
+	}
+	if style.Font.Size > 0 {
+		props += ` fo:font-size="` + strconv.FormatFloat(style.Font.Size, 'f', -1, 64) + `pt"`
+	}
+	if style.Font.Bold {
+		props += ` fo:font-weight="bold"`
+	}
+	if style.Font.Italic {
+		props += ` fo:font-style="italic"`
+	}
+	if color := odsRGB(style.Font.Color); color != "" {
+		props += ` fo:color="#` + color + `"`
+	}
+	switch style.Font.Underline {
+	case "single", "singleAccounting":
+		props += ` style:text-underline-style="solid" style:text-underline-width="auto" style:text-underline-color="font-color"`
+	case "double", "doubleAccounting":
+		props += ` style:text-underline-style="solid" style:text-underline-type="double" style:text-underline-width="auto" style:text-underline-color="font-color"`
+	}
+	if style.Font.Strike {
+		props += ` style:text-line-through-style="solid"`
+	}
+	if props != "" {
+		ow.styleBuf.WriteString("<style:text-properties" + props + "/>")
+	}
+}
+
+// odsRGB returns the lower case RGB hex color by given RGB or ARGB hex color.
+func odsRGB(color string) string {
+	color = strings.TrimPrefix(color, "#")
+	if len(color) == 8 {
+		color = color[2:]
+	}
+	if len(color) != 6 {
+		return ""
+	}
+	return strings.ToLower(color)
+}
+
+// odsDataStyleFromCode converts the first section of the given number format
+// code into the data style element with the given name, and returns the kind
+// of the data style and the element. An empty element will be returned if the
+// number format code is general.
+func odsDataStyleFromCode(name, code string) (string, string) {
+	if code == "" || strings.EqualFold(code, "General") {
+		return "number-style", ""
+	}
+	p := nfp.NumberFormatParser()
+	sections := p.Parse(code)
+	if len(sections) == 0 {
+		return "number-style", ""
+	}
+	var (
+		kind, truncate string
+		items          []string
+		tokens         = sections[0].Items
+		number         []nfp.Token
+		hasDate        bool
+	)
+	flush := func() {
+		if len(number) > 0 {
+			items = append(items, odsNumberElement(number))
+			number = nil
+		}
+	}
+	for i, token := range tokens {
+		switch token.TType {
+		case nfp.TokenTypeZeroPlaceHolder, nfp.TokenTypeHashPlaceHolder, nfp.TokenTypeDecimalPoint,
+			nfp.TokenTypeThousandsSeparator, nfp.TokenTypeExponential:
+			number = append(number, token)
+			continue
+		}
+		flush()
+		switch token.TType {
+		case nfp.TokenTypeGeneral:
+			items = append(items, `<number:number number:min-integer-digits="1"/>`)
+		case nfp.TokenTypeLiteral:
+			items = append(items, `<number:text>`+odsEscape(token.TValue)+`</number:text>`)
+		case nfp.TokenTypePercent:
+			kind = "percentage-style"
+			items = append(items, `<number:text>%</number:text>`)
+		case nfp.TokenTypeTextPlaceHolder:
+			kind = "text-style"
+			items = append(items, `<number:text-content/>`)
+		case nfp.TokenTypeCurrencyLanguage:
+			for _, part := range token.Parts {
+				if part.Token.TType == nfp.TokenSubTypeCurrencyString {
+					kind = "currency-style"
+					items = append(items, `<number:currency-symbol>`+odsEscape(part.Token.TValue)+`</number:currency-symbol>`)
+				}
+			}
+		case nfp.TokenTypeDateTimes, nfp.TokenTypeElapsedDateTimes:
+			if token.TType == nfp.TokenTypeElapsedDateTimes {
+				truncate = ` number:truncate-on-overflow="false"`
+			}
+			element, isDate := odsDateTimeElement(tokens, i)
+			hasDate = hasDate || isDate
+			if kind == "" || kind == "time-style" {
+				kind = map[bool]string{true: "date-style", false: "time-style"}[hasDate]
+			}
+			items = append(items, element)
+		}
+	}
+	flush()
+	if kind == "" {
+		kind = "number-style"
+	}
+	if kind != "time-style" {
+		truncate = ""
+	}
+	return kind, fmt.Sprintf(`<number:%s style:name="%s"%s>%s</number:%s>`, kind, name, truncate, strings.Join(items, ""), kind)
+}
+
+// odsNumberElement returns the number:number or number:scientific-number
+// element by given digit placeholder tokens of the number format.
+func odsNumberElement(tokens []nfp.Token) string {
+	var (
+		minInt, places, exponent int
+		grouping, decimal, exp   bool
+	)
+	for _, token := range tokens {
+		switch token.TType {
+		case nfp.TokenTypeZeroPlaceHolder:
+			switch {
+			case exp:
+				exponent += len(token.TValue)
+			case decimal:
+				places += len(token.TValue)
+			default:
+				minInt += len(token.TValue)
+			}
+		case nfp.TokenTypeHashPlaceHolder:
+			if decimal && !exp {
+				places += len(token.TValue)
+			}
+		case nfp.TokenTypeDecimalPoint:
+			decimal = true
+		case nfp.TokenTypeThousandsSeparator:
+			grouping = !decimal
+		case nfp.TokenTypeExponential:
+			exp = true
+		}
+	}
+	attrs := fmt.Sprintf(` number:decimal-places="%d" number:min-integer-digits="%d"`, places, minInt)
+	if exp {
+		return fmt.Sprintf(`<number:scientific-number%s number:min-exponent-digits="%d"/>`, attrs, exponent)
+	}
+	if grouping {
+		attrs += ` number:grouping="true"`
+	}
+	return `<number:number` + attrs + `/>`
+}
+
+// odsDateTimeElement returns the date or time element of the data style by
+// given index of the date and time token in the number format tokens, and
+// returns if the element is a date part.
+func odsDateTimeElement(tokens []nfp.Token, idx int) (string, bool) {
+	value := strings.ToLower(tokens[idx].TValue)
+	long := ` number:style="long"`
+	if len(value) == 1 {
+		long = ""
+	}
+	switch value[0] {
+	case 'y':
+		return `<number:year` + map[bool]string{true: ` number:style="long"`, false: ""}[len(value) > 2] + `/>`, true
+	case 'd':
+		if len(value) > 2 {
+			return `<number:day-of-week` + map[bool]string{true: ` number:style="long"`, false: ""}[len(value) > 3] + `/>`, true
+		}
+		return `<number:day` + long + `/>`, true
+	case 'h':
+		return `<number:hours` + long + `/>`, false
+	case 's':
+		return `<number:seconds` + map[bool]string{true: ` number:style="long"`, false: ""}[strings.HasPrefix(value, "ss")] + `/>`, false
+	case 'a':
+		return `<number:am-pm/>`, false
+	case 'm':
+		if len(value) <= 2 && odsIsMinutes(tokens, idx) {
+			return `<number:minutes` + long + `/>`, false
+		}
+		if len(value) > 2 {
+			return `<number:month number:textual="true"` + map[bool]string{true: ` number:style="long"`, false: ""}[len(value) > 3] + `/>`, true
+		}
+		return `<number:month` + long + `/>`, true
+	}
+	return `<number:text>` + odsEscape(tokens[idx].TValue) + `</number:text>`, false
+}
+
+// odsIsMinutes returns if the "m" or "mm" date and time token at the given
+// index represents the minutes, which immediately follows the hours or is
+// immediately followed by the seconds.
+func odsIsMinutes(tokens []nfp.Token, idx int) bool {
+	for i := idx - 1; i >= 0; i-- {
+		if tokens[i].TType == nfp.TokenTypeDateTimes || tokens[i].TType == nfp.TokenTypeElapsedDateTimes {
+			if c := strings.ToLower(tokens[i].TValue)[0]; c == 'h' {
+				return true
+			}
+			break
+		}
+	}
+	for i := idx + 1; i < len(tokens); i++ {
+		if tokens[i].TType == nfp.TokenTypeDateTimes || tokens[i].TType == nfp.TokenTypeElapsedDateTimes {
+			return strings.ToLower(tokens[i].TValue)[0] == 's'
+		}
+	}
+	return false
+}
+
+// odsEscape returns the XML escaped text.
+func odsEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// odsText returns the escaped paragraph text, the consecutive, leading and
+// trailing spaces will be replaced with the text:s elements.
+func odsText(s string) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] != ' ' {
+			end := strings.IndexByte(s[i:], ' ')
+			if end == -1 {
+				end = len(s) - i
+			}
+			buf.WriteString(odsEscape(s[i : i+end]))
+			i += end
+			continue
+		}
+		end := i
+		for end < len(s) && s[end] == ' ' {
+			end++
+		}
+		n := end - i
+		if i > 0 && end < len(s) {
+			buf.WriteString(" ")
+			n--
+		}
+		if n == 1 {
+			buf.WriteString("<text:s/>")
+		} else if n > 1 {
+			fmt.Fprintf(&buf, `<text:s text:c="%d"/>`, n)
+		}
+		i = end
+	}
+	return buf.String()
+}
@@ -0,0 +1,290 @@
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveAsODS(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet 2")
+	assert.NoError(t, err)
+	for cell, value := range map[string]interface{}{
+		"A1": "Name", "B1": 12.5, "C1": true, "D1": time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC),
+		"A2": "a  b\nc", "B2": 0.25,
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	assert.NoError(t, f.SetCellValue("Sheet 2", "C3", 7))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B3", "SUM(B1:B2,'Sheet 2'!C3)*{1,2;3,4}&\"x\"\"y\""))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C3", "SUM(A1:B2 B1:C3)"))
+	assert.NoError(t, f.MergeCell("Sheet1", "A4", "B5"))
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "A", 20))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 2, 30))
+	styleID, err := f.NewStyle(&Style{
+		Font:      &Font{Bold: true, Italic: true, Color: "FF0000", Size: 12, Family: "Arial", Underline: "single"},
+		Fill:      Fill{Type: "pattern", Pattern: 1, Color: []string{"FFFF00"}},
+		Border:    []Border{{Type: "left", Color: "0000FF", Style: 2}},
+		Alignment: &Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", styleID))
+	percentStyle, err := f.NewStyle(&Style{NumFmt: 10})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B2", "B2", percentStyle))
+	path := filepath.Join("test", "TestSaveAsODS.ods")
+	assert.NoError(t, f.SaveAs(path))
+	assert.NoError(t, f.Close())
+
+	zr, err := zip.OpenReader(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "mimetype", zr.File[0].Name)
+	assert.Equal(t, zip.Store, zr.File[0].Method)
+	assert.NoError(t, zr.Close())
+
+	f, err = OpenFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Sheet 2"}, f.GetSheetList())
+	rows, err := f.GetRows("Sheet1", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Name", "12.5", "1", "45366.4375"}, rows[0])
+	assert.Equal(t, []string{"a  b\nc", "0.25"}, rows[1])
+	cellValue, err := f.GetCellValue("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "25.00%", cellValue)
+	cellValue, err = f.GetCellValue("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, "3/15/24 10:30", cellValue)
+	cellValue, err = f.GetCellValue("Sheet 2", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "7", cellValue)
+	formula, err := f.GetCellFormula("Sheet1", "B3")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(B1:B2,'Sheet 2'!C3)*{1,2;3,4}&\"x\"\"y\"", formula)
+	formula, err = f.GetCellFormula("Sheet1", "C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(A1:B2 B1:C3)", formula)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "A4", mergeCells[0].GetStartAxis())
+	assert.Equal(t, "B5", mergeCells[0].GetEndAxis())
+	width, err := f.GetColWidth("Sheet1", "A")
+	assert.NoError(t, err)
+	assert.InDelta(t, 20, width, 0.2)
+	height, err := f.GetRowHeight("Sheet1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	styleID, err = f.GetCellStyle("Sheet1", "A1")
+	assert.NoError(t, err)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, &Font{Bold: true, Italic: true, Color: "FF0000", Size: 12, Family: "Arial", Underline: "single"}, style.Font)
+	assert.Equal(t, []string{"FFFF00"}, style.Fill.Color)
+	assert.Equal(t, []Border{{Type: "left", Color: "0000FF", Style: 2}}, style.Border)
+	assert.Equal(t, "center", style.Alignment.Horizontal)
+	assert.Equal(t, "center", style.Alignment.Vertical)
+	assert.True(t, style.Alignment.WrapText)
+	assert.NoError(t, f.Close())
+
+	// Test save workbook as the OpenDocument Spreadsheet with invalid sheet
+	f = NewFile()
+	f.Sheet.Store("xl/worksheets/sheet1.xml", nil)
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	assert.Error(t, f.SaveAs(filepath.Join("test", "TestSaveAsODS.ods")))
+	assert.NoError(t, f.Close())
+}
+
+func TestOpenODS(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content` + odsNameSpaceDeclaration + `>
+<office:font-face-decls><style:font-face style:name="Liberation Sans" svg:font-family="'Liberation Sans'"/></office:font-face-decls>
+<office:automatic-styles>
+<style:style style:name="co1" style:family="table-column"><style:table-column-properties style:column-width="1in"/></style:style>
+<number:number-style style:name="N1P0"><number:number number:decimal-places="2" number:min-integer-digits="1" number:grouping="true"/></number:number-style>
+<number:number-style style:name="N1"><style:text-properties fo:color="#ff0000"/><number:text>-</number:text><number:number number:decimal-places="2" number:min-integer-digits="1" number:grouping="true"/><style:map style:condition="value()&gt;=0" style:apply-style-name="N1P0"/></number:number-style>
+<number:date-style style:name="N2"><number:year number:style="long"/><number:text>-</number:text><number:month number:style="long"/><number:text>-</number:text><number:day number:style="long"/></number:date-style>
+<number:time-style style:name="N3" number:truncate-on-overflow="false"><number:hours/><number:text>:</number:text><number:minutes number:style="long"/></number:time-style>
+<style:style style:name="ce1" style:family="table-cell" style:parent-style-name="Default" style:data-style-name="N1"><style:text-properties fo:font-weight="bold" style:font-name="Liberation Sans"/></style:style>
+<style:style style:name="ce2" style:family="table-cell" style:parent-style-name="ce1" style:data-style-name="N2"><style:table-cell-properties fo:border="0.06pt solid #000000" fo:background-color="#00ff00" style:rotation-angle="270"/><style:paragraph-properties fo:text-align="end"/></style:style>
+<style:style style:name="ce3" style:family="table-cell" style:data-style-name="N3"/>
+</office:automatic-styles>
+<office:body><office:spreadsheet>
+<table:table table:name="Data">
+<table:table-column table:style-name="co1" table:number-columns-repeated="2" table:default-cell-style-name="ce1"/>
+<table:table-column table:number-columns-repeated="1022" table:default-cell-style-name="Default"/>
+<table:table-row><table:table-cell office:value-type="string"><text:p>a<text:s text:c="2"/>b</text:p><text:p>c<office:annotation><text:p>comment</text:p></office:annotation></text:p></table:table-cell><table:table-cell office:value-type="float" office:value="-1234.5"><text:p>-1,234.50</text:p></table:table-cell><table:table-cell table:number-columns-repeated="1022"/></table:table-row>
+<table:table-row table:number-rows-repeated="2"><table:table-cell table:number-columns-repeated="2" table:style-name="Default" office:value-type="float" office:value="3"><text:p>3</text:p></table:table-cell></table:table-row>
+<table:table-row><table:table-cell table:style-name="ce2" office:value-type="date" office:date-value="2024-03-15"><text:p>2024-03-15</text:p></table:table-cell><table:table-cell table:style-name="ce3" office:value-type="time" office:time-value="PT36H30M00S"><text:p>36:30</text:p></table:table-cell><table:table-cell table:style-name="Default" office:value-type="boolean" office:boolean-value="true"><text:p>TRUE</text:p></table:table-cell><table:table-cell table:style-name="Default" table:formula="of:=SUM([.B2:.B3];[$'Other sheet'.A1])&amp;&quot;;&quot;" office:value-type="string" office:string-value="10;"><text:p>10;</text:p></table:table-cell></table:table-row>
+<table:table-row table:number-rows-repeated="1048570"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+</table:table>
+<table:table table:name="Other sheet">
+<table:table-row><table:table-cell table:number-columns-spanned="2" table:number-rows-spanned="2" office:value-type="float" office:value="4"><text:p>4</text:p></table:table-cell><table:covered-table-cell/></table:table-row>
+<table:table-row><table:covered-table-cell table:number-columns-repeated="2"/></table:table-row>
+<table:table-row><table:table-cell table:formula="of:=1+1" office:value-type="float" office:value="2"/><table:table-cell table:formula="of:=TRUE()" office:value-type="boolean" office:boolean-value="true"/><table:table-cell table:formula="of:=DATE(2024;1;1)" office:value-type="date" office:date-value="2024-01-01"/><table:table-cell table:formula="of:=TIME(12;0;0)" office:value-type="time" office:time-value="PT12H"/></table:table-row>
+</table:table>
+</office:spreadsheet></office:body></office:document-content>`
+	f, err := OpenReader(prepareTestODS(t, map[string]string{"content.xml": content}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Data", "Other sheet"}, f.GetSheetList())
+	rows, err := f.GetRows("Data")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"a  b\nc", "-1,234.50"}, {"3", "3"}, {"3", "3"}, {"2024-03-15", "36:30", "TRUE", "10;"},
+	}, rows)
+	formula, err := f.GetCellFormula("Data", "D4")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(B2:B3,'Other sheet'!A1)&\";\"", formula)
+	result, err := f.CalcCellValue("Data", "D4")
+	assert.NoError(t, err)
+	assert.Equal(t, "10;", result)
+	width, err := f.GetColWidth("Data", "B")
+	assert.NoError(t, err)
+	assert.Equal(t, 12.92, width)
+	styleID, err := f.GetCellStyle("Data", "A4")
+	assert.NoError(t, err)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, "yyyy-mm-dd", *style.CustomNumFmt)
+	assert.Equal(t, &Font{Bold: true, Family: "Liberation Sans", Size: 11}, style.Font)
+	assert.Equal(t, []string{"00FF00"}, style.Fill.Color)
+	assert.Len(t, style.Border, 4)
+	assert.Equal(t, &Alignment{Horizontal: "right", TextRotation: 180}, style.Alignment)
+	styleID, err = f.GetCellStyle("Data", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, styleID)
+	mergeCells, err := f.GetMergeCells("Other sheet")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "A1:B2", mergeCells[0][0])
+	rows, err = f.GetRows("Other sheet", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2", "1", "45292", "0.5"}, rows[2])
+	assert.NoError(t, f.Close())
+
+	// Test open the OpenDocument Spreadsheet without content
+	_, err = OpenReader(prepareTestODS(t, nil))
+	assert.Equal(t, ErrWorkbookFileFormat, err)
+	// Test open the OpenDocument Spreadsheet without table
+	_, err = OpenReader(prepareTestODS(t, map[string]string{"content.xml": "<office:document-content/>"}))
+	assert.Equal(t, ErrWorkbookFileFormat, err)
+	// Test open the OpenDocument Spreadsheet with invalid content
+	_, err = OpenReader(prepareTestODS(t, map[string]string{"content.xml": "<office:document-content>"}))
+	assert.EqualError(t, err, "XML syntax error on line 1: unexpected EOF")
+	_, err = OpenReader(prepareTestODS(t, map[string]string{"styles.xml": "<style:style xmlns:style=\"" + NameSpaceODSStyle + "\"><", "content.xml": ""}))
+	assert.Error(t, err)
+	// Test open the OpenDocument Spreadsheet with invalid sheet name
+	_, err = OpenReader(prepareTestODS(t, map[string]string{"content.xml": `<office:document-content` + odsNameSpaceDeclaration + `><office:body><office:spreadsheet><table:table table:name="a:b"/></office:spreadsheet></office:body></office:document-content>`}))
+	assert.Equal(t, ErrSheetNameInvalid, err)
+}
+
+func TestODSFormula(t *testing.T) {
+	for formula, expected := range map[string]string{
+		"of:=[.A1]+[.$B$2]":                  "A1+$B$2",
+		"of:=SUM([Sheet1.A1:.B2];{1;2|3;4})": "SUM(Sheet1!A1:B2,{1,2;3,4})",
+		"of:=[$'It''s'.A1]&\"[.A1];\"\"\"":   "'It''s'!A1&\"[.A1];\"\"\"",
+		"of:=IF([.#REF!];1;0)":               "IF(#REF!,1,0)",
+		"=A1":                                "A1",
+		"of:=\"unclosed":                     "\"unclosed",
+		"of:=[.A1":                           "A1",
+		"of:=SUM([.A1:.B2]![.B1:.C3])":       "SUM(A1:B2 B1:C3)",
+		"of:=IF(#REF!;#N/A;#DIV/0!)":         "IF(#REF!,#N/A,#DIV/0!)",
+	} {
+		assert.Equal(t, expected, odsFormulaToExcel(formula), formula)
+	}
+	for formula, expected := range map[string]string{
+		"SUM(A1:B2,'Sheet 2'!$C$3)":     "of:=SUM([.A1:.B2];[$'Sheet 2'.$C$3])",
+		"A:A+1:1+Name1+TRUE":            "of:=[.A1:.A1048576]+[.A1:.XFD1]+Name1+TRUE()",
+		"_xlfn.CONCAT(\"a\"\"b\",-A1%)": "of:=CONCAT(\"a\"\"b\";-[.A1]%)",
+		"{1,2;3,4}*(1+2)":               "of:={1;2|3;4}*(1+2)",
+		"A1:B2:C3":                      "of:=A1:B2:C3",
+		"SUM(A1:B2 B1:C3)":              "of:=SUM([.A1:.B2]![.B1:.C3])",
+	} {
+		assert.Equal(t, expected, excelFormulaToODS(formula), formula)
+	}
+	// Test round trip the formula with the intersection operator
+	for _, formula := range []string{"A1 B1", "SUM(A1:B2 B1:C3)", "SUM(A1:A3 A2:C2)*2"} {
+		assert.Equal(t, formula, odsFormulaToExcel(excelFormulaToODS(formula)), formula)
+	}
+}
+
+func TestODSDataStyle(t *testing.T) {
+	for code, expected := range map[string][]string{
+		"General":            {"number-style", ""},
+		"#,##0.00":           {"number-style", `<number:number-style style:name="N1"><number:number number:decimal-places="2" number:min-integer-digits="1" number:grouping="true"/></number:number-style>`},
+		"0.00E+00":           {"number-style", `<number:number-style style:name="N1"><number:scientific-number number:decimal-places="2" number:min-integer-digits="1" number:min-exponent-digits="2"/></number:number-style>`},
+		"0%":                 {"percentage-style", `<number:percentage-style style:name="N1"><number:number number:decimal-places="0" number:min-integer-digits="1"/><number:text>%</number:text></number:percentage-style>`},
+		"[$€-407]0":          {"currency-style", `<number:currency-style style:name="N1"><number:currency-symbol>€</number:currency-symbol><number:number number:decimal-places="0" number:min-integer-digits="1"/></number:currency-style>`},
+		"@":                  {"text-style", `<number:text-style style:name="N1"><number:text-content/></number:text-style>`},
+		"[h]:mm":             {"time-style", `<number:time-style style:name="N1" number:truncate-on-overflow="false"><number:hours/><number:text>:</number:text><number:minutes number:style="long"/></number:time-style>`},
+		"yy-mmm-d ddd AM/PM": {"date-style", `<number:date-style style:name="N1"><number:year/><number:text>-</number:text><number:month number:textual="true"/><number:text>-</number:text><number:day/><number:text> </number:text><number:day-of-week/><number:text> </number:text><number:am-pm/></number:date-style>`},
+		"yyyy/m/d h:mm:ss":   {"date-style", `<number:date-style style:name="N1"><number:year number:style="long"/><number:text>/</number:text><number:month/><number:text>/</number:text><number:day/><number:text> </number:text><number:hours/><number:text>:</number:text><number:minutes number:style="long"/><number:text>:</number:text><number:seconds number:style="long"/></number:date-style>`},
+	} {
+		kind, dataStyle := odsDataStyleFromCode("N1", code)
+		assert.Equal(t, expected, []string{kind, dataStyle}, code)
+	}
+}
+
+func TestODSNumFmtCode(t *testing.T) {
+	r := &odsReader{dataStyles: make(map[string]*odsDataStyle)}
+	for content, expected := range map[string]string{
+		`<number:number-style><number:number number:min-integer-digits="0" number:grouping="true"/></number:number-style>`:                                                                                                                                              "#,###",
+		`<number:number-style><number:number/><number:text>x "y"</number:text></number:number-style>`:                                                                                                                                                                   `General"x "\""y"\"""`,
+		`<number:number-style><number:scientific-number number:decimal-places="1" number:min-integer-digits="1" number:min-exponent-digits="3"/></number:number-style>`:                                                                                                 "0.0E+000",
+		`<number:number-style><number:fraction number:min-integer-digits="0" number:min-numerator-digits="2" number:denominator-value="16"/></number:number-style>`:                                                                                                     "??/16",
+		`<number:number-style><number:fraction number:min-integer-digits="1"/></number:number-style>`:                                                                                                                                                                   "# ?/?",
+		`<number:currency-style><number:currency-symbol>€</number:currency-symbol><number:number number:decimal-places="2" number:min-integer-digits="1"/></number:currency-style>`:                                                                                     "[$€]0.00",
+		`<number:text-style><number:text-content/></number:text-style>`:                                                                                                                                                                                                 "@",
+		`<number:boolean-style><number:boolean/></number:boolean-style>`:                                                                                                                                                                                                `"TRUE";"TRUE";"FALSE"`,
+		`<number:date-style><number:day-of-week number:style="long"/><number:text>, </number:text><number:month number:textual="true" number:style="long"/><number:text> </number:text><number:day/><number:text> </number:text><number:year/></number:date-style>`:     "dddd, mmmm d yy",
+		`<number:time-style><number:hours number:style="long"/><number:text>:</number:text><number:minutes/><number:text>:</number:text><number:seconds number:style="long" number:decimal-places="2"/><number:text> </number:text><number:am-pm/></number:time-style>`: "hh:m:ss.00 AM/PM",
+		`<number:date-style><number:month number:textual="true"/></number:date-style>`:                                                                                                                                                                                  "mmm",
+		`<number:number-style><style:map style:apply-style-name="N0"/></number:number-style>`:                                                                                                                                                                           "",
+	} {
+		style := &odsDataStyle{}
+		assert.NoError(t, xml.Unmarshal([]byte(content), style))
+		r.dataStyles["N1"] = style
+		assert.Equal(t, expected, r.numFmtCode("N1", 0), content)
+	}
+	assert.Empty(t, r.numFmtCode("N2", 0))
+}
+
+func TestODSText(t *testing.T) {
+	assert.Equal(t, "<text:s/>a <text:s/>b <text:s text:c=\"2\"/>c&amp;<text:s/>", odsText(" a  b   c& "))
+	assert.Equal(t, 0.5, odsLengthToPixels("0.5px"))
+	assert.Equal(t, 0.0, odsLengthToPixels("apx"))
+	assert.Equal(t, 0.0, odsLengthToPixels("1em"))
+	assert.Equal(t, 0.5, odsPixelsToColWidth(6))
+	val, ok := odsDurationToExcelTime("-PT12H")
+	assert.True(t, ok)
+	assert.Equal(t, -0.5, val)
+	_, ok = odsDurationToExcelTime("P1D")
+	assert.False(t, ok)
+	_, ok = odsDateToExcelTime("15/03/2024")
+	assert.False(t, ok)
+}
+
+// prepareTestODS provides a function to create the OpenDocument Spreadsheet
+// package with the given parts for testing.
+func prepareTestODS(t *testing.T, parts map[string]string) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	fi, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	assert.NoError(t, err)
+	_, err = fi.Write([]byte(ContentTypeODS))
+	assert.NoError(t, err)
+	for name, content := range parts {
+		fi, err = zw.Create(name)
+		assert.NoError(t, err)
+		_, err = fi.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf
+}
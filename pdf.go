@@ -0,0 +1,1545 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// pdfPaperSizes defined the width and height in points of the paper sizes by
+// the paper size index of the page layout.
+var pdfPaperSizes = map[int][2]float64{
+	1: {612, 792}, 2: {612, 792}, 3: {792, 1224}, 4: {1224, 792}, 5: {612, 1008},
+	6: {396, 612}, 7: {522, 756}, 8: {841.89, 1190.55}, 9: {595.28, 841.89},
+	10: {595.28, 841.89}, 11: {419.53, 595.28}, 12: {708.66, 1000.63},
+	13: {498.9, 708.66}, 14: {612, 936}, 66: {1190.55, 1683.78},
+}
+
+// pdfBorderStyles defined the line width and dash pattern in points by the
+// index of the cell border styles.
+var pdfBorderStyles = map[int]struct {
+	width float64
+	dash  string
+}{
+	1: {0.5, ""}, 2: {1, ""}, 3: {0.5, "3 1"}, 4: {0.5, "1 1"}, 5: {1.5, ""},
+	6: {0.5, ""}, 7: {0.25, "0.5 0.5"}, 8: {1, "4 2"}, 9: {0.5, "3 1 1 1"},
+	10: {1, "4 2 1 2"}, 11: {0.5, "3 1 1 1 1 1"}, 12: {1, "4 2 1 2 1 2"},
+	13: {1, "4 1 2 1"},
+}
+
+// PDFOptions directly maps the settings of the PDF export.
+//
+// RangeRef specifies the cell range reference to be exported, such as
+// "A1:D10". The print area of the worksheet will be exported by default, and
+// the used range of the worksheet will be exported if the print area has not
+// been defined.
+//
+// Fonts specifies the TrueType font data by the font family name used to draw
+// the text, such as "Arial". The bold and italic variant of the font could be
+// specified with the "Bold", "Italic" or "Bold Italic" suffix, such as
+// "Arial Bold".
+//
+// FallbackFont specifies the font family name in the Fonts used to draw the
+// text of the font families which not been specified, such as "Arial". An
+// error will be returned if the font of the text to be drawn not been
+// specified in the Fonts.
+type PDFOptions struct {
+	RangeRef     string
+	Fonts        map[string][]byte
+	FallbackFont string
+}
+
+// pdfFont defined the TrueType font embedded in the PDF document, and the
+// glyphs used in the document.
+type pdfFont struct {
+	name    string
+	data    []byte
+	font    *sfnt.Font
+	buf     sfnt.Buffer
+	glyphs  map[sfnt.GlyphIndex]rune
+	widths  map[sfnt.GlyphIndex]float64
+	ascent  float64
+	descent float64
+	bbox    [4]float64
+}
+
+// pdfImage defined the image XObject embedded in the PDF document.
+type pdfImage struct {
+	name          string
+	data, mask    []byte
+	filter        string
+	colorSpace    string
+	width, height int
+}
+
+// pdfPicture defined the position and size in points of the picture on the
+// worksheet.
+type pdfPicture struct {
+	x, y, width, height float64
+	img                 *pdfImage
+}
+
+// pdfPage defined the cell range of the page in the exported range.
+type pdfPage struct {
+	rows, cols [2]int
+}
+
+// pdfCanvas defined the content stream of the page, the y-axis of the
+// coordinate system increases downward.
+type pdfCanvas struct {
+	bytes.Buffer
+}
+
+// pdfPainter defined the drawing operations of the canvas which the cells,
+// headers, footers and pictures drawn on. The unit of the coordinates is
+// point, and the y-axis of the coordinate system increases downward.
+type pdfPainter interface {
+	clip(rect [4]float64)
+	restore()
+	fillRect(rect [4]float64, hexColor string)
+	strokeLines(lines [][4]float64, width float64, dash, hexColor string)
+	fillText(pf *pdfFont, size float64, text string, x, y, angle float64, hexColor string)
+	drawImage(img *pdfImage, rect [4]float64)
+}
+
+// pdfExporter defined the state of the worksheet PDF export.
+type pdfExporter struct {
+	*htmlExporter
+	opts         PDFOptions
+	pageSize     [2]float64
+	margins      PageLayoutMarginsOptions
+	layout       PageLayoutOptions
+	headerFooter *HeaderFooterOptions
+	printOptions xlsxPrintOptions
+	overThenDown bool
+	fitToPage    bool
+	rowBreaks    map[int]bool
+	colBreaks    map[int]bool
+	titleRows    []int
+	titleCols    []int
+	colX, rowY   []float64
+	scale        float64
+	merges       [][]int
+	mergedCells  map[int]map[int]int
+	pdfStyles    map[int]*Style
+	defaultFont  Font
+	fonts        map[string]*pdfFont
+	fontList     []*pdfFont
+	images       map[string]*pdfImage
+	imageList    []*pdfImage
+	pictures     []pdfPicture
+	pages        []pdfPage
+	screen       bool
+}
+
+// ExportPDF provides a function to render the worksheet or a range of the
+// worksheet to io.Writer as a PDF document by given worksheet name and PDF
+// options. The page size, orientation, scaling, first page number and fit to
+// page settings of the page layout, the page margins, the headers and
+// footers, the manual page breaks, the print gridlines and centering
+// options, and the print area and print titles defined names will be
+// honoured. The cell values will be formatted with the number format of the
+// cells, and the fonts, fills, borders, alignment of the cells, merged cells
+// and pictures will be drawn. The hidden rows and columns will be skipped.
+// For example, render the worksheet named 'Sheet1' as a PDF document:
+//
+//	file, err := os.Create("invoice.pdf")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	if err := f.ExportPDF("Sheet1", file); err != nil {
+//	    fmt.Println(err)
+//	}
+//
+// The TrueType fonts data used to draw the text must be specified in the PDF
+// options, for example, draw the text with the Arial font, and draw the text
+// of the other font families with the Go fonts:
+//
+//	import (
+//	    "golang.org/x/image/font/gofont/gobold"
+//	    "golang.org/x/image/font/gofont/goregular"
+//	)
+//
+//	arial, err := os.ReadFile("arial.ttf")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.ExportPDF("Sheet1", file, excelize.PDFOptions{
+//	    Fonts: map[string][]byte{
+//	        "Arial":   arial,
+//	        "Go":      goregular.TTF,
+//	        "Go Bold": gobold.TTF,
+//	    },
+//	    FallbackFont: "Go",
+//	})
+//
+// The pictures in the worksheet will be drawn if the image format decoders
+// have been registered, for example, register the GIF, JPEG and PNG format
+// decoders:
+//
+//	import (
+//	    _ "image/gif"
+//	    _ "image/jpeg"
+//	    _ "image/png"
+//	)
+func (f *File) ExportPDF(sheet string, w io.Writer, opts ...PDFOptions) error {
+	e := newPDFExporter(f, sheet)
+	for _, opt := range opts {
+		e.opts = opt
+	}
+	if err := e.preparePageSetup(); err != nil {
+		return err
+	}
+	rangeRef, err := e.preparePrintNames()
+	if err != nil {
+		return err
+	}
+	if e.opts.RangeRef != "" {
+		rangeRef = e.opts.RangeRef
+	}
+	if err = e.prepareCells(rangeRef); err != nil {
+		return err
+	}
+	if err = e.preparePositions(); err != nil {
+		return err
+	}
+	if err = e.prepareMergeCells(); err != nil {
+		return err
+	}
+	if err = e.preparePictures(); err != nil {
+		return err
+	}
+	e.paginate()
+	contents := make([]*pdfCanvas, len(e.pages))
+	for i := range e.pages {
+		if contents[i], err = e.drawPage(i); err != nil {
+			return err
+		}
+	}
+	return e.writeDocument(w, contents)
+}
+
+// newPDFExporter provides a function to create the PDF exporter by given
+// worksheet name.
+func newPDFExporter(f *File, sheet string) *pdfExporter {
+	return &pdfExporter{
+		htmlExporter: &htmlExporter{
+			f: f, sheet: sheet, cells: make(map[int]map[int]xlsxC),
+			hiddenRows: make(map[int]bool), hiddenCols: make(map[int]bool),
+		},
+		rowBreaks: make(map[int]bool), colBreaks: make(map[int]bool),
+		mergedCells: make(map[int]map[int]int), pdfStyles: make(map[int]*Style),
+		fonts: make(map[string]*pdfFont), images: make(map[string]*pdfImage),
+	}
+}
+
+// preparePageSetup provides a function to get the page layout, page margins,
+// headers and footers, print options and page breaks of the worksheet.
+func (e *pdfExporter) preparePageSetup() error {
+	f := e.f
+	var err error
+	if e.layout, err = f.GetPageLayout(e.sheet); err != nil {
+		return err
+	}
+	if e.margins, err = f.GetPageMargins(e.sheet); err != nil {
+		return err
+	}
+	if e.headerFooter, err = f.GetHeaderFooter(e.sheet); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	ws, err := f.workSheetReader(e.sheet)
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	ws.mu.Lock()
+	if ws.PrintOptions != nil {
+		e.printOptions = *ws.PrintOptions
+	}
+	if ws.PageSetUp != nil {
+		e.overThenDown = ws.PageSetUp.PageOrder == "overThenDown"
+		if !ws.PageSetUp.UseFirstPageNumber {
+			e.layout.FirstPageNumber = uintPtr(1)
+		}
+	}
+	if ws.SheetPr != nil && ws.SheetPr.PageSetUpPr != nil {
+		e.fitToPage = ws.SheetPr.PageSetUpPr.FitToPage
+	}
+	if ws.RowBreaks != nil {
+		for _, brk := range ws.RowBreaks.Brk {
+			e.rowBreaks[brk.ID] = true
+		}
+	}
+	if ws.ColBreaks != nil {
+		for _, brk := range ws.ColBreaks.Brk {
+			e.colBreaks[brk.ID] = true
+		}
+	}
+	ws.mu.Unlock()
+	size, ok := pdfPaperSizes[*e.layout.Size]
+	if !ok {
+		size = pdfPaperSizes[1]
+	}
+	if *e.layout.Orientation == "landscape" {
+		size[0], size[1] = size[1], size[0]
+	}
+	e.pageSize = size
+	defaultStyle, err := f.GetStyle(0)
+	if err != nil {
+		return err
+	}
+	if defaultStyle.Font != nil {
+		e.defaultFont = *defaultStyle.Font
+	}
+	if e.defaultFont.Size == 0 {
+		e.defaultFont.Size = 11
+	}
+	return err
+}
+
+// preparePrintNames provides a function to get the print area and print
+// titles of the worksheet, and returns the cell range reference of the print
+// area.
+func (e *pdfExporter) preparePrintNames() (string, error) {
+	var rangeRef string
+	for _, dn := range e.f.GetDefinedName() {
+		if dn.Scope != e.sheet {
+			continue
+		}
+		for _, ref := range strings.Split(dn.RefersTo, ",") {
+			ref = strings.ReplaceAll(ref[strings.LastIndex(ref, "!")+1:], "$", "")
+			parts := strings.Split(ref, ":")
+			switch dn.Name {
+			case builtInDefinedNames[0]:
+				if rangeRef == "" {
+					rangeRef = ref
+				}
+			case builtInDefinedNames[1]:
+				if len(parts) != 2 {
+					continue
+				}
+				start, err1 := strconv.Atoi(parts[0])
+				end, err2 := strconv.Atoi(parts[1])
+				if err1 == nil && err2 == nil {
+					e.titleRows = []int{start, end}
+					continue
+				}
+				if start, err1 = ColumnNameToNumber(parts[0]); err1 != nil {
+					return rangeRef, err1
+				}
+				if end, err2 = ColumnNameToNumber(parts[1]); err2 != nil {
+					return rangeRef, err2
+				}
+				e.titleCols = []int{start, end}
+			}
+		}
+	}
+	for _, titles := range [][]int{e.titleRows, e.titleCols} {
+		if len(titles) == 2 && titles[0] > titles[1] {
+			titles[0], titles[1] = titles[1], titles[0]
+		}
+	}
+	return rangeRef, nil
+}
+
+// preparePositions provides a function to calculate the positions in points
+// of the columns and rows in the exported range and print titles.
+func (e *pdfExporter) preparePositions() error {
+	maxCol, maxRow := e.coordinates[2], e.coordinates[3]
+	if len(e.titleCols) == 2 && e.titleCols[1] > maxCol {
+		maxCol = e.titleCols[1]
+	}
+	if len(e.titleRows) == 2 && e.titleRows[1] > maxRow {
+		maxRow = e.titleRows[1]
+	}
+	e.colX, e.rowY = []float64{0, 0}, []float64{0, 0}
+	return e.extendPositions(maxCol, maxRow)
+}
+
+// extendPositions provides a function to calculate the positions in points
+// of the columns and rows until the given column and row number, the width
+// and height of the hidden columns and rows are zero.
+func (e *pdfExporter) extendPositions(maxCol, maxRow int) error {
+	for col := len(e.colX) - 1; col <= maxCol && col <= MaxColumns; col++ {
+		pos := e.colX[col]
+		if !e.hiddenCols[col] {
+			name, _ := ColumnNumberToName(col)
+			width, err := e.f.GetColWidth(e.sheet, name)
+			if err != nil {
+				return err
+			}
+			px := defaultColWidthPixels
+			if width != defaultColWidth {
+				px = convertColWidthToPixels(width)
+			}
+			pos += px * 0.75
+		}
+		e.colX = append(e.colX, pos)
+	}
+	for row := len(e.rowY) - 1; row <= maxRow && row <= TotalRows; row++ {
+		pos := e.rowY[row]
+		if !e.hiddenRows[row] {
+			height, err := e.f.GetRowHeight(e.sheet, row)
+			if err != nil {
+				return err
+			}
+			pos += height
+		}
+		e.rowY = append(e.rowY, pos)
+	}
+	return nil
+}
+
+// prepareMergeCells provides a function to get the merged cells in the
+// exported range, the merged cells will be clipped by the exported range.
+func (e *pdfExporter) prepareMergeCells() error {
+	mergeCells, err := e.f.GetMergeCells(e.sheet)
+	if err != nil {
+		return err
+	}
+	for _, mc := range mergeCells {
+		ref := mc[0]
+		if !strings.Contains(ref, ":") {
+			ref += ":" + ref
+		}
+		coordinates, err := rangeRefToCoordinates(ref)
+		if err != nil {
+			return err
+		}
+		_ = sortCoordinates(coordinates)
+		if coordinates[2] < e.coordinates[0] || coordinates[0] > e.coordinates[2] ||
+			coordinates[3] < e.coordinates[1] || coordinates[1] > e.coordinates[3] {
+			continue
+		}
+		for i := 0; i < 2; i++ {
+			if coordinates[i] < e.coordinates[i] {
+				coordinates[i] = e.coordinates[i]
+			}
+			if coordinates[i+2] > e.coordinates[i+2] {
+				coordinates[i+2] = e.coordinates[i+2]
+			}
+		}
+		for row := coordinates[1]; row <= coordinates[3]; row++ {
+			if e.mergedCells[row] == nil {
+				e.mergedCells[row] = make(map[int]int)
+			}
+			for col := coordinates[0]; col <= coordinates[2]; col++ {
+				e.mergedCells[row][col] = len(e.merges)
+			}
+		}
+		e.merges = append(e.merges, coordinates)
+	}
+	return err
+}
+
+// preparePictures provides a function to get the printable pictures in the
+// drawing part of the worksheet, and calculate the positions and sizes of
+// the pictures. The non-printable pictures will also be drawn when drawing
+// the worksheet as displayed on the screen.
+func (e *pdfExporter) preparePictures() error {
+	f := e.f
+	f.mu.Lock()
+	ws, err := f.workSheetReader(e.sheet)
+	f.mu.Unlock()
+	if err != nil || ws.Drawing == nil || e.coordinates[0] == 0 {
+		return err
+	}
+	target := f.getSheetRelationshipsTargetByID(e.sheet, ws.Drawing.RID)
+	drawingXML := strings.TrimPrefix(strings.ReplaceAll(target, "..", "xl"), "/")
+	drawingRelationships := strings.ReplaceAll(
+		strings.ReplaceAll(target, "../drawings", "xl/drawings/_rels"), ".xml", ".xml.rels")
+	wsDr, _, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return err
+	}
+	wsDr.mu.Lock()
+	defer wsDr.mu.Unlock()
+	cond := func(from *xlsxFrom) bool { return true }
+	cond2 := func(from *decodeFrom) bool { return true }
+	cb := func(a *xdrCellAnchor, r *xlsxRelationship) {
+		if !e.screen && a.ClientData != nil && !a.ClientData.FPrintsWithSheet {
+			return
+		}
+		to := []int{-1}
+		if a.To != nil {
+			to = []int{a.To.Col, a.To.ColOff, a.To.Row, a.To.RowOff}
+		}
+		ext := a.Pic.SpPr.Xfrm.Ext
+		if a.Ext != nil && (ext.Cx == 0 || ext.Cy == 0) {
+			ext = *a.Ext
+		}
+		err = e.addPicture(r.Target, []int{a.From.Col, a.From.ColOff, a.From.Row, a.From.RowOff}, to, ext.Cx, ext.Cy)
+	}
+	cb2 := func(a *decodeCellAnchor, r *xlsxRelationship) {
+		if !e.screen && a.ClientData != nil && !a.ClientData.FPrintsWithSheet {
+			return
+		}
+		to := []int{-1}
+		if a.To != nil {
+			to = []int{a.To.Col, a.To.ColOff, a.To.Row, a.To.RowOff}
+		}
+		err = e.addPicture(r.Target, []int{a.From.Col, a.From.ColOff, a.From.Row, a.From.RowOff}, to,
+			a.Pic.SpPr.Xfrm.Ext.Cx, a.Pic.SpPr.Xfrm.Ext.Cy)
+	}
+	for _, anchor := range append(wsDr.TwoCellAnchor, wsDr.OneCellAnchor...) {
+		if f.extractCellAnchor(anchor, drawingRelationships, cond, cb, cond2, cb2); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// addPicture provides a function to add the picture by given the picture
+// target in the drawing relationships, the starting and ending anchors and
+// the size in EMUs of the picture.
+func (e *pdfExporter) addPicture(target string, from, to []int, cx, cy int) error {
+	maxCol, maxRow := from[0]+1, from[2]+1
+	if to[0] != -1 {
+		maxCol, maxRow = to[0]+1, to[2]+1
+	}
+	if err := e.extendPositions(maxCol, maxRow); err != nil || from[0] >= MaxColumns || from[2] >= TotalRows {
+		return err
+	}
+	pic := pdfPicture{
+		x: e.colX[from[0]+1] + float64(from[1])/12700,
+		y: e.rowY[from[2]+1] + float64(from[3])/12700,
+	}
+	pic.width, pic.height = float64(cx)/12700, float64(cy)/12700
+	if (cx == 0 || cy == 0) && to[0] != -1 && to[0] < MaxColumns && to[2] < TotalRows {
+		pic.width = e.colX[to[0]+1] + float64(to[1])/12700 - pic.x
+		pic.height = e.rowY[to[2]+1] + float64(to[3])/12700 - pic.y
+	}
+	path, _ := filepath.Abs("/xl/drawings/" + target)
+	path = strings.TrimPrefix(path, "/")
+	img, ok := e.images[path]
+	if !ok {
+		buffer, _ := e.f.Pkg.Load(path)
+		data, _ := buffer.([]byte)
+		var err error
+		if img, err = newPDFImage(data); err != nil {
+			return err
+		}
+		if img != nil {
+			img.name = "Im" + strconv.Itoa(len(e.imageList)+1)
+			e.imageList = append(e.imageList, img)
+		}
+		e.images[path] = img
+	}
+	if img != nil && pic.width > 0 && pic.height > 0 {
+		pic.img = img
+		e.pictures = append(e.pictures, pic)
+	}
+	return nil
+}
+
+// paginate provides a function to split the exported range into pages by the
+// printable size of the page, the scaling and the manual page breaks.
+func (e *pdfExporter) paginate() {
+	availWidth := e.pageSize[0] - (*e.margins.Left+*e.margins.Right)*72
+	availHeight := e.pageSize[1] - (*e.margins.Top+*e.margins.Bottom)*72
+	e.scale = 1
+	if e.layout.AdjustTo != nil {
+		e.scale = float64(*e.layout.AdjustTo) / 100
+	}
+	if e.coordinates[0] == 0 {
+		e.pages = []pdfPage{{}}
+		return
+	}
+	contentWidth := e.colX[e.coordinates[2]+1] - e.colX[e.coordinates[0]]
+	contentHeight := e.rowY[e.coordinates[3]+1] - e.rowY[e.coordinates[1]]
+	if e.fitToPage {
+		e.scale = 1
+		fit := func(pages *int, avail, content float64) {
+			num := 1
+			if pages != nil {
+				num = *pages
+			}
+			if num > 0 && content > 0 && avail*float64(num)/content < e.scale {
+				e.scale = avail * float64(num) / content
+			}
+		}
+		fit(e.layout.FitToWidth, availWidth, contentWidth)
+		fit(e.layout.FitToHeight, availHeight, contentHeight)
+	}
+	split := func(start, end int, pos []float64, breaks map[int]bool, avail float64, titles []int) [][2]int {
+		if len(titles) == 2 {
+			avail -= pos[titles[1]+1] - pos[titles[0]]
+		}
+		var chunks [][2]int
+		first := start
+		for i := start; i <= end; i++ {
+			if i > first && (pos[i+1]-pos[first] > avail || breaks[i-1]) {
+				chunks = append(chunks, [2]int{first, i - 1})
+				first = i
+			}
+		}
+		return append(chunks, [2]int{first, end})
+	}
+	rows := split(e.coordinates[1], e.coordinates[3], e.rowY, e.rowBreaks, availHeight/e.scale, e.titleRows)
+	cols := split(e.coordinates[0], e.coordinates[2], e.colX, e.colBreaks, availWidth/e.scale, e.titleCols)
+	if e.overThenDown {
+		for _, row := range rows {
+			for _, col := range cols {
+				e.pages = append(e.pages, pdfPage{rows: row, cols: col})
+			}
+		}
+		return
+	}
+	for _, col := range cols {
+		for _, row := range rows {
+			e.pages = append(e.pages, pdfPage{rows: row, cols: col})
+		}
+	}
+}
+
+// drawPage provides a function to draw the headers, footers and cells of the
+// page by given page index.
+func (e *pdfExporter) drawPage(index int) (*pdfCanvas, error) {
+	canvas := &pdfCanvas{}
+	canvas.WriteString("1 0 0 -1 0 " + pdfNum(e.pageSize[1]) + " cm\n")
+	if err := e.drawHeaderFooter(canvas, index); err != nil {
+		return canvas, err
+	}
+	page := e.pages[index]
+	if page.rows[0] == 0 {
+		return canvas, nil
+	}
+	type block struct{ rows, cols [2]int }
+	rowBands, colBands := [][2]int{page.rows}, [][2]int{page.cols}
+	if len(e.titleRows) == 2 && page.rows[0] > e.titleRows[1] {
+		rowBands = append([][2]int{{e.titleRows[0], e.titleRows[1]}}, rowBands...)
+	}
+	if len(e.titleCols) == 2 && page.cols[0] > e.titleCols[1] {
+		colBands = append([][2]int{{e.titleCols[0], e.titleCols[1]}}, colBands...)
+	}
+	var width, height float64
+	for _, cols := range colBands {
+		width += e.colX[cols[1]+1] - e.colX[cols[0]]
+	}
+	for _, rows := range rowBands {
+		height += e.rowY[rows[1]+1] - e.rowY[rows[0]]
+	}
+	left, top := *e.margins.Left*72, *e.margins.Top*72
+	if e.printOptions.HorizontalCentered {
+		left += (e.pageSize[0] - (*e.margins.Left+*e.margins.Right)*72 - width*e.scale) / 2
+	}
+	if e.printOptions.VerticalCentered {
+		top += (e.pageSize[1] - (*e.margins.Top+*e.margins.Bottom)*72 - height*e.scale) / 2
+	}
+	canvas.WriteString("q " + pdfNum(e.scale, 0, 0, e.scale, left, top) + " cm\n")
+	var y float64
+	for _, rows := range rowBands {
+		var x float64
+		for _, cols := range colBands {
+			if err := e.drawBlock(canvas, rows, cols, x, y); err != nil {
+				return canvas, err
+			}
+			x += e.colX[cols[1]+1] - e.colX[cols[0]]
+		}
+		y += e.rowY[rows[1]+1] - e.rowY[rows[0]]
+	}
+	canvas.WriteString("Q\n")
+	return canvas, nil
+}
+
+// drawBlock provides a function to draw the cells, merged cells and pictures
+// in the cell range of the page at the given position.
+func (e *pdfExporter) drawBlock(canvas pdfPainter, rows, cols [2]int, x, y float64) error {
+	width, height := e.colX[cols[1]+1]-e.colX[cols[0]], e.rowY[rows[1]+1]-e.rowY[rows[0]]
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	rect := func(col1, row1, col2, row2 int) [4]float64 {
+		return [4]float64{
+			x + e.colX[col1] - e.colX[cols[0]], y + e.rowY[row1] - e.rowY[rows[0]],
+			e.colX[col2+1] - e.colX[col1], e.rowY[row2+1] - e.rowY[row1],
+		}
+	}
+	canvas.clip([4]float64{x, y, width, height})
+	var merges []int
+	for idx, mc := range e.merges {
+		if mc[2] >= cols[0] && mc[0] <= cols[1] && mc[3] >= rows[0] && mc[1] <= rows[1] {
+			merges = append(merges, idx)
+		}
+	}
+	if e.printOptions.GridLines {
+		canvas.strokeLines(e.gridLines(rows, cols, merges, x, y), 0.25, "", "BFBFBF")
+	}
+	// Draw the fills of the cells and merged cells
+	for _, idx := range merges {
+		mc := e.merges[idx]
+		if err := e.drawFill(canvas, e.cells[mc[1]][mc[0]].S, rect(mc[0], mc[1], mc[2], mc[3])); err != nil {
+			return err
+		}
+	}
+	for row := rows[0]; row <= rows[1]; row++ {
+		for col := cols[0]; col <= cols[1]; col++ {
+			if _, merged := e.mergedCells[row][col]; merged {
+				continue
+			}
+			if c, ok := e.cells[row][col]; ok {
+				if err := e.drawFill(canvas, c.S, rect(col, row, col, row)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	// Draw the borders of the cells
+	for row := rows[0]; row <= rows[1]; row++ {
+		for col := cols[0]; col <= cols[1]; col++ {
+			if c, ok := e.cells[row][col]; ok && c.S != 0 {
+				if err := e.drawBorders(canvas, c.S, rect(col, row, col, row)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	// Draw the text of the cells and merged cells
+	for _, idx := range merges {
+		mc := e.merges[idx]
+		if err := e.drawText(canvas, mc[0], mc[1], rect(mc[0], mc[1], mc[2], mc[3]), rect(mc[0], mc[1], mc[2], mc[3])); err != nil {
+			return err
+		}
+	}
+	for row := rows[0]; row <= rows[1]; row++ {
+		for col := cols[0]; col <= cols[1]; col++ {
+			if _, merged := e.mergedCells[row][col]; merged || e.hiddenRows[row] || e.hiddenCols[col] {
+				continue
+			}
+			if _, ok := e.cells[row][col]; ok {
+				left, right := e.overflowCols(row, col, cols)
+				if err := e.drawText(canvas, col, row, rect(col, row, col, row), rect(left, row, right, row)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	// Draw the pictures
+	for _, pic := range e.pictures {
+		px, py := x+pic.x-e.colX[cols[0]], y+pic.y-e.rowY[rows[0]]
+		if px+pic.width <= x || px >= x+width || py+pic.height <= y || py >= y+height {
+			continue
+		}
+		canvas.drawImage(pic.img, [4]float64{px, py, pic.width, pic.height})
+	}
+	canvas.restore()
+	return nil
+}
+
+// gridLines returns the gridlines of the block of the cells, the gridlines
+// inside the merged cells will be skipped.
+func (e *pdfExporter) gridLines(rows, cols [2]int, merges []int, x, y float64) [][4]float64 {
+	inside := func(col, row int, vertical bool) bool {
+		for _, idx := range merges {
+			mc := e.merges[idx]
+			if vertical && mc[0] < col && col <= mc[2] && mc[1] <= row && row <= mc[3] {
+				return true
+			}
+			if !vertical && mc[1] < row && row <= mc[3] && mc[0] <= col && col <= mc[2] {
+				return true
+			}
+		}
+		return false
+	}
+	var lines [][4]float64
+	for col := cols[0]; col <= cols[1]+1; col++ {
+		px := x + e.colX[col] - e.colX[cols[0]]
+		for row := rows[0]; row <= rows[1]; row++ {
+			if inside(col, row, true) {
+				continue
+			}
+			y1, y2 := y+e.rowY[row]-e.rowY[rows[0]], y+e.rowY[row+1]-e.rowY[rows[0]]
+			if n := len(lines); n > 0 && lines[n-1][0] == px && lines[n-1][2] == px && lines[n-1][3] == y1 {
+				lines[n-1][3] = y2
+				continue
+			}
+			lines = append(lines, [4]float64{px, y1, px, y2})
+		}
+	}
+	for row := rows[0]; row <= rows[1]+1; row++ {
+		py := y + e.rowY[row] - e.rowY[rows[0]]
+		for col := cols[0]; col <= cols[1]; col++ {
+			if inside(col, row, false) {
+				continue
+			}
+			x1, x2 := x+e.colX[col]-e.colX[cols[0]], x+e.colX[col+1]-e.colX[cols[0]]
+			if n := len(lines); n > 0 && lines[n-1][1] == py && lines[n-1][3] == py && lines[n-1][2] == x1 {
+				lines[n-1][2] = x2
+				continue
+			}
+			lines = append(lines, [4]float64{x1, py, x2, py})
+		}
+	}
+	return lines
+}
+
+// overflowCols returns the range of the columns which the text of the cell
+// could overflow into, the text of the cell without wrapping can overflow
+// into the adjacent empty cells.
+func (e *pdfExporter) overflowCols(row, col int, cols [2]int) (int, int) {
+	isEmpty := func(c int) bool {
+		if _, merged := e.mergedCells[row][c]; merged {
+			return false
+		}
+		cell, ok := e.cells[row][c]
+		return !ok || (cell.V == "" && cell.F == nil && cell.IS == nil)
+	}
+	left, right := col, col
+	for left > cols[0] && isEmpty(left-1) {
+		left--
+	}
+	for right < cols[1] && isEmpty(right+1) {
+		right++
+	}
+	return left, right
+}
+
+// style provides a function to get the cell style by given style index.
+func (e *pdfExporter) style(styleID int) (*Style, error) {
+	if style, ok := e.pdfStyles[styleID]; ok {
+		return style, nil
+	}
+	style, err := e.f.GetStyle(styleID)
+	if err != nil {
+		return nil, err
+	}
+	e.pdfStyles[styleID] = style
+	return style, err
+}
+
+// drawFill provides a function to fill the cell area by the fill color of
+// the cell style.
+func (e *pdfExporter) drawFill(canvas pdfPainter, styleID int, rect [4]float64) error {
+	style, err := e.style(styleID)
+	if err != nil {
+		return err
+	}
+	var fillColor string
+	if len(style.Fill.Color) > 0 && (style.Fill.Type == "gradient" || style.Fill.Pattern > 0) {
+		fillColor = e.color(style.Fill.Color[0], 0, nil, 0)
+	}
+	if fillColor != "" {
+		canvas.fillRect(rect, fillColor)
+	}
+	return err
+}
+
+// drawBorders provides a function to draw the borders of the cell by the
+// border settings of the cell style.
+func (e *pdfExporter) drawBorders(canvas pdfPainter, styleID int, rect [4]float64) error {
+	style, err := e.style(styleID)
+	if err != nil {
+		return err
+	}
+	x1, y1, x2, y2 := rect[0], rect[1], rect[0]+rect[2], rect[1]+rect[3]
+	for _, border := range style.Border {
+		line, ok := pdfBorderStyles[border.Style]
+		if !ok {
+			continue
+		}
+		points, ok := map[string][4]float64{
+			"left": {x1, y1, x1, y2}, "right": {x2, y1, x2, y2}, "top": {x1, y1, x2, y1},
+			"bottom": {x1, y2, x2, y2}, "diagonalDown": {x1, y1, x2, y2}, "diagonalUp": {x1, y2, x2, y1},
+		}[border.Type]
+		if !ok {
+			continue
+		}
+		borderColor := e.color(border.Color, 0, nil, 0)
+		if borderColor == "" {
+			borderColor = "000000"
+		}
+		if border.Style != 6 {
+			canvas.strokeLines([][4]float64{points}, line.width, line.dash, borderColor)
+			continue
+		}
+		dx, dy := 0.0, 0.75
+		if points[0] == points[2] {
+			dx, dy = 0.75, 0
+		}
+		var lines [][4]float64
+		for _, sign := range []float64{-1, 1} {
+			lines = append(lines, [4]float64{points[0] + sign*dx, points[1] + sign*dy, points[2] + sign*dx, points[3] + sign*dy})
+		}
+		canvas.strokeLines(lines, line.width, line.dash, borderColor)
+	}
+	return err
+}
+
+// drawText provides a function to draw the formatted value of the cell in
+// the cell area by the font and alignment settings of the cell style, and
+// the text will be clipped by the given clip area.
+func (e *pdfExporter) drawText(canvas pdfPainter, col, row int, rect, clip [4]float64) error {
+	c, ok := e.cells[row][col]
+	if !ok {
+		return nil
+	}
+	value, err := c.getValueFrom(e.f, e.sst, false)
+	if err != nil || value == "" {
+		return err
+	}
+	style, err := e.style(c.S)
+	if err != nil {
+		return err
+	}
+	fontStyle := e.defaultFont
+	if style.Font != nil {
+		fontStyle = *style.Font
+		if fontStyle.Family == "" {
+			fontStyle.Family = e.defaultFont.Family
+		}
+		if fontStyle.Size == 0 {
+			fontStyle.Size = e.defaultFont.Size
+		}
+	}
+	pf, err := e.font(fontStyle.Family, fontStyle.Bold, fontStyle.Italic)
+	if err != nil {
+		return err
+	}
+	alignment := style.Alignment
+	if alignment == nil {
+		alignment = &Alignment{}
+	}
+	horizontal := alignment.Horizontal
+	if horizontal == "" || horizontal == "general" {
+		horizontal = "left"
+		if c.T == "b" || c.T == "e" {
+			horizontal = "center"
+		} else if isNum, _, _ := isNumeric(c.V); isNum && c.T != "s" && c.T != "str" && c.T != "inlineStr" {
+			horizontal = "right"
+		}
+	}
+	size, padding := fontStyle.Size, 2.0
+	indent := float64(alignment.Indent) * 6.75
+	lines := pf.wrapText(value, size, rect[2]-2*padding-indent, alignment.WrapText)
+	if !alignment.WrapText && alignment.TextRotation == 0 {
+		if horizontal == "left" {
+			clip[2] = clip[0] + clip[2] - rect[0]
+			clip[0] = rect[0]
+		}
+		if horizontal == "right" {
+			clip[2] = rect[0] + rect[2] - clip[0]
+		}
+	}
+	if alignment.WrapText || len(lines) > 1 {
+		clip = rect
+	}
+	lineHeight := size * 1.2
+	textColor := "000000"
+	if fontColor := e.color(fontStyle.Color, fontStyle.ColorIndexed, fontStyle.ColorTheme, fontStyle.ColorTint); fontColor != "" {
+		textColor = fontColor
+	}
+	canvas.clip(clip)
+	defer canvas.restore()
+	if rotation := alignment.TextRotation; rotation != 0 && rotation != 255 {
+		angle := float64(rotation)
+		if rotation > 90 {
+			angle = 90 - angle
+		}
+		angle = angle * math.Pi / 180
+		text := strings.Join(lines, " ")
+		width := pf.width(text, size)
+		cos, sin := math.Cos(angle), math.Sin(angle)
+		cx, cy := rect[0]+rect[2]/2, rect[1]+rect[3]/2
+		baseline := (pf.ascent - pf.descent) / 2 * size
+		x := cx - cos*width/2 - sin*baseline
+		y := cy + sin*width/2 - cos*baseline
+		canvas.fillText(pf, size, text, x+sin*size*pf.ascent, y+cos*size*pf.ascent, angle, textColor)
+		return err
+	}
+	if alignment.TextRotation == 255 {
+		var stacked []string
+		for _, r := range strings.Join(lines, "") {
+			stacked = append(stacked, string(r))
+		}
+		lines, horizontal = stacked, "center"
+	}
+	var top float64
+	switch alignment.Vertical {
+	case "top":
+		top = rect[1] + padding
+	case "center", "justify", "distributed":
+		top = rect[1] + (rect[3]-lineHeight*float64(len(lines)))/2
+	default:
+		top = rect[1] + rect[3] - padding/2 - lineHeight*float64(len(lines))
+	}
+	for i, line := range lines {
+		width := pf.width(line, size)
+		x := rect[0] + padding + indent
+		switch horizontal {
+		case "center", "centerContinuous":
+			x = rect[0] + (rect[2]-width)/2
+		case "right":
+			x = rect[0] + rect[2] - padding - indent - width
+		}
+		baseline := top + float64(i)*lineHeight + (lineHeight-(pf.ascent-pf.descent)*size)/2 + pf.ascent*size
+		canvas.fillText(pf, size, line, x, baseline, 0, textColor)
+		var decorations [][4]float64
+		if fontStyle.Underline != "" && fontStyle.Underline != "none" {
+			decorations = append(decorations, [4]float64{x, baseline + size*0.1, x + width, baseline + size*0.1})
+		}
+		if fontStyle.Strike {
+			decorations = append(decorations, [4]float64{x, baseline - size*0.3, x + width, baseline - size*0.3})
+		}
+		if len(decorations) > 0 {
+			canvas.strokeLines(decorations, size*0.05, "", textColor)
+		}
+	}
+	return err
+}
+
+// drawHeaderFooter provides a function to draw the header and footer of the
+// page by given page index.
+func (e *pdfExporter) drawHeaderFooter(canvas pdfPainter, index int) error {
+	hf := e.headerFooter
+	header, footer := hf.OddHeader, hf.OddFooter
+	if hf.DifferentOddEven && index%2 == 1 {
+		header, footer = hf.EvenHeader, hf.EvenFooter
+	}
+	if hf.DifferentFirst && index == 0 {
+		header, footer = hf.FirstHeader, hf.FirstFooter
+	}
+	pf, err := e.font(e.defaultFont.Family, false, false)
+	if err != nil {
+		return err
+	}
+	size := e.defaultFont.Size
+	left, right := *e.margins.Left*72, e.pageSize[0]-*e.margins.Right*72
+	for i, text := range []string{header, footer} {
+		for section, value := range e.headerFooterSections(text, index) {
+			lines := strings.Split(value, "\n")
+			for j, line := range lines {
+				width := pf.width(line, size)
+				x := []float64{left, (e.pageSize[0] - width) / 2, right - width}[section]
+				y := *e.margins.Header*72 + float64(j)*size*1.2 + pf.ascent*size
+				if i == 1 {
+					y = e.pageSize[1] - *e.margins.Footer*72 - float64(len(lines)-1-j)*size*1.2 + pf.descent*size
+				}
+				if line != "" {
+					canvas.fillText(pf, size, line, x, y, 0, "000000")
+				}
+			}
+		}
+	}
+	return err
+}
+
+// headerFooterSections provides a function to parse the left, center and
+// right sections of the header or footer, and replace the page number, total
+// pages, date, time, file name and worksheet name codes by given page index.
+func (e *pdfExporter) headerFooterSections(text string, index int) [3]string {
+	var (
+		sections [3]strings.Builder
+		section  = 1
+	)
+	fileName := "Book1"
+	if e.f.Path != "" {
+		fileName = filepath.Base(e.f.Path)
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] != '&' || i+1 >= len(text) {
+			sections[section].WriteByte(text[i])
+			continue
+		}
+		i++
+		switch code := text[i]; code {
+		case 'L', 'C', 'R':
+			section = strings.IndexByte("LCR", code)
+		case 'P':
+			sections[section].WriteString(strconv.Itoa(int(*e.layout.FirstPageNumber) + index))
+		case 'N':
+			sections[section].WriteString(strconv.Itoa(len(e.pages) + int(*e.layout.FirstPageNumber) - 1))
+		case 'D':
+			sections[section].WriteString(time.Now().Format("1/2/2006"))
+		case 'T':
+			sections[section].WriteString(time.Now().Format("3:04 PM"))
+		case 'A':
+			sections[section].WriteString(e.sheet)
+		case 'F':
+			sections[section].WriteString(fileName)
+		case 'Z':
+			if e.f.Path != "" {
+				sections[section].WriteString(filepath.Dir(e.f.Path) + string(filepath.Separator))
+			}
+		case '&':
+			sections[section].WriteByte('&')
+		case '"':
+			if end := strings.IndexByte(text[i+1:], '"'); end != -1 {
+				i += end + 1
+			}
+		case 'K':
+			i += 6
+		default:
+			for code >= '0' && code <= '9' && i+1 < len(text) && text[i+1] >= '0' && text[i+1] <= '9' {
+				i++
+			}
+		}
+	}
+	return [3]string{sections[0].String(), sections[1].String(), sections[2].String()}
+}
+
+// font provides a function to get the embedded font by given font family
+// name, bold and italic settings. The TrueType font data specified in the
+// PDF options will be used first, and the fallback font will be used if the
+// font family not been specified.
+func (e *pdfExporter) font(family string, bold, italic bool) (*pdfFont, error) {
+	var suffix string
+	if bold {
+		suffix = " Bold"
+	}
+	if italic {
+		suffix += " Italic"
+	}
+	var data []byte
+	key, names := family+suffix, []string{family + suffix, family}
+	if fallback := e.opts.FallbackFont; fallback != "" {
+		names = append(names, fallback+suffix, fallback)
+	}
+	for _, name := range names {
+		if data = e.opts.Fonts[name]; data != nil {
+			key = name
+			break
+		}
+	}
+	if data == nil {
+		return nil, newNoExistFontError(family)
+	}
+	if pf, ok := e.fonts[key]; ok {
+		return pf, nil
+	}
+	pf, err := newPDFFont(data)
+	if err != nil {
+		return nil, err
+	}
+	pf.name = "F" + strconv.Itoa(len(e.fontList)+1)
+	e.fonts[key] = pf
+	e.fontList = append(e.fontList, pf)
+	return pf, err
+}
+
+// writeDocument provides a function to write the PDF document with the pages
+// content streams, fonts and images.
+func (e *pdfExporter) writeDocument(w io.Writer, contents []*pdfCanvas) error {
+	pw := &pdfWriter{w: bufio.NewWriter(w)}
+	pw.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	catalog, pages, resources := pw.alloc(), pw.alloc(), pw.alloc()
+	pageIDs := make([]int, len(contents))
+	for i := range contents {
+		pageIDs[i] = pw.alloc()
+	}
+	var res strings.Builder
+	res.WriteString("<< /ProcSet [/PDF /Text /ImageB /ImageC] /Font <<")
+	fontIDs := make([]int, len(e.fontList))
+	for i, pf := range e.fontList {
+		fontIDs[i] = pw.alloc()
+		fmt.Fprintf(&res, " /%s %d 0 R", pf.name, fontIDs[i])
+	}
+	res.WriteString(" >> /XObject <<")
+	imageIDs := make([]int, len(e.imageList))
+	for i, img := range e.imageList {
+		imageIDs[i] = pw.alloc()
+		fmt.Fprintf(&res, " /%s %d 0 R", img.name, imageIDs[i])
+	}
+	res.WriteString(" >> >>")
+	pw.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+	var kids strings.Builder
+	for _, id := range pageIDs {
+		fmt.Fprintf(&kids, "%d 0 R ", id)
+	}
+	pw.object(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s] >>",
+		strings.TrimSpace(kids.String()), len(pageIDs), pdfNum(e.pageSize[0], e.pageSize[1])))
+	pw.object(resources, res.String())
+	for i, content := range contents {
+		contentID := pw.alloc()
+		pw.object(pageIDs[i], fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Resources %d 0 R /Contents %d 0 R >>", pages, resources, contentID))
+		if err := pw.stream(contentID, "", content.Bytes(), true); err != nil {
+			return err
+		}
+	}
+	for i, pf := range e.fontList {
+		if err := pw.writeFont(fontIDs[i], pf); err != nil {
+			return err
+		}
+	}
+	for i, img := range e.imageList {
+		if err := pw.writeImage(imageIDs[i], img); err != nil {
+			return err
+		}
+	}
+	return pw.finish(catalog)
+}
+
+// newPDFFont provides a function to parse the TrueType font by given font
+// data.
+func newPDFFont(data []byte) (*pdfFont, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	pf := &pdfFont{data: data, font: f, glyphs: make(map[sfnt.GlyphIndex]rune), widths: make(map[sfnt.GlyphIndex]float64)}
+	ppem := fixed.I(1000)
+	metrics, err := f.Metrics(&pf.buf, ppem, font.HintingNone)
+	if err != nil {
+		return nil, err
+	}
+	pf.ascent, pf.descent = float64(metrics.Ascent)/64000, -float64(metrics.Descent)/64000
+	bounds, err := f.Bounds(&pf.buf, ppem, font.HintingNone)
+	if err != nil {
+		return nil, err
+	}
+	pf.bbox = [4]float64{float64(bounds.Min.X) / 64, -float64(bounds.Max.Y) / 64, float64(bounds.Max.X) / 64, -float64(bounds.Min.Y) / 64}
+	return pf, err
+}
+
+// glyph returns the glyph index and the advance width in thousandths of the
+// em by given rune, and records the glyph used in the document.
+func (pf *pdfFont) glyph(r rune) (sfnt.GlyphIndex, float64) {
+	idx, err := pf.font.GlyphIndex(&pf.buf, r)
+	if err != nil {
+		return 0, 0
+	}
+	if width, ok := pf.widths[idx]; ok {
+		return idx, width
+	}
+	advance, err := pf.font.GlyphAdvance(&pf.buf, idx, fixed.I(1000), font.HintingNone)
+	if err != nil {
+		return idx, 0
+	}
+	pf.widths[idx] = float64(advance) / 64
+	if idx != 0 {
+		pf.glyphs[idx] = r
+	}
+	return idx, pf.widths[idx]
+}
+
+// width returns the width in points of the text by given font size.
+func (pf *pdfFont) width(text string, size float64) float64 {
+	var width float64
+	for _, r := range text {
+		_, w := pf.glyph(r)
+		width += w
+	}
+	return width * size / 1000
+}
+
+// encode returns the hexadecimal string of the glyph indexes of the text.
+func (pf *pdfFont) encode(text string) string {
+	var buf strings.Builder
+	buf.WriteByte('<')
+	for _, r := range text {
+		idx, _ := pf.glyph(r)
+		fmt.Fprintf(&buf, "%04X", uint16(idx))
+	}
+	buf.WriteByte('>')
+	return buf.String()
+}
+
+// wrapText provides a function to split the text into lines by the line
+// breaks, and wrap the lines by given width in points if the wrap parameter
+// is true.
+func (pf *pdfFont) wrapText(text string, size, width float64, wrap bool) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		if !wrap || pf.width(paragraph, size) <= width {
+			lines = append(lines, paragraph)
+			continue
+		}
+		var line string
+		for i, word := range strings.Split(paragraph, " ") {
+			next := word
+			if i > 0 {
+				next = line + " " + word
+			}
+			if i > 0 && line != "" && pf.width(next, size) > width {
+				lines = append(lines, line)
+				next = word
+			}
+			for runes := []rune(next); len(runes) > 1 && pf.width(next, size) > width; runes = []rune(next) {
+				n := len(runes) - 1
+				for n > 1 && pf.width(string(runes[:n]), size) > width {
+					n--
+				}
+				lines = append(lines, string(runes[:n]))
+				next = string(runes[n:])
+			}
+			line = next
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// newPDFImage provides a function to create the image XObject by given image
+// data, the JPEG image will be embedded directly, and the other image formats
+// will be decoded and compressed. It returns nil if the image format is not
+// supported.
+func newPDFImage(data []byte) (*pdfImage, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil
+	}
+	img := &pdfImage{width: cfg.Width, height: cfg.Height, colorSpace: "DeviceRGB"}
+	if format == "jpeg" {
+		switch cfg.ColorModel {
+		case color.GrayModel:
+			img.colorSpace = "DeviceGray"
+		case color.CMYKModel:
+			img.colorSpace = "DeviceCMYK"
+		}
+		img.data, img.filter = data, "DCTDecode"
+		return img, nil
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	bounds := src.Bounds()
+	rgb := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	alpha := make([]byte, 0, bounds.Dx()*bounds.Dy())
+	opaque := true
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(src.At(x, y)).(color.NRGBA)
+			rgb = append(rgb, c.R, c.G, c.B)
+			alpha = append(alpha, c.A)
+			opaque = opaque && c.A == 255
+		}
+	}
+	img.data, img.filter = rgb, "FlateDecode"
+	if !opaque {
+		img.mask = alpha
+	}
+	return img, nil
+}
+
+// pdfWriter defined the writer of the PDF document objects, and records the
+// offsets of the objects for the cross-reference table.
+type pdfWriter struct {
+	w       *bufio.Writer
+	offset  int
+	offsets []int
+}
+
+// WriteString writes the string to the PDF document.
+func (pw *pdfWriter) WriteString(s string) {
+	n, _ := pw.w.WriteString(s)
+	pw.offset += n
+}
+
+// alloc returns the allocated object number.
+func (pw *pdfWriter) alloc() int {
+	pw.offsets = append(pw.offsets, 0)
+	return len(pw.offsets)
+}
+
+// object writes the indirect object by given object number and object
+// content.
+func (pw *pdfWriter) object(id int, content string) {
+	pw.offsets[id-1] = pw.offset
+	pw.WriteString(strconv.Itoa(id) + " 0 obj\n" + content + "\nendobj\n")
+}
+
+// stream writes the stream object by given object number, stream dictionary
+// entries and stream data. The data will be compressed if the compress
+// parameter is true.
+func (pw *pdfWriter) stream(id int, dict string, data []byte, compress bool) error {
+	if compress {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		data, dict = buf.Bytes(), dict+" /Filter /FlateDecode"
+	}
+	pw.offsets[id-1] = pw.offset
+	pw.WriteString(fmt.Sprintf("%d 0 obj\n<< /Length %d%s >>\nstream\n", id, len(data), dict))
+	n, _ := pw.w.Write(data)
+	pw.offset += n
+	pw.WriteString("\nendstream\nendobj\n")
+	return nil
+}
+
+// writeFont writes the Type 0 font with the CIDFontType2 descendant font,
+// the font descriptor, the embedded TrueType font program and the ToUnicode
+// character map by given font object number.
+func (pw *pdfWriter) writeFont(id int, pf *pdfFont) error {
+	cidFont, descriptor, fontFile, toUnicode := pw.alloc(), pw.alloc(), pw.alloc(), pw.alloc()
+	name, _ := pf.font.Name(&pf.buf, sfnt.NameIDPostScript)
+	name = strings.Map(func(r rune) rune {
+		if r > ' ' && r < '~' && !strings.ContainsRune("()<>[]{}/%#", r) {
+			return r
+		}
+		return -1
+	}, name)
+	if name == "" {
+		name = pf.name
+	}
+	glyphs := make([]int, 0, len(pf.widths))
+	for idx := range pf.widths {
+		glyphs = append(glyphs, int(idx))
+	}
+	sort.Ints(glyphs)
+	var widths, cmap strings.Builder
+	for _, idx := range glyphs {
+		fmt.Fprintf(&widths, "%d [%s] ", idx, pdfNum(pf.widths[sfnt.GlyphIndex(idx)]))
+	}
+	pw.object(id, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		name, cidFont, toUnicode))
+	pw.object(cidFont, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /DW 1000 /W [%s] /CIDToGIDMap /Identity >>",
+		name, descriptor, strings.TrimSpace(widths.String())))
+	pw.object(descriptor, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%s] /ItalicAngle 0 /Ascent %s /Descent %s /CapHeight %s /StemV 80 /FontFile2 %d 0 R >>",
+		name, pdfNum(pf.bbox[:]...), pdfNum(pf.ascent*1000), pdfNum(pf.descent*1000), pdfNum(pf.ascent*1000), fontFile))
+	if err := pw.stream(fontFile, fmt.Sprintf(" /Length1 %d", len(pf.data)), pf.data, true); err != nil {
+		return err
+	}
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	mapped := make([]int, 0, len(pf.glyphs))
+	for idx := range pf.glyphs {
+		mapped = append(mapped, int(idx))
+	}
+	sort.Ints(mapped)
+	for i := 0; i < len(mapped); i += 100 {
+		end := i + 100
+		if end > len(mapped) {
+			end = len(mapped)
+		}
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", end-i)
+		for _, idx := range mapped[i:end] {
+			fmt.Fprintf(&cmap, "<%04X> <", idx)
+			for _, u := range utf16.Encode([]rune{pf.glyphs[sfnt.GlyphIndex(idx)]}) {
+				fmt.Fprintf(&cmap, "%04X", u)
+			}
+			cmap.WriteString(">\n")
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+	return pw.stream(toUnicode, "", []byte(cmap.String()), true)
+}
+
+// writeImage writes the image XObject and the soft mask of the image by
+// given image object number.
+func (pw *pdfWriter) writeImage(id int, img *pdfImage) error {
+	dict := fmt.Sprintf(" /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8",
+		img.width, img.height, img.colorSpace)
+	if img.mask != nil {
+		mask := pw.alloc()
+		if err := pw.stream(mask, fmt.Sprintf(" /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8",
+			img.width, img.height), img.mask, true); err != nil {
+			return err
+		}
+		dict += fmt.Sprintf(" /SMask %d 0 R", mask)
+	}
+	if img.filter == "DCTDecode" {
+		return pw.stream(id, dict+" /Filter /DCTDecode", img.data, false)
+	}
+	return pw.stream(id, dict, img.data, true)
+}
+
+// finish writes the cross-reference table and the trailer of the PDF
+// document by given catalog object number, and flush the writer.
+func (pw *pdfWriter) finish(catalog int) error {
+	start := pw.offset
+	pw.WriteString(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets)+1))
+	for _, offset := range pw.offsets {
+		pw.WriteString(fmt.Sprintf("%010d 00000 n \n", offset))
+	}
+	pw.WriteString(fmt.Sprintf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(pw.offsets)+1, catalog, start))
+	return pw.w.Flush()
+}
+
+// clip provides a function to save the graphics state and intersect the
+// clipping path with the given rectangle.
+func (canvas *pdfCanvas) clip(rect [4]float64) {
+	canvas.WriteString("q " + pdfNum(rect[0], rect[1], rect[2], rect[3]) + " re W n\n")
+}
+
+// restore provides a function to restore the graphics state saved by the
+// clip function.
+func (canvas *pdfCanvas) restore() {
+	canvas.WriteString("Q\n")
+}
+
+// fillRect provides a function to fill the rectangle by given color.
+func (canvas *pdfCanvas) fillRect(rect [4]float64, hexColor string) {
+	canvas.WriteString(pdfColor(hexColor, false) + " " + pdfNum(rect[0], rect[1], rect[2], rect[3]) + " re f\n")
+}
+
+// strokeLines provides a function to stroke the lines by given line width,
+// dash pattern and color.
+func (canvas *pdfCanvas) strokeLines(lines [][4]float64, width float64, dash, hexColor string) {
+	canvas.WriteString(pdfColor(hexColor, true) + " " + pdfNum(width) + " w [" + dash + "] 0 d\n")
+	for _, line := range lines {
+		canvas.WriteString(pdfNum(line[0], line[1]) + " m " + pdfNum(line[2], line[3]) + " l S\n")
+	}
+}
+
+// fillText provides a function to draw the text by given font, font size,
+// the origin of the baseline, the counterclockwise rotation angle in radians
+// and color.
+func (canvas *pdfCanvas) fillText(pf *pdfFont, size float64, text string, x, y, angle float64, hexColor string) {
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	canvas.WriteString("BT " + pdfColor(hexColor, false) + " /" + pf.name + " " + pdfNum(size) + " Tf " +
+		pdfNum(cos, -sin, -sin, -cos, x, y) + " Tm " + pf.encode(text) + " Tj ET\n")
+}
+
+// drawImage provides a function to draw the image XObject in the rectangle.
+func (canvas *pdfCanvas) drawImage(img *pdfImage, rect [4]float64) {
+	canvas.WriteString("q " + pdfNum(rect[2], 0, 0, -rect[3], rect[0], rect[1]+rect[3]) + " cm /" + img.name + " Do Q\n")
+}
+
+// pdfNum returns the space separated numbers in the PDF content stream by
+// given numbers, the numbers will be rounded to 3 decimal places.
+func pdfNum(nums ...float64) string {
+	values := make([]string, len(nums))
+	for i, num := range nums {
+		values[i] = strconv.FormatFloat(math.Round(num*1000)/1000, 'f', -1, 64)
+		if values[i] == "-0" {
+			values[i] = "0"
+		}
+	}
+	return strings.Join(values, " ")
+}
+
+// pdfColor returns the operator of setting the fill or stroke color in the
+// PDF content stream by given RGB hex color.
+func pdfColor(hexColor string, stroke bool) string {
+	rgb, _ := strconv.ParseUint(hexColor, 16, 32)
+	op := " rg"
+	if stroke {
+		op = " RG"
+	}
+	return pdfNum(float64(rgb>>16&0xFF)/255, float64(rgb>>8&0xFF)/255, float64(rgb&0xFF)/255) + op
+}
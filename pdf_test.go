@@ -0,0 +1,233 @@
+package excelize
+
+import (
+	"bytes"
+	"compress/zlib"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// testFonts defined the Go fonts used to draw the text in the PDF export and
+// image rendering tests.
+var testFonts = map[string][]byte{
+	"Go": goregular.TTF, "Go Bold": gobold.TTF, "Go Italic": goitalic.TTF, "Go Bold Italic": gobolditalic.TTF,
+}
+
+// parseTestPDF parses the PDF document by the cross-reference table, and
+// returns the content of the objects and the decompressed streams.
+func parseTestPDF(t *testing.T, data []byte) map[int]string {
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-1.7\n")))
+	assert.True(t, bytes.HasSuffix(data, []byte("%%EOF\n")))
+	startXref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	assert.Len(t, startXref, 2)
+	start, err := strconv.Atoi(string(startXref[1]))
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data[start:], []byte("xref\n0 ")))
+	objects := make(map[int]string)
+	for i, entry := range regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[start:], -1) {
+		offset, err := strconv.Atoi(string(entry[1]))
+		assert.NoError(t, err)
+		prefix := strconv.Itoa(i+1) + " 0 obj\n"
+		assert.True(t, bytes.HasPrefix(data[offset:], []byte(prefix)), prefix)
+		end := bytes.Index(data[offset:], []byte("\nendobj\n"))
+		content := string(data[offset+len(prefix) : offset+end])
+		if idx := strings.Index(content, ">>\nstream\n"); idx != -1 {
+			stream := content[idx+len(">>\nstream\n") : len(content)-len("\nendstream")]
+			if strings.Contains(content[:idx], "/FlateDecode") {
+				zr, err := zlib.NewReader(strings.NewReader(stream))
+				assert.NoError(t, err)
+				decoded, err := io.ReadAll(zr)
+				assert.NoError(t, err)
+				stream = string(decoded)
+			}
+			content = content[:idx+2] + "\n" + stream
+		}
+		objects[i+1] = content
+	}
+	return objects
+}
+
+// getTestPDFPageContents returns the content streams of the pages in the
+// parsed PDF document.
+func getTestPDFPageContents(t *testing.T, objects map[int]string) []string {
+	var contents []string
+	for id := 1; id <= len(objects); id++ {
+		if match := regexp.MustCompile(`^<< /Type /Page /Parent 2 0 R /Resources 3 0 R /Contents (\d+) 0 R >>$`).FindStringSubmatch(objects[id]); match != nil {
+			contentID, err := strconv.Atoi(match[1])
+			assert.NoError(t, err)
+			contents = append(contents, objects[contentID])
+		}
+	}
+	return contents
+}
+
+func TestExportPDF(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Item", "Price", "Paid", "Note"}))
+	for row := 2; row <= 60; row++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A"+strconv.Itoa(row), &[]interface{}{"Item " + strconv.Itoa(row), 1234.5, true}))
+	}
+	assert.NoError(t, f.SetCellValue("Sheet1", "D2", "The long text should be wrapped in the cell"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A62", "Overflow text into empty cells"))
+	assert.NoError(t, f.MergeCell("Sheet1", "C61", "D62"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C61", "Merged"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "F1", "Out of print area"))
+	assert.NoError(t, f.SetRowVisible("Sheet1", 3, false))
+	assert.NoError(t, f.SetColWidth("Sheet1", "D", "D", 20))
+	headerStyle, err := f.NewStyle(&Style{
+		Font:      &Font{Bold: true, Italic: true, Underline: "single", Strike: true, Color: "FFFFFF", Family: "Courier New"},
+		Fill:      Fill{Type: "pattern", Pattern: 1, Color: []string{"4472C4"}},
+		Border:    []Border{{Type: "bottom", Color: "FF0000", Style: 6}, {Type: "left", Style: 5}, {Type: "diagonalUp", Style: 3}, {Type: "top", Style: 14}},
+		Alignment: &Alignment{Horizontal: "center", Vertical: "center"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "D1", headerStyle))
+	numberStyle, err := f.NewStyle(&Style{NumFmt: 4, Alignment: &Alignment{Vertical: "top", Indent: 1}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B2", "B60", numberStyle))
+	wrapStyle, err := f.NewStyle(&Style{Alignment: &Alignment{WrapText: true, Horizontal: "right"}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "D2", "D2", wrapStyle))
+	rotateStyle, err := f.NewStyle(&Style{Alignment: &Alignment{TextRotation: 135}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "C61", "C61", rotateStyle))
+	verticalStyle, err := f.NewStyle(&Style{Alignment: &Alignment{TextRotation: 255}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "C60", "C60", verticalStyle))
+	for _, name := range []string{"excel.png", "excel.jpg"} {
+		file, err := os.ReadFile(filepath.Join("test", "images", name))
+		assert.NoError(t, err)
+		assert.NoError(t, f.AddPictureFromBytes("Sheet1", "E2", &Picture{Extension: filepath.Ext(name), File: file, Format: &GraphicOptions{}}))
+	}
+	assert.NoError(t, f.AddPicture("Sheet1", "E20", filepath.Join("test", "images", "excel.png"), &GraphicOptions{PrintObject: boolPtr(false)}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "_xlnm.Print_Area", RefersTo: "Sheet1!$A$1:$E$62", Scope: "Sheet1"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "_xlnm.Print_Titles", RefersTo: "Sheet1!$A:$A,Sheet1!$1:$1", Scope: "Sheet1"}))
+	assert.NoError(t, f.InsertPageBreak("Sheet1", "A40"))
+	assert.NoError(t, f.InsertPageBreak("Sheet1", "E1"))
+	assert.NoError(t, f.SetPageLayout("Sheet1", &PageLayoutOptions{Size: intPtr(9), Orientation: stringPtr("landscape"), FirstPageNumber: uintPtr(3)}))
+	assert.NoError(t, f.SetPageMargins("Sheet1", &PageLayoutMarginsOptions{Left: float64Ptr(0.5), Top: float64Ptr(1.5), Bottom: float64Ptr(1.5), Header: float64Ptr(0.3), Footer: float64Ptr(0.3), Horizontally: boolPtr(true), Vertically: boolPtr(true)}))
+	assert.NoError(t, f.SetHeaderFooter("Sheet1", &HeaderFooterOptions{
+		DifferentFirst: true, DifferentOddEven: true,
+		FirstHeader: `&L&"Arial,Bold"&14Invoice&R&KFF0000&A`,
+		OddHeader:   "&CPage &P of &N",
+		EvenHeader:  "&C&&&F&Z&D&T&B",
+		OddFooter:   "&RLine 1\nLine 2",
+	}))
+	ws, err := f.workSheetReader("Sheet1")
+	assert.NoError(t, err)
+	ws.PrintOptions = &xlsxPrintOptions{GridLines: true, HorizontalCentered: true, VerticalCentered: true}
+
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportPDF("Sheet1", &buf, PDFOptions{Fonts: testFonts, FallbackFont: "Go"}))
+	objects := parseTestPDF(t, buf.Bytes())
+	assert.Contains(t, objects[2], "/Count 6 /MediaBox [0 0 841.89 595.28]")
+	contents := getTestPDFPageContents(t, objects)
+	assert.Len(t, contents, 6)
+	pf, err := newPDFFont(goregular.TTF)
+	assert.NoError(t, err)
+	pf.name = "F1"
+	// Test the headers and footers
+	assert.Contains(t, contents[0], pf.encode("Invoice"))
+	assert.Contains(t, contents[0], pf.encode("Sheet1"))
+	assert.Contains(t, contents[1], strings.TrimSuffix(pf.encode("&Book1"), ">"))
+	assert.Contains(t, contents[2], pf.encode("Page 5 of 8"))
+	assert.Contains(t, contents[2], pf.encode("Line 2"))
+	// Test the page breaks, print titles and print area
+	assert.Contains(t, contents[0], pf.encode("Item 25"))
+	assert.NotContains(t, contents[0], pf.encode("Item 26"))
+	assert.NotContains(t, contents[0], pf.encode("Item 3"))
+	assert.Contains(t, contents[1], pf.encode("Item 39"))
+	assert.NotContains(t, contents[1], pf.encode("Item 40"))
+	assert.Contains(t, contents[2], pf.encode("Item 40"))
+	assert.Contains(t, contents[2], pf.encode("Overflow text into empty cells"))
+	assert.Contains(t, contents[3], pf.encode("Item 2"))
+	assert.NotContains(t, contents[3], pf.encode("Price"))
+	assert.NotContains(t, contents[3], pf.encode("Out of print area"))
+	// Test the number format, fills, borders, gridlines and pictures
+	assert.Contains(t, contents[0], pf.encode("1,234.50"))
+	assert.Contains(t, contents[0], pdfColor("4472C4", false))
+	assert.Contains(t, contents[0], pdfColor("FF0000", true)+" 0.5 w [] 0 d")
+	assert.Contains(t, contents[0], pdfColor("BFBFBF", true)+" 0.25 w")
+	assert.NotContains(t, contents[0], "/Im1 Do")
+	assert.Contains(t, contents[3], "/Im1 Do")
+	assert.Contains(t, contents[3], "/Im2 Do")
+	assert.NotContains(t, contents[3], "/Im3 Do")
+	var images int
+	for _, object := range objects {
+		if strings.Contains(object, "/Subtype /Image") && !strings.Contains(object, "/ColorSpace /DeviceGray") {
+			images++
+		}
+	}
+	assert.Equal(t, 2, images)
+	// Test export the worksheet with the range reference and fit to page
+	assert.NoError(t, f.SetSheetProps("Sheet1", &SheetPropsOptions{FitToPage: boolPtr(true)}))
+	assert.NoError(t, f.SetPageLayout("Sheet1", &PageLayoutOptions{FitToHeight: intPtr(1)}))
+	ws.PageSetUp.PageOrder = "overThenDown"
+	buf.Reset()
+	assert.NoError(t, f.ExportPDF("Sheet1", &buf, PDFOptions{RangeRef: "A1:F62", Fonts: testFonts, FallbackFont: "Go"}))
+	objects = parseTestPDF(t, buf.Bytes())
+	assert.Contains(t, objects[2], "/Count 4")
+
+	// Test export the empty worksheet with the custom font
+	f = NewFile()
+	assert.NoError(t, f.SetHeaderFooter("Sheet1", &HeaderFooterOptions{OddHeader: "Header"}))
+	buf.Reset()
+	assert.NoError(t, f.ExportPDF("Sheet1", &buf, PDFOptions{Fonts: map[string][]byte{"Calibri": goregular.TTF}}))
+	objects = parseTestPDF(t, buf.Bytes())
+	assert.Contains(t, objects[2], "/Count 1 /MediaBox [0 0 612 792]")
+	assert.Contains(t, getTestPDFPageContents(t, objects)[0], pf.encode("Header"))
+	assert.Contains(t, buf.String(), "/BaseFont /GoRegular")
+	// Test export the worksheet without the font data
+	assert.EqualError(t, f.ExportPDF("Sheet1", &buf), "font data of font family Calibri does not exist")
+	assert.EqualError(t, f.ExportPDF("Sheet1", &buf, PDFOptions{Fonts: testFonts, FallbackFont: "Arial"}), "font data of font family Calibri does not exist")
+	// Test export the worksheet with the invalid font data
+	assert.Error(t, f.ExportPDF("Sheet1", &buf, PDFOptions{Fonts: map[string][]byte{"Calibri": []byte("font")}}))
+	// Test export the worksheet with the invalid range reference
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), f.ExportPDF("Sheet1", &buf, PDFOptions{RangeRef: "A:B1"}))
+	// Test export the worksheet with the invalid print titles
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "_xlnm.Print_Titles", RefersTo: "Sheet1!$A:$XFE", Scope: "Sheet1"}))
+	assert.Equal(t, ErrColumnNumber, f.ExportPDF("Sheet1", &buf))
+	assert.NoError(t, f.DeleteDefinedName(&DefinedName{Name: "_xlnm.Print_Titles", Scope: "Sheet1"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "_xlnm.Print_Titles", RefersTo: "Sheet1!$XFE:$A", Scope: "Sheet1"}))
+	assert.Equal(t, ErrColumnNumber, f.ExportPDF("Sheet1", &buf))
+	// Test export the worksheet on not exists worksheet
+	assert.EqualError(t, f.ExportPDF("SheetN", &buf), "sheet SheetN does not exist")
+	// Test export the worksheet with unsupported charset styles
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ExportPDF("Sheet1", &buf), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestNewPDFImage(t *testing.T) {
+	img, err := newPDFImage([]byte("image"))
+	assert.NoError(t, err)
+	assert.Nil(t, img)
+	// Test create image with the corrupted image data
+	file, err := os.ReadFile(filepath.Join("test", "images", "excel.png"))
+	assert.NoError(t, err)
+	_, err = newPDFImage(file[:len(file)/2])
+	assert.Error(t, err)
+}
+
+func TestPDFWrapText(t *testing.T) {
+	pf, err := newPDFFont(goregular.TTF)
+	assert.NoError(t, err)
+	width := pf.width("abc", 10)
+	assert.Equal(t, []string{"abc abc", "abc"}, pf.wrapText("abc abc abc", 10, width*2.5, true))
+	assert.Equal(t, []string{"abc", "abc", "a"}, pf.wrapText("abcabca", 10, width*1.2, true))
+	assert.Equal(t, []string{"abc abc abc", ""}, pf.wrapText("abc abc abc\n", 10, width, false))
+}
@@ -691,7 +691,9 @@ func (f *File) drawingsWriter() {
 	f.Drawings.Range(func(path, d interface{}) bool {
 		if d != nil {
 			v, _ := xml.Marshal(d.(*xlsxWsDr))
-			f.saveFileList(path.(string), v)
+			if f.partModified(path.(string), v) {
+				f.saveFileList(path.(string), v)
+			}
 		}
 		return true
 	})
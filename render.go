@@ -0,0 +1,1844 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// rasterThemeColors defined the default colors of the theme color scheme
+// which used when the workbook without theme, the index of the color is the
+// same as the theme color index.
+var rasterThemeColors = []string{
+	"FFFFFF", "000000", "E7E6E6", "44546A", "4472C4",
+	"ED7D31", "A5A5A5", "FFC000", "5B9BD5", "70AD47",
+}
+
+// rasterSchemeColors defined the theme color index by the name of the scheme
+// color in DrawingML.
+var rasterSchemeColors = map[string]int{
+	"bg1": 0, "lt1": 0, "tx1": 1, "dk1": 1, "bg2": 2, "lt2": 2, "tx2": 3, "dk2": 3,
+	"accent1": 4, "accent2": 5, "accent3": 6, "accent4": 7, "accent5": 8, "accent6": 9,
+}
+
+// RenderOptions directly maps the settings of the worksheet image rendering.
+//
+// RangeRef specifies the cell range reference to be rendered, such as
+// "A1:D10". The used range of the worksheet will be rendered by default.
+//
+// Chart specifies the cell reference of the top-left corner of the chart to
+// be rendered, such as "E1". The chart will be rendered instead of the cell
+// range if this option is specified.
+//
+// Scale specifies the zoom scale of the image, the default value is 1, which
+// renders 96 pixels per inch.
+//
+// Fonts specifies the TrueType font data by the font family name used to draw
+// the text, such as "Arial". The bold and italic variant of the font could be
+// specified with the "Bold", "Italic" or "Bold Italic" suffix, such as
+// "Arial Bold".
+//
+// FallbackFont specifies the font family name in the Fonts used to draw the
+// text of the font families which not been specified, such as "Arial". An
+// error will be returned if the font of the text to be drawn not been
+// specified in the Fonts.
+type RenderOptions struct {
+	RangeRef     string
+	Chart        string
+	Scale        float64
+	Fonts        map[string][]byte
+	FallbackFont string
+}
+
+// rasterCanvas defined the image which the worksheet or chart drawn on, the
+// unit of the coordinates is point, and the y-axis of the coordinate system
+// increases downward.
+type rasterCanvas struct {
+	img    *image.RGBA
+	scale  float64
+	clips  []image.Rectangle
+	images map[*pdfImage]image.Image
+}
+
+// rasterRenderer defined the state of the worksheet image rendering.
+type rasterRenderer struct {
+	*pdfExporter
+	resolution float64
+}
+
+// rasterChartAnchor defined the chart part path, the anchor cell and the
+// position and size in points of the chart on the worksheet.
+type rasterChartAnchor struct {
+	path     string
+	col, row int
+	rect     [4]float64
+}
+
+// rasterSeries defined the name, colors, markers and data of the chart
+// series to be rendered.
+type rasterSeries struct {
+	name, color, lineColor string
+	markerColor            string
+	explicit, line, marker bool
+	symbol                 string
+	markerSize             float64
+	points                 map[int]string
+	categories             []string
+	values, xValues, sizes []float64
+}
+
+// rasterGroup defined the chart type, grouping and series of the chart group
+// in the plot area.
+type rasterGroup struct {
+	kind, barDir, grouping      string
+	varyColors, filled, surface bool
+	wireframe                   bool
+	holeSize, bubbleScale       float64
+	series                      []*rasterSeries
+}
+
+// rasterLegendEntry defined the text and legend key of the chart legend
+// entry.
+type rasterLegendEntry struct {
+	name, color string
+	line        bool
+}
+
+// rasterAxes defined the plot area and the scales of the axes of the chart,
+// the category coordinate is in the unit of the category band.
+type rasterAxes struct {
+	plot           [4]float64
+	horizontal, xy bool
+	edges          bool
+	n              int
+	lo, hi         float64
+	xlo, xhi       float64
+}
+
+// rasterChart defined the state of the chart rendering.
+type rasterChart struct {
+	*rasterRenderer
+	canvas        *rasterCanvas
+	space         decodeChartSpace
+	width, height float64
+	groups        []*rasterGroup
+	font, bold    *pdfFont
+}
+
+// RenderImage provides a function to render the cell range or the chart of
+// the worksheet as an image by given worksheet name and render options. The
+// cell values will be formatted with the number format of the cells, and the
+// fonts, fills, borders, alignment of the cells, merged cells, pictures and
+// charts in the cell range will be drawn, the hidden rows and columns will
+// be skipped. The charts created by the AddChart function will be rendered
+// from the data of the series. For example, render the range A1:D10 of the
+// worksheet named 'Sheet1' in double size:
+//
+//	img, err := f.RenderImage("Sheet1", excelize.RenderOptions{
+//	    RangeRef:     "A1:D10",
+//	    Scale:        2,
+//	    Fonts:        map[string][]byte{"Go": goregular.TTF},
+//	    FallbackFont: "Go",
+//	})
+//
+// Render the chart which top-left corner anchored at cell E1 of the
+// worksheet named 'Sheet1':
+//
+//	img, err := f.RenderImage("Sheet1", excelize.RenderOptions{
+//	    Chart:        "E1",
+//	    Fonts:        map[string][]byte{"Go": goregular.TTF},
+//	    FallbackFont: "Go",
+//	})
+//
+// The TrueType fonts data used to draw the text must be specified in the
+// render options, and the pictures in the worksheet will be drawn if the
+// image format decoders have been registered, the same as the ExportPDF
+// function. The 3-D charts will be rendered as the 2-D charts, the pie of pie
+// and bar of pie charts will be rendered as pie charts, and the surface
+// charts will be rendered as contour charts.
+func (f *File) RenderImage(sheet string, opts ...RenderOptions) (image.Image, error) {
+	var options RenderOptions
+	for _, opt := range opts {
+		options = opt
+	}
+	if options.Scale <= 0 {
+		options.Scale = 1
+	}
+	r := &rasterRenderer{pdfExporter: newPDFExporter(f, sheet), resolution: options.Scale * 4 / 3}
+	r.opts, r.screen = PDFOptions{RangeRef: options.RangeRef, Fonts: options.Fonts, FallbackFont: options.FallbackFont}, true
+	if err := r.preparePageSetup(); err != nil {
+		return nil, err
+	}
+	if options.Chart != "" {
+		col, row, err := CellNameToCoordinates(options.Chart)
+		if err != nil {
+			return nil, err
+		}
+		charts, err := r.prepareCharts()
+		if err != nil {
+			return nil, err
+		}
+		for _, chart := range charts {
+			if chart.col == col && chart.row == row {
+				return r.renderChart(chart.path, chart.rect[2], chart.rect[3])
+			}
+		}
+		return nil, newNoExistChartError(options.Chart)
+	}
+	return r.renderRange()
+}
+
+// ExportPNG provides a function to render the cell range or the chart of the
+// worksheet to io.Writer as a PNG image by given worksheet name and render
+// options. For example, render the used range of the worksheet named
+// 'Sheet1' as a PNG image:
+//
+//	file, err := os.Create("preview.png")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	if err := f.ExportPNG("Sheet1", file); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) ExportPNG(sheet string, w io.Writer, opts ...RenderOptions) error {
+	img, err := f.RenderImage(sheet, opts...)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// renderRange provides a function to render the cell range with the
+// pictures and charts in the cell range.
+func (r *rasterRenderer) renderRange() (image.Image, error) {
+	if err := r.prepareCells(r.opts.RangeRef); err != nil {
+		return nil, err
+	}
+	if r.coordinates[0] == 0 {
+		r.coordinates = []int{1, 1, 1, 1}
+	}
+	if err := r.preparePositions(); err != nil {
+		return nil, err
+	}
+	if err := r.prepareMergeCells(); err != nil {
+		return nil, err
+	}
+	if err := r.preparePictures(); err != nil {
+		return nil, err
+	}
+	if err := r.prepareSheetView(); err != nil {
+		return nil, err
+	}
+	charts, err := r.prepareCharts()
+	if err != nil {
+		return nil, err
+	}
+	cols, rows := [2]int{r.coordinates[0], r.coordinates[2]}, [2]int{r.coordinates[1], r.coordinates[3]}
+	x, y := r.colX[cols[0]], r.rowY[rows[0]]
+	width, height := r.colX[cols[1]+1]-x, r.rowY[rows[1]+1]-y
+	canvas := newRasterCanvas(width, height, r.resolution)
+	if err = r.drawBlock(canvas, rows, cols, 0, 0); err != nil {
+		return nil, err
+	}
+	for _, chart := range charts {
+		rect := [4]float64{chart.rect[0] - x, chart.rect[1] - y, chart.rect[2], chart.rect[3]}
+		if rect[0]+rect[2] <= 0 || rect[0] >= width || rect[1]+rect[3] <= 0 || rect[1] >= height {
+			continue
+		}
+		img, err := r.renderChart(chart.path, rect[2], rect[3])
+		if err != nil {
+			return nil, err
+		}
+		canvas.drawRaster(img, rect)
+	}
+	return canvas.img, err
+}
+
+// prepareSheetView provides a function to get the gridlines settings of the
+// worksheet view, the gridlines will be drawn as displayed on the screen.
+func (r *rasterRenderer) prepareSheetView() error {
+	f := r.f
+	f.mu.Lock()
+	ws, err := f.workSheetReader(r.sheet)
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	r.printOptions.GridLines = true
+	if ws.SheetViews != nil && len(ws.SheetViews.SheetView) > 0 && ws.SheetViews.SheetView[0].ShowGridLines != nil {
+		r.printOptions.GridLines = *ws.SheetViews.SheetView[0].ShowGridLines
+	}
+	return err
+}
+
+// prepareCharts provides a function to get the chart parts, anchor cells and
+// the positions of the charts in the drawing part of the worksheet.
+func (r *rasterRenderer) prepareCharts() ([]rasterChartAnchor, error) {
+	f := r.f
+	f.mu.Lock()
+	ws, err := f.workSheetReader(r.sheet)
+	f.mu.Unlock()
+	if err != nil || ws.Drawing == nil {
+		return nil, err
+	}
+	target := f.getSheetRelationshipsTargetByID(r.sheet, ws.Drawing.RID)
+	drawingXML := strings.TrimPrefix(strings.ReplaceAll(target, "..", "xl"), "/")
+	drawingRelationships := strings.ReplaceAll(
+		strings.ReplaceAll(target, "../drawings", "xl/drawings/_rels"), ".xml", ".xml.rels")
+	wsDr, _, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return nil, err
+	}
+	wsDr.mu.Lock()
+	anchors := append([]*xdrCellAnchor{}, wsDr.TwoCellAnchor...)
+	wsDr.mu.Unlock()
+	if len(r.colX) == 0 {
+		r.colX, r.rowY = []float64{0, 0}, []float64{0, 0}
+	}
+	var charts []rasterChartAnchor
+	for _, anchor := range anchors {
+		var a decodeChartAnchor
+		if err = f.xmlNewDecoder(strings.NewReader("<decodeChartAnchor>" + anchor.GraphicFrame + "</decodeChartAnchor>")).
+			Decode(&a); err != nil && err != io.EOF {
+			return nil, err
+		}
+		if anchor.From != nil && anchor.To != nil {
+			a.From = &decodeFrom{Col: anchor.From.Col, ColOff: anchor.From.ColOff, Row: anchor.From.Row, RowOff: anchor.From.RowOff}
+			a.To = &decodeTo{Col: anchor.To.Col, ColOff: anchor.To.ColOff, Row: anchor.To.Row, RowOff: anchor.To.RowOff}
+		}
+		if a.From == nil || a.To == nil || a.Chart == nil || a.To.Col >= MaxColumns || a.To.Row >= TotalRows {
+			continue
+		}
+		rel := f.getDrawingRelationships(drawingRelationships, a.Chart.RID)
+		if rel == nil {
+			continue
+		}
+		if err = r.extendPositions(a.To.Col+1, a.To.Row+1); err != nil {
+			return nil, err
+		}
+		x, y := r.colX[a.From.Col+1]+float64(a.From.ColOff)/12700, r.rowY[a.From.Row+1]+float64(a.From.RowOff)/12700
+		chart := rasterChartAnchor{
+			path: strings.TrimPrefix(strings.ReplaceAll(rel.Target, "..", "xl"), "/"),
+			col:  a.From.Col + 1, row: a.From.Row + 1,
+			rect: [4]float64{
+				x, y, r.colX[a.To.Col+1] + float64(a.To.ColOff)/12700 - x,
+				r.rowY[a.To.Row+1] + float64(a.To.RowOff)/12700 - y,
+			},
+		}
+		if chart.rect[2] > 0 && chart.rect[3] > 0 {
+			charts = append(charts, chart)
+		}
+	}
+	return charts, nil
+}
+
+// renderChart provides a function to render the chart by given chart part
+// path, and the width and height in points of the chart.
+func (r *rasterRenderer) renderChart(path string, width, height float64) (image.Image, error) {
+	c := &rasterChart{rasterRenderer: r, canvas: newRasterCanvas(width, height, r.resolution), width: width, height: height}
+	if err := r.f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(r.f.readXML(path)))).
+		Decode(&c.space); err != nil && err != io.EOF {
+		return nil, err
+	}
+	var err error
+	if c.font, err = r.font(r.defaultFont.Family, false, false); err != nil {
+		return nil, err
+	}
+	if c.bold, err = r.font(r.defaultFont.Family, true, false); err != nil {
+		return nil, err
+	}
+	if err = c.prepareGroups(); err != nil {
+		return nil, err
+	}
+	c.draw()
+	return c.canvas.img, err
+}
+
+// prepareGroups provides a function to get the chart groups in the plot
+// area, and read the data of the series from the referenced cells. The
+// cached data of the series will be used if the referenced cells not exist.
+func (c *rasterChart) prepareGroups() error {
+	var index int
+	for _, g := range c.space.Chart.PlotArea.Charts {
+		if !strings.HasSuffix(g.XMLName.Local, "Chart") {
+			continue
+		}
+		group := &rasterGroup{
+			kind:     strings.TrimSuffix(strings.Replace(g.XMLName.Local, "3D", "", 1), "Chart"),
+			barDir:   "col",
+			grouping: "standard",
+			holeSize: 50, bubbleScale: 100,
+		}
+		if group.kind == "bar" {
+			group.grouping = "clustered"
+		}
+		if g.BarDir != nil && g.BarDir.Val != nil {
+			group.barDir = *g.BarDir.Val
+		}
+		if g.Grouping != nil && g.Grouping.Val != nil {
+			group.grouping = *g.Grouping.Val
+		}
+		if g.VaryColors != nil {
+			group.varyColors = g.VaryColors.Val == nil || *g.VaryColors.Val
+		}
+		if g.HoleSize != nil && g.HoleSize.Val != nil {
+			group.holeSize = float64(*g.HoleSize.Val)
+		}
+		if g.BubbleScale != nil && g.BubbleScale.Val != nil {
+			group.bubbleScale = *g.BubbleScale.Val
+		}
+		group.filled = g.RadarStyle != nil && g.RadarStyle.Val != nil && *g.RadarStyle.Val == "filled"
+		group.wireframe = g.Wireframe != nil && (g.Wireframe.Val == nil || *g.Wireframe.Val)
+		for _, ser := range g.Ser {
+			s, err := c.prepareSeries(group, &ser, index)
+			if err != nil {
+				return err
+			}
+			group.series = append(group.series, s)
+			index++
+		}
+		c.groups = append(c.groups, group)
+	}
+	return nil
+}
+
+// prepareSeries provides a function to get the name, colors, markers and
+// data of the chart series by given chart group, series and series index.
+func (c *rasterChart) prepareSeries(group *rasterGroup, ser *decodeChartSeries, index int) (*rasterSeries, error) {
+	s := &rasterSeries{
+		line: true, marker: group.kind == "scatter", symbol: "circle", markerSize: 5,
+		color: c.paletteColor(index), points: make(map[int]string),
+	}
+	names, err := c.data(ser.Tx, false)
+	if err != nil {
+		return s, err
+	}
+	if s.name = strings.Join(names, " "); s.name == "" {
+		s.name = "Series" + strconv.Itoa(index+1)
+	}
+	cat := ser.Cat
+	if ser.XVal != nil {
+		cat = ser.XVal
+	}
+	if s.categories, err = c.data(cat, false); err != nil {
+		return s, err
+	}
+	val := ser.Val
+	if ser.YVal != nil {
+		val = ser.YVal
+	}
+	if s.values, err = c.numbers(val); err != nil {
+		return s, err
+	}
+	if ser.XVal != nil {
+		if s.xValues, err = c.numbers(ser.XVal); err != nil {
+			return s, err
+		}
+		for i := range s.xValues {
+			if math.IsNaN(s.xValues[i]) {
+				s.xValues = nil
+				break
+			}
+		}
+	}
+	if s.sizes, err = c.numbers(ser.BubbleSize); err != nil {
+		return s, err
+	}
+	if ser.SpPr != nil {
+		if fill := c.color(ser.SpPr.SolidFill); fill != "" {
+			s.color, s.explicit = fill, true
+		}
+		if ser.SpPr.Ln != nil {
+			s.line = ser.SpPr.Ln.NoFill == nil
+			s.lineColor = c.color(ser.SpPr.Ln.SolidFill)
+		}
+	}
+	if s.lineColor == "" {
+		s.lineColor = s.color
+	} else if group.kind == "line" || group.kind == "scatter" || group.kind == "radar" {
+		s.color, s.explicit = s.lineColor, true
+	}
+	if ser.Marker != nil {
+		if ser.Marker.Symbol != nil && ser.Marker.Symbol.Val != nil {
+			s.symbol = *ser.Marker.Symbol.Val
+			s.marker = s.symbol != "none"
+		}
+		if ser.Marker.Size != nil && ser.Marker.Size.Val != nil {
+			s.markerSize = float64(*ser.Marker.Size.Val)
+		}
+		if ser.Marker.SpPr != nil {
+			s.markerColor = c.color(ser.Marker.SpPr.SolidFill)
+		}
+	}
+	for _, dPt := range ser.DPt {
+		if dPt.IDx != nil && dPt.IDx.Val != nil && dPt.SpPr != nil {
+			if fill := c.color(dPt.SpPr.SolidFill); fill != "" {
+				s.points[*dPt.IDx.Val] = fill
+			}
+		}
+	}
+	return s, err
+}
+
+// data provides a function to get the values of the chart data source by
+// given data source element, the values will be read from the referenced
+// cells, or from the cached data if the referenced cells not exist.
+func (c *rasterChart) data(d *decodeChartData, raw bool) ([]string, error) {
+	if d == nil {
+		return nil, nil
+	}
+	var (
+		ref   string
+		cache []*cPt
+	)
+	if d.NumRef != nil {
+		if ref = d.NumRef.F; d.NumRef.NumCache != nil {
+			cache = d.NumRef.NumCache.Pt
+		}
+	}
+	if d.StrRef != nil {
+		if ref = d.StrRef.F; d.StrRef.StrCache != nil {
+			cache = d.StrRef.StrCache.Pt
+		}
+	}
+	if values, ok := c.refValues(ref, raw); ok {
+		return values, nil
+	}
+	if d.V != "" {
+		return []string{d.V}, nil
+	}
+	var values []string
+	for _, pt := range cache {
+		if pt.IDx < 0 || pt.IDx >= TotalRows {
+			continue
+		}
+		for len(values) <= pt.IDx {
+			values = append(values, "")
+		}
+		if pt.V != nil {
+			values[pt.IDx] = *pt.V
+		}
+	}
+	return values, nil
+}
+
+// refValues provides a function to get the values of the cells by given
+// reference of the chart data source, such as "Sheet1!$B$2:$B$5". It returns
+// false if the reference is invalid.
+func (c *rasterChart) refValues(ref string, raw bool) ([]string, bool) {
+	if ref = strings.TrimSuffix(strings.TrimPrefix(ref, "("), ")"); ref == "" {
+		return nil, false
+	}
+	var values []string
+	for _, part := range strings.Split(ref, ",") {
+		idx := strings.LastIndex(part, "!")
+		if idx == -1 {
+			return nil, false
+		}
+		sheet := strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(part[:idx], "'"), "'"), "''", "'")
+		cells := strings.Split(strings.ReplaceAll(part[idx+1:], "$", ""), ":")
+		coordinates, err := cellRefsToCoordinates(cells[0], cells[len(cells)-1])
+		if err != nil {
+			return nil, false
+		}
+		_ = sortCoordinates(coordinates)
+		for row := coordinates[1]; row <= coordinates[3]; row++ {
+			for col := coordinates[0]; col <= coordinates[2]; col++ {
+				cell, _ := CoordinatesToCellName(col, row)
+				value, err := c.f.GetCellValue(sheet, cell, Options{RawCellValue: raw})
+				if err != nil {
+					return nil, false
+				}
+				values = append(values, value)
+			}
+		}
+	}
+	return values, true
+}
+
+// numbers provides a function to get the numeric values of the chart data
+// source, the blank and non-numeric values will be NaN.
+func (c *rasterChart) numbers(d *decodeChartData) ([]float64, error) {
+	values, err := c.data(d, true)
+	numbers := make([]float64, len(values))
+	for i, value := range values {
+		if numbers[i], err = strconv.ParseFloat(value, 64); err != nil {
+			numbers[i], err = math.NaN(), nil
+		}
+	}
+	return numbers, err
+}
+
+// color provides a function to get the RGB hex color of the solid fill, the
+// luminance modulation and offset of the scheme color will be applied.
+func (c *rasterChart) color(clr *decodeChartColor) string {
+	if clr == nil {
+		return ""
+	}
+	if clr.SrgbClr != nil && clr.SrgbClr.Val != nil {
+		return strings.ToUpper(*clr.SrgbClr.Val)
+	}
+	if clr.SchemeClr == nil {
+		return ""
+	}
+	idx, ok := rasterSchemeColors[clr.SchemeClr.Val]
+	if !ok {
+		return ""
+	}
+	lumMod, lumOff := 1.0, 0.0
+	if clr.SchemeClr.LumMod != nil && clr.SchemeClr.LumMod.Val != nil {
+		lumMod = float64(*clr.SchemeClr.LumMod.Val) / 100000
+	}
+	if clr.SchemeClr.LumOff != nil && clr.SchemeClr.LumOff.Val != nil {
+		lumOff = float64(*clr.SchemeClr.LumOff.Val) / 100000
+	}
+	return rasterLuminance(c.themeColor(idx), lumMod, lumOff)
+}
+
+// themeColor provides a function to get the RGB hex color of the theme color
+// by given theme color index.
+func (c *rasterChart) themeColor(idx int) string {
+	if c.f.Theme != nil {
+		if clr := c.htmlExporter.color("", len(IndexedColorMapping), &idx, 0); clr != "" {
+			return clr
+		}
+	}
+	return rasterThemeColors[idx]
+}
+
+// paletteColor provides a function to get the default color of the series or
+// data point by given index, the accent colors of the theme will be used in
+// turn, and the luminance of the colors will be changed in the next turns.
+func (c *rasterChart) paletteColor(index int) string {
+	clr := c.themeColor(4 + index%6)
+	switch turn := index / 6 % 3; turn {
+	case 1:
+		return rasterLuminance(clr, 0.6, 0)
+	case 2:
+		return rasterLuminance(clr, 0.6, 0.4)
+	}
+	return clr
+}
+
+// title returns the text of the chart title, the name of the series will be
+// used as the title if the chart only contains one series and the title
+// without text.
+func (c *rasterChart) title() string {
+	t := c.space.Chart.Title
+	if t == nil {
+		return ""
+	}
+	text := strings.Join(t.T, "")
+	if t.StrRef != nil {
+		values, _ := c.data(&decodeChartData{StrRef: t.StrRef}, false)
+		text = strings.Join(values, " ")
+	}
+	if text == "" && (c.space.Chart.AutoTitleDeleted == nil || c.space.Chart.AutoTitleDeleted.Val == nil || !*c.space.Chart.AutoTitleDeleted.Val) &&
+		len(c.groups) == 1 && len(c.groups[0].series) == 1 {
+		text = c.groups[0].series[0].name
+	}
+	return text
+}
+
+// pieLike returns if the chart group is a pie or doughnut chart.
+func (g *rasterGroup) pieLike() bool {
+	return g.kind == "pie" || g.kind == "ofPie" || g.kind == "doughnut"
+}
+
+// pointColor returns the color of the data point by given series and data
+// point index.
+func (c *rasterChart) pointColor(g *rasterGroup, s *rasterSeries, idx int) string {
+	if clr, ok := s.points[idx]; ok {
+		return clr
+	}
+	if g.varyColors && !s.explicit && (g.pieLike() || len(g.series) == 1) {
+		return c.paletteColor(idx)
+	}
+	return s.color
+}
+
+// markerColor returns the fill color of the data point marker by given
+// series and data point index.
+func (c *rasterChart) markerColor(g *rasterGroup, s *rasterSeries, idx int) string {
+	if _, ok := s.points[idx]; !ok && s.markerColor != "" {
+		return s.markerColor
+	}
+	return c.pointColor(g, s, idx)
+}
+
+// draw provides a function to draw the chart area, title, legend and plot
+// area of the chart.
+func (c *rasterChart) draw() {
+	canvas, area := c.canvas, [4]float64{0, 0, c.width, c.height}
+	background, border := "FFFFFF", "D9D9D9"
+	if spPr := c.space.SpPr; spPr != nil {
+		if clr := c.color(spPr.SolidFill); clr != "" {
+			background = clr
+		}
+		if spPr.NoFill != nil {
+			background = ""
+		}
+		if spPr.Ln != nil {
+			if clr := c.color(spPr.Ln.SolidFill); clr != "" {
+				border = clr
+			}
+			if spPr.Ln.NoFill != nil {
+				border = ""
+			}
+		}
+	}
+	if background != "" {
+		canvas.fillRect(area, background)
+	}
+	if border != "" {
+		canvas.strokeLines([][4]float64{
+			{0.5, 0.5, c.width - 0.5, 0.5}, {c.width - 0.5, 0.5, c.width - 0.5, c.height - 0.5},
+			{c.width - 0.5, c.height - 0.5, 0.5, c.height - 0.5}, {0.5, c.height - 0.5, 0.5, 0.5},
+		}, 0.75, "", border)
+	}
+	area = [4]float64{7, 7, c.width - 14, c.height - 14}
+	if title := c.title(); title != "" {
+		c.text(c.bold, 14, title, area[0]+area[2]/2, area[1], "center", "595959")
+		area[1] += 14*1.2 + 4
+		area[3] -= 14*1.2 + 4
+	}
+	area = c.drawLegend(area)
+	if area[2] <= 0 || area[3] <= 0 || len(c.groups) == 0 {
+		return
+	}
+	for _, g := range c.groups {
+		switch {
+		case g.pieLike():
+			c.drawPie(g, area)
+			return
+		case g.kind == "radar":
+			c.drawRadar(g, area)
+			return
+		case g.kind == "surface":
+			c.drawSurface(g, area)
+			return
+		}
+	}
+	c.drawCartesian(area)
+}
+
+// text provides a function to draw the single line text by given font, font
+// size, text, the x-axis position of the text anchor, the top of the text,
+// horizontal alignment and color.
+func (c *rasterChart) text(pf *pdfFont, size float64, text string, x, top float64, align, hexColor string) {
+	width := pf.width(text, size)
+	switch align {
+	case "center":
+		x -= width / 2
+	case "right":
+		x -= width
+	}
+	c.canvas.fillText(pf, size, text, x, top+(1.2*size-(pf.ascent-pf.descent)*size)/2+pf.ascent*size, 0, hexColor)
+}
+
+// legendEntries returns the legend entries of the chart, the categories will
+// be the legend entries of the pie and doughnut charts, and the value bands
+// will be the legend entries of the surface charts.
+func (c *rasterChart) legendEntries() []rasterLegendEntry {
+	var entries []rasterLegendEntry
+	for _, g := range c.groups {
+		if g.pieLike() && len(g.series) > 0 {
+			s := g.series[0]
+			for i := range s.values {
+				name := strconv.Itoa(i + 1)
+				if i < len(s.categories) && s.categories[i] != "" {
+					name = s.categories[i]
+				}
+				entries = append(entries, rasterLegendEntry{name: name, color: c.pointColor(g, s, i)})
+			}
+			return entries
+		}
+		if g.kind == "surface" {
+			ticks := c.ticks(g, 0)
+			for i := 0; i < len(ticks)-1; i++ {
+				entries = append(entries, rasterLegendEntry{
+					name:  c.formatValue(ticks[i], false) + "-" + c.formatValue(ticks[i+1], false),
+					color: c.paletteColor(i),
+				})
+			}
+			return entries
+		}
+		for _, s := range g.series {
+			line := g.kind == "line" || (g.kind == "radar" && !g.filled) || (g.kind == "scatter" && s.line)
+			entries = append(entries, rasterLegendEntry{name: s.name, color: s.color, line: line})
+		}
+	}
+	return entries
+}
+
+// drawLegend provides a function to draw the legend of the chart in the
+// given area, and returns the remaining area for the plot area.
+func (c *rasterChart) drawLegend(area [4]float64) [4]float64 {
+	legend := c.space.Chart.Legend
+	if legend == nil {
+		return area
+	}
+	pos := "r"
+	if legend.LegendPos != nil && legend.LegendPos.Val != nil {
+		pos = *legend.LegendPos.Val
+	}
+	entries := c.legendEntries()
+	if len(entries) == 0 {
+		return area
+	}
+	size, rowHeight, key := 9.0, 9*1.5, 14.0
+	widths := make([]float64, len(entries))
+	var maxWidth float64
+	for i, entry := range entries {
+		widths[i] = key + 4 + c.font.width(entry.name, size)
+		maxWidth = math.Max(maxWidth, widths[i])
+	}
+	drawEntry := func(entry rasterLegendEntry, x, y float64) {
+		if entry.line {
+			c.canvas.strokeLines([][4]float64{{x, y + rowHeight/2, x + key, y + rowHeight/2}}, 2.25, "", entry.color)
+		} else {
+			c.canvas.fillRect([4]float64{x + key/2 - 3.5, y + rowHeight/2 - 3.5, 7, 7}, entry.color)
+		}
+		c.text(c.font, size, entry.name, x+key+4, y+(rowHeight-size*1.2)/2, "left", "595959")
+	}
+	if pos == "t" || pos == "b" {
+		var rows [][]int
+		var rowWidths []float64
+		for i := range entries {
+			if n := len(rows); n == 0 || rowWidths[n-1]+widths[i] > area[2] {
+				rows, rowWidths = append(rows, nil), append(rowWidths, 0)
+			}
+			n := len(rows) - 1
+			rows[n], rowWidths[n] = append(rows[n], i), rowWidths[n]+widths[i]+10
+		}
+		height := float64(len(rows)) * rowHeight
+		y := area[1]
+		if pos == "b" {
+			y = area[1] + area[3] - height
+		}
+		for i, row := range rows {
+			x := area[0] + (area[2]-rowWidths[i]+10)/2
+			for _, idx := range row {
+				drawEntry(entries[idx], x, y+float64(i)*rowHeight)
+				x += widths[idx] + 10
+			}
+		}
+		if pos == "t" {
+			area[1] += height + 4
+		}
+		area[3] -= height + 4
+		return area
+	}
+	maxWidth = math.Min(maxWidth, area[2]/2)
+	height := float64(len(entries)) * rowHeight
+	x, y := area[0]+area[2]-maxWidth, area[1]+(area[3]-height)/2
+	if pos == "l" {
+		x = area[0]
+	}
+	if pos == "tr" {
+		y = area[1]
+	}
+	c.canvas.clip([4]float64{x, area[1], maxWidth, area[3]})
+	for i, entry := range entries {
+		drawEntry(entry, x, y+float64(i)*rowHeight)
+	}
+	c.canvas.restore()
+	if pos == "l" {
+		area[0] += maxWidth + 7
+	}
+	area[2] -= maxWidth + 7
+	return area
+}
+
+// drawPie provides a function to draw the pie or doughnut chart in the plot
+// area, the series of the doughnut chart will be drawn as concentric rings.
+func (c *rasterChart) drawPie(g *rasterGroup, area [4]float64) {
+	if len(g.series) == 0 {
+		return
+	}
+	rings := g.series[:1]
+	radius := math.Min(area[2], area[3]) / 2 * 0.95
+	hole := 0.0
+	if g.kind == "doughnut" {
+		rings, hole = g.series, radius*g.holeSize/100
+	}
+	cx, cy := area[0]+area[2]/2, area[1]+area[3]/2
+	ringWidth := (radius - hole) / float64(len(rings))
+	for i, s := range rings {
+		outer := radius - float64(i)*ringWidth
+		inner := outer - ringWidth
+		if i < len(rings)-1 {
+			inner += 0.75
+		}
+		var total float64
+		for _, v := range s.values {
+			if v > 0 {
+				total += v
+			}
+		}
+		if total == 0 {
+			continue
+		}
+		angle := -math.Pi / 2
+		var separators [][4]float64
+		for idx, v := range s.values {
+			if !(v > 0) {
+				continue
+			}
+			sweep := v / total * 2 * math.Pi
+			c.canvas.fillPolygon(rasterSector(cx, cy, inner, outer, angle, angle+sweep), c.pointColor(g, s, idx))
+			separators = append(separators, [4]float64{
+				cx + inner*math.Cos(angle), cy + inner*math.Sin(angle),
+				cx + outer*math.Cos(angle), cy + outer*math.Sin(angle),
+			})
+			angle += sweep
+		}
+		if len(separators) > 1 {
+			c.canvas.strokeLines(separators, 0.75, "", "FFFFFF")
+		}
+	}
+}
+
+// drawRadar provides a function to draw the radar chart in the plot area.
+func (c *rasterChart) drawRadar(g *rasterGroup, area [4]float64) {
+	n, categories := c.categories(g)
+	if n == 0 {
+		return
+	}
+	size := 9.0
+	var labelWidth float64
+	for _, label := range categories {
+		labelWidth = math.Max(labelWidth, c.font.width(label, size))
+	}
+	radius := math.Min(area[2]/2-labelWidth-4, area[3]/2-size*1.2-4)
+	if radius <= 0 {
+		return
+	}
+	cx, cy := area[0]+area[2]/2, area[1]+area[3]/2
+	ticks := c.ticks(g, c.majorUnit())
+	lo, hi := ticks[0], ticks[len(ticks)-1]
+	point := func(i int, v float64) [2]float64 {
+		angle := -math.Pi/2 + float64(i)*2*math.Pi/float64(n)
+		r := (v - lo) / (hi - lo) * radius
+		return [2]float64{cx + r*math.Cos(angle), cy + r*math.Sin(angle)}
+	}
+	var lines [][4]float64
+	for _, tick := range ticks[1:] {
+		for i := 0; i < n; i++ {
+			p1, p2 := point(i, tick), point((i+1)%n, tick)
+			lines = append(lines, [4]float64{p1[0], p1[1], p2[0], p2[1]})
+		}
+	}
+	for i := 0; i < n; i++ {
+		p := point(i, hi)
+		lines = append(lines, [4]float64{cx, cy, p[0], p[1]})
+		label := point(i, hi+(hi-lo)*(6+size*0.6)/radius)
+		align := "center"
+		if label[0] > cx+1 {
+			align = "left"
+		} else if label[0] < cx-1 {
+			align = "right"
+		}
+		c.text(c.font, size, categories[i], label[0], label[1]-size*0.6, align, "595959")
+	}
+	c.canvas.strokeLines(lines, 0.75, "", "D9D9D9")
+	for _, tick := range ticks {
+		p := point(0, tick)
+		c.text(c.font, size, c.formatValue(tick, false), p[0]-4, p[1]-size*0.6, "right", "595959")
+	}
+	for _, s := range g.series {
+		var points [][2]float64
+		for i := 0; i < n; i++ {
+			v := 0.0
+			if i < len(s.values) && !math.IsNaN(s.values[i]) {
+				v = s.values[i]
+			}
+			points = append(points, point(i, v))
+		}
+		if g.filled {
+			c.canvas.fillPolygon(points, s.color)
+			continue
+		}
+		if s.line {
+			c.canvas.strokePolyline(append(points, points[0]), 2.25, s.lineColor)
+		}
+		for i, p := range points {
+			if s.marker {
+				c.drawMarker(p, s.symbol, s.markerSize, c.markerColor(g, s, i))
+			}
+		}
+	}
+}
+
+// drawSurface provides a function to draw the surface chart as the contour
+// chart in the plot area, the data points of the series will be filled by
+// the color of the value bands, and only the outlines of the data points
+// will be drawn for the wireframe surface chart.
+func (c *rasterChart) drawSurface(g *rasterGroup, area [4]float64) {
+	n, categories := c.categories(g)
+	if n == 0 || len(g.series) == 0 {
+		return
+	}
+	size := 9.0
+	var labelWidth float64
+	for _, s := range g.series {
+		labelWidth = math.Max(labelWidth, c.font.width(s.name, size))
+	}
+	plot := [4]float64{area[0] + labelWidth + 4, area[1], area[2] - labelWidth - 4, area[3] - size*1.2 - 4}
+	if plot[2] <= 0 || plot[3] <= 0 {
+		return
+	}
+	ticks := c.ticks(g, 0)
+	width, height := plot[2]/float64(n), plot[3]/float64(len(g.series))
+	for j, s := range g.series {
+		y := plot[1] + plot[3] - float64(j+1)*height
+		c.text(c.font, size, s.name, plot[0]-4, y+(height-size*1.2)/2, "right", "595959")
+		for i := 0; i < n && i < len(s.values); i++ {
+			if math.IsNaN(s.values[i]) {
+				continue
+			}
+			band := sort.SearchFloat64s(ticks, s.values[i]) - 1
+			if band < 0 {
+				band = 0
+			}
+			rect := [4]float64{plot[0] + float64(i)*width, y, width, height}
+			if g.wireframe {
+				c.canvas.strokeLines([][4]float64{
+					{rect[0], rect[1], rect[0] + rect[2], rect[1]}, {rect[0] + rect[2], rect[1], rect[0] + rect[2], rect[1] + rect[3]},
+					{rect[0] + rect[2], rect[1] + rect[3], rect[0], rect[1] + rect[3]}, {rect[0], rect[1] + rect[3], rect[0], rect[1]},
+				}, 1.5, "", c.paletteColor(band))
+				continue
+			}
+			c.canvas.fillRect(rect, c.paletteColor(band))
+		}
+	}
+	c.drawCategoryLabels(categories, plot, false)
+}
+
+// categories returns the number of the categories and the category labels
+// of the chart group.
+func (c *rasterChart) categories(groups ...*rasterGroup) (int, []string) {
+	var n int
+	var labels []string
+	for _, g := range groups {
+		for _, s := range g.series {
+			if len(s.values) > n {
+				n = len(s.values)
+			}
+			if len(s.categories) > len(labels) {
+				labels = s.categories
+			}
+		}
+	}
+	categories := make([]string, n)
+	for i := range categories {
+		if categories[i] = strconv.Itoa(i + 1); i < len(labels) && labels[i] != "" {
+			categories[i] = labels[i]
+		}
+	}
+	return n, categories
+}
+
+// stack provides a function to calculate the bottom and top values of the
+// data points of the chart group by the grouping of the chart group. The
+// positive and negative values of the stacked bars will be stacked
+// separately, and the values of the percent stacked charts will be
+// calculated as the ratio to the sum of the absolute values.
+func (c *rasterChart) stack(g *rasterGroup) (bases, tops [][]float64) {
+	n, _ := c.categories(g)
+	stacked := g.grouping == "stacked" || g.grouping == "percentStacked"
+	totals, positive, negative := make([]float64, n), make([]float64, n), make([]float64, n)
+	for _, s := range g.series {
+		for i := 0; i < n && i < len(s.values); i++ {
+			if !math.IsNaN(s.values[i]) {
+				totals[i] += math.Abs(s.values[i])
+			}
+		}
+	}
+	for _, s := range g.series {
+		base, top := make([]float64, n), make([]float64, n)
+		for i := 0; i < n; i++ {
+			v := math.NaN()
+			if i < len(s.values) {
+				v = s.values[i]
+			}
+			if !stacked {
+				base[i], top[i] = 0, v
+				continue
+			}
+			if math.IsNaN(v) {
+				v = 0
+			}
+			if g.grouping == "percentStacked" && totals[i] != 0 {
+				v /= totals[i]
+			}
+			sum := positive
+			if g.kind == "bar" && v < 0 {
+				sum = negative
+			}
+			base[i], top[i] = sum[i], sum[i]+v
+			sum[i] += v
+		}
+		bases, tops = append(bases, base), append(tops, top)
+	}
+	return bases, tops
+}
+
+// valueRange returns the minimum and maximum values of the data points of
+// the chart groups.
+func (c *rasterChart) valueRange(groups ...*rasterGroup) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, g := range groups {
+		bases, tops := c.stack(g)
+		for i := range tops {
+			for j := range tops[i] {
+				for _, v := range []float64{bases[i][j], tops[i][j]} {
+					if !math.IsNaN(v) && (g.kind == "bar" || g.kind == "area" || v != bases[i][j]) {
+						lo, hi = math.Min(lo, v), math.Max(hi, v)
+					}
+				}
+			}
+		}
+	}
+	if math.IsInf(lo, 1) {
+		return 0, 1
+	}
+	if lo > 0 && (hi-lo)/hi > 1.0/6 {
+		lo = 0
+	}
+	if hi < 0 && (lo-hi)/lo > 1.0/6 {
+		hi = 0
+	}
+	return lo, hi
+}
+
+// ticks returns the values of the major tick marks of the value axis of the
+// chart group by given major unit.
+func (c *rasterChart) ticks(g *rasterGroup, unit float64) []float64 {
+	lo, hi := c.valueRange(g)
+	return rasterTicks(lo, hi, unit)
+}
+
+// majorUnit returns the major unit of the value axis.
+func (c *rasterChart) majorUnit() float64 {
+	for _, ax := range c.space.Chart.PlotArea.ValAx {
+		if ax.MajorUnit != nil && ax.MajorUnit.Val != nil {
+			return *ax.MajorUnit.Val
+		}
+	}
+	return 0
+}
+
+// formatValue returns the axis label of the value by the number format of
+// the value axis.
+func (c *rasterChart) formatValue(v float64, percent bool) string {
+	value := strconv.FormatFloat(v, 'g', 12, 64)
+	if v, err := strconv.ParseFloat(value, 64); err == nil {
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	numFmt := ""
+	if percent {
+		numFmt = "0%"
+	}
+	for _, ax := range c.space.Chart.PlotArea.ValAx {
+		if ax.NumFmt != nil && ax.NumFmt.FormatCode != "" && ax.NumFmt.FormatCode != "General" {
+			numFmt = ax.NumFmt.FormatCode
+		}
+	}
+	if numFmt == "" {
+		return value
+	}
+	return format(value, numFmt, false, CellTypeNumber, nil)
+}
+
+// drawCartesian provides a function to draw the area, bar, line, scatter
+// and bubble charts with the axes in the plot area.
+func (c *rasterChart) drawCartesian(area [4]float64) {
+	var groups []*rasterGroup
+	axes, percent, hasBar := &rasterAxes{xy: true, edges: true}, false, false
+	order := map[string]int{"area": 0, "bar": 1, "line": 2, "scatter": 3, "bubble": 3}
+	for _, g := range c.groups {
+		if _, ok := order[g.kind]; !ok {
+			continue
+		}
+		groups = append(groups, g)
+		axes.xy = axes.xy && (g.kind == "scatter" || g.kind == "bubble")
+		axes.edges = axes.edges && (g.kind == "area" || g.kind == "line")
+		axes.horizontal = axes.horizontal || (g.kind == "bar" && g.barDir == "bar")
+		percent = percent || g.grouping == "percentStacked"
+		hasBar = hasBar || g.kind == "bar" || g.kind == "area"
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return order[groups[i].kind] < order[groups[j].kind] })
+	if len(groups) == 0 {
+		return
+	}
+	var categories []string
+	axes.n, categories = c.categories(groups...)
+	lo, hi := c.valueRange(groups...)
+	if hasBar {
+		lo, hi = math.Min(lo, 0), math.Max(hi, 0)
+	}
+	for _, ax := range c.space.Chart.PlotArea.ValAx {
+		if ax.Scaling != nil && ax.Scaling.Min != nil && ax.Scaling.Min.Val != nil {
+			lo = *ax.Scaling.Min.Val
+		}
+		if ax.Scaling != nil && ax.Scaling.Max != nil && ax.Scaling.Max.Val != nil {
+			hi = *ax.Scaling.Max.Val
+		}
+		if axes.xy {
+			break
+		}
+	}
+	ticks := rasterTicks(lo, hi, c.majorUnit())
+	axes.lo, axes.hi = ticks[0], ticks[len(ticks)-1]
+	var xTicks []float64
+	if axes.xy {
+		xlo, xhi := c.xRange(groups)
+		xTicks = rasterTicks(xlo, xhi, 0)
+		axes.xlo, axes.xhi = xTicks[0], xTicks[len(xTicks)-1]
+	}
+	size := 9.0
+	labels := make([]string, len(ticks))
+	var labelWidth float64
+	for i, tick := range ticks {
+		labels[i] = c.formatValue(tick, percent)
+		labelWidth = math.Max(labelWidth, c.font.width(labels[i], size))
+	}
+	if axes.horizontal {
+		labelWidth = 0
+		for _, label := range categories {
+			labelWidth = math.Max(labelWidth, c.font.width(label, size))
+		}
+		labelWidth = math.Min(labelWidth, area[2]/3)
+	}
+	axes.plot = [4]float64{area[0] + labelWidth + 4, area[1] + size*0.6, area[2] - labelWidth - 4 - size, area[3] - size*1.8 - 4}
+	if axes.plot[2] <= 0 || axes.plot[3] <= 0 {
+		return
+	}
+	c.drawAxes(axes, ticks, labels, xTicks, categories)
+	for _, g := range groups {
+		switch g.kind {
+		case "area":
+			c.drawAreas(g, axes)
+		case "bar":
+			c.drawBars(g, axes)
+		case "line":
+			c.drawLines(g, axes)
+		default:
+			c.drawXY(g, axes)
+		}
+	}
+}
+
+// xRange returns the minimum and maximum x values of the scatter and bubble
+// chart groups, the index of the data point will be used as the x value if
+// the x values of the series not been specified.
+func (c *rasterChart) xRange(groups []*rasterGroup) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, g := range groups {
+		for _, s := range g.series {
+			for i := range s.values {
+				x := s.x(i)
+				lo, hi = math.Min(lo, x), math.Max(hi, x)
+			}
+		}
+	}
+	if math.IsInf(lo, 1) {
+		return 0, 1
+	}
+	if lo > 0 && (hi-lo)/hi > 1.0/6 {
+		lo = 0
+	}
+	return lo, hi
+}
+
+// x returns the x value of the data point by given data point index.
+func (s *rasterSeries) x(i int) float64 {
+	if i < len(s.xValues) {
+		return s.xValues[i]
+	}
+	return float64(i + 1)
+}
+
+// point returns the position of the data point by given category coordinate
+// and value.
+func (a *rasterAxes) point(t, v float64) [2]float64 {
+	f := (v - a.lo) / (a.hi - a.lo)
+	if a.horizontal {
+		return [2]float64{a.plot[0] + f*a.plot[2], a.plot[1] + a.plot[3] - t/float64(a.n)*a.plot[3]}
+	}
+	return [2]float64{a.plot[0] + t/float64(a.n)*a.plot[2], a.plot[1] + a.plot[3] - f*a.plot[3]}
+}
+
+// xyPoint returns the position of the data point by given x and y values.
+func (a *rasterAxes) xyPoint(x, y float64) [2]float64 {
+	return [2]float64{
+		a.plot[0] + (x-a.xlo)/(a.xhi-a.xlo)*a.plot[2],
+		a.plot[1] + a.plot[3] - (y-a.lo)/(a.hi-a.lo)*a.plot[3],
+	}
+}
+
+// categoryPos returns the category coordinate of the data point by given
+// data point index, the data points of the area and line charts will be
+// placed on the edges of the plot area if the chart without bars.
+func (a *rasterAxes) categoryPos(i int) float64 {
+	if !a.edges {
+		return float64(i) + 0.5
+	}
+	if a.n == 1 {
+		return 0.5
+	}
+	return float64(i) * float64(a.n) / float64(a.n-1)
+}
+
+// drawAxes provides a function to draw the gridlines, axis lines and axis
+// labels of the chart.
+func (c *rasterChart) drawAxes(axes *rasterAxes, ticks []float64, labels []string, xTicks []float64, categories []string) {
+	size, plot := 9.0, axes.plot
+	var gridlines [][4]float64
+	for i, tick := range ticks {
+		p := axes.point(0, tick)
+		if axes.xy {
+			p = axes.xyPoint(axes.xlo, tick)
+		}
+		if axes.horizontal {
+			gridlines = append(gridlines, [4]float64{p[0], plot[1], p[0], plot[1] + plot[3]})
+			c.text(c.font, size, labels[i], p[0], plot[1]+plot[3]+4, "center", "595959")
+			continue
+		}
+		gridlines = append(gridlines, [4]float64{plot[0], p[1], plot[0] + plot[2], p[1]})
+		c.text(c.font, size, labels[i], plot[0]-4, p[1]-size*0.6, "right", "595959")
+	}
+	if axes.xy {
+		for _, tick := range xTicks {
+			p := axes.xyPoint(tick, axes.lo)
+			gridlines = append(gridlines, [4]float64{p[0], plot[1], p[0], plot[1] + plot[3]})
+			c.text(c.font, size, c.formatValue(tick, false), p[0], plot[1]+plot[3]+4, "center", "595959")
+		}
+	}
+	c.canvas.strokeLines(gridlines, 0.75, "", "D9D9D9")
+	base := math.Max(axes.lo, math.Min(0, axes.hi))
+	if axes.xy {
+		return
+	}
+	p := axes.point(0, base)
+	line := [4]float64{plot[0], p[1], plot[0] + plot[2], p[1]}
+	if axes.horizontal {
+		line = [4]float64{p[0], plot[1], p[0], plot[1] + plot[3]}
+	}
+	c.canvas.strokeLines([][4]float64{line}, 0.75, "", "BFBFBF")
+	c.drawCategoryLabels(categories, plot, axes.horizontal)
+}
+
+// drawCategoryLabels provides a function to draw the category labels along
+// the bottom or the left of the plot area, the labels will be skipped if the
+// labels overlap.
+func (c *rasterChart) drawCategoryLabels(categories []string, plot [4]float64, horizontal bool) {
+	n, size := len(categories), 9.0
+	if n == 0 {
+		return
+	}
+	band := plot[2] / float64(n)
+	var maxWidth float64
+	for _, label := range categories {
+		maxWidth = math.Max(maxWidth, c.font.width(label, size))
+	}
+	if horizontal {
+		band, maxWidth = plot[3]/float64(n), size*1.2
+	}
+	step := 1
+	if maxWidth+4 > band {
+		step = int(math.Ceil((maxWidth + 4) / band))
+	}
+	for i := 0; i < n; i += step {
+		if horizontal {
+			y := plot[1] + plot[3] - (float64(i)+0.5)*band
+			c.text(c.font, size, categories[i], plot[0]-4, y-size*0.6, "right", "595959")
+			continue
+		}
+		c.text(c.font, size, categories[i], plot[0]+(float64(i)+0.5)*band, plot[1]+plot[3]+4, "center", "595959")
+	}
+}
+
+// drawBars provides a function to draw the bars of the bar or column chart
+// group, the gap width between the clusters of bars is 150% of the bar width.
+func (c *rasterChart) drawBars(g *rasterGroup, axes *rasterAxes) {
+	bases, tops := c.stack(g)
+	clusters := float64(len(g.series))
+	if g.grouping == "stacked" || g.grouping == "percentStacked" {
+		clusters = 1
+	}
+	width := 1 / (clusters + 1.5)
+	for k, s := range g.series {
+		offset := 0.75 * width
+		if clusters > 1 {
+			offset += float64(k) * width
+		}
+		for i := range tops[k] {
+			if math.IsNaN(tops[k][i]) {
+				continue
+			}
+			t0, t1 := float64(i)+offset, float64(i)+offset+width
+			base, top := math.Max(axes.lo, math.Min(bases[k][i], axes.hi)), math.Max(axes.lo, math.Min(tops[k][i], axes.hi))
+			c.canvas.fillPolygon([][2]float64{
+				axes.point(t0, base), axes.point(t1, base), axes.point(t1, top), axes.point(t0, top),
+			}, c.pointColor(g, s, i))
+		}
+	}
+}
+
+// drawLines provides a function to draw the lines and markers of the line
+// chart group, the lines will be broken at the blank data points.
+func (c *rasterChart) drawLines(g *rasterGroup, axes *rasterAxes) {
+	_, tops := c.stack(g)
+	for k, s := range g.series {
+		var points [][2]float64
+		flush := func() {
+			if s.line && len(points) > 1 {
+				c.canvas.strokePolyline(points, 2.25, s.lineColor)
+			}
+			points = nil
+		}
+		for i, v := range tops[k] {
+			if math.IsNaN(v) {
+				flush()
+				continue
+			}
+			points = append(points, axes.point(axes.categoryPos(i), v))
+		}
+		flush()
+		for i, v := range tops[k] {
+			if s.marker && !math.IsNaN(v) {
+				c.drawMarker(axes.point(axes.categoryPos(i), v), s.symbol, s.markerSize, c.markerColor(g, s, i))
+			}
+		}
+	}
+}
+
+// drawAreas provides a function to draw the areas of the area chart group,
+// the blank data points will be treated as zero.
+func (c *rasterChart) drawAreas(g *rasterGroup, axes *rasterAxes) {
+	bases, tops := c.stack(g)
+	for k, s := range g.series {
+		var upper, lower [][2]float64
+		for i := range tops[k] {
+			base, top := bases[k][i], tops[k][i]
+			if math.IsNaN(top) {
+				top = 0
+			}
+			t := axes.categoryPos(i)
+			upper = append(upper, axes.point(t, math.Max(axes.lo, math.Min(top, axes.hi))))
+			lower = append([][2]float64{axes.point(t, math.Max(axes.lo, math.Min(base, axes.hi)))}, lower...)
+		}
+		c.canvas.fillPolygon(append(upper, lower...), s.color)
+	}
+}
+
+// drawXY provides a function to draw the scatter or bubble chart group, the
+// diameter of the largest bubble is 25% of the plot area by default.
+func (c *rasterChart) drawXY(g *rasterGroup, axes *rasterAxes) {
+	var maxSize float64
+	for _, s := range g.series {
+		for _, size := range s.sizes {
+			if size > maxSize {
+				maxSize = size
+			}
+		}
+	}
+	maxRadius := math.Min(axes.plot[2], axes.plot[3]) / 8 * g.bubbleScale / 100
+	for _, s := range g.series {
+		var (
+			points  [][2]float64
+			indexes []int
+		)
+		for i, v := range s.values {
+			if math.IsNaN(v) {
+				continue
+			}
+			p := axes.xyPoint(s.x(i), v)
+			points, indexes = append(points, p), append(indexes, i)
+			if g.kind != "bubble" {
+				continue
+			}
+			if i < len(s.sizes) && s.sizes[i] > 0 && maxSize > 0 {
+				radius := math.Sqrt(s.sizes[i]/maxSize) * maxRadius
+				c.canvas.fillPolygon(rasterSector(p[0], p[1], 0, radius, 0, 2*math.Pi), c.pointColor(g, s, i))
+			}
+		}
+		if g.kind == "bubble" {
+			continue
+		}
+		if s.line && len(points) > 1 {
+			c.canvas.strokePolyline(points, 2.25, s.lineColor)
+		}
+		for i, p := range points {
+			if s.marker {
+				c.drawMarker(p, s.symbol, s.markerSize, c.markerColor(g, s, indexes[i]))
+			}
+		}
+	}
+}
+
+// drawMarker provides a function to draw the data point marker by given
+// position, marker symbol, size in points and color.
+func (c *rasterChart) drawMarker(p [2]float64, symbol string, size float64, hexColor string) {
+	r := size / 2
+	x, y := p[0], p[1]
+	switch symbol {
+	case "square":
+		c.canvas.fillPolygon([][2]float64{{x - r, y - r}, {x + r, y - r}, {x + r, y + r}, {x - r, y + r}}, hexColor)
+	case "diamond":
+		c.canvas.fillPolygon([][2]float64{{x, y - r}, {x + r, y}, {x, y + r}, {x - r, y}}, hexColor)
+	case "triangle":
+		c.canvas.fillPolygon([][2]float64{{x, y - r}, {x + r, y + r}, {x - r, y + r}}, hexColor)
+	case "x", "star", "plus", "dash":
+		lines := map[string][][4]float64{
+			"x":    {{x - r, y - r, x + r, y + r}, {x - r, y + r, x + r, y - r}},
+			"star": {{x - r, y - r, x + r, y + r}, {x - r, y + r, x + r, y - r}, {x, y - r, x, y + r}},
+			"plus": {{x - r, y, x + r, y}, {x, y - r, x, y + r}},
+			"dash": {{x - r, y, x + r, y}},
+		}[symbol]
+		c.canvas.strokeLines(lines, 1, "", hexColor)
+	default:
+		c.canvas.fillPolygon(rasterSector(x, y, 0, r, 0, 2*math.Pi), hexColor)
+	}
+}
+
+// rasterTicks returns the values of the major tick marks of the value axis
+// by given minimum, maximum values and the major unit. The major unit will
+// be calculated if the major unit is zero.
+func rasterTicks(lo, hi, unit float64) []float64 {
+	if hi <= lo {
+		hi = lo + 1
+	}
+	if unit <= 0 || (hi-lo)/unit > 1000 {
+		raw := (hi - lo) / 5
+		magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+		for _, m := range []float64{1, 2, 5, 10} {
+			if unit = m * magnitude; raw <= unit {
+				break
+			}
+		}
+	}
+	var ticks []float64
+	for v := math.Floor(lo/unit+1e-9) * unit; len(ticks) < 2 || ticks[len(ticks)-1] < hi-unit*1e-9; v += unit {
+		ticks = append(ticks, math.Round(v/unit)*unit)
+	}
+	return ticks
+}
+
+// rasterSector returns the polygon of the circular sector or the annular
+// sector by given center, inner and outer radius, start and end angles in
+// radians.
+func rasterSector(cx, cy, inner, outer, start, end float64) [][2]float64 {
+	n := int(math.Ceil(math.Abs(end-start) * outer / 2))
+	if n < 8 {
+		n = 8
+	}
+	var points [][2]float64
+	if inner <= 0 {
+		points = append(points, [2]float64{cx, cy})
+	}
+	for i := 0; i <= n; i++ {
+		angle := start + (end-start)*float64(i)/float64(n)
+		points = append(points, [2]float64{cx + outer*math.Cos(angle), cy + outer*math.Sin(angle)})
+	}
+	for i := n; inner > 0 && i >= 0; i-- {
+		angle := start + (end-start)*float64(i)/float64(n)
+		points = append(points, [2]float64{cx + inner*math.Cos(angle), cy + inner*math.Sin(angle)})
+	}
+	return points
+}
+
+// rasterLuminance returns the RGB hex color by given RGB hex color, the
+// luminance modulation and offset.
+func rasterLuminance(hexColor string, lumMod, lumOff float64) string {
+	if lumMod == 1 && lumOff == 0 {
+		return hexColor
+	}
+	rgb, _ := strconv.ParseUint(hexColor, 16, 32)
+	h, s, l := RGBToHSL(uint8(rgb>>16), uint8(rgb>>8), uint8(rgb))
+	r, g, b := HSLToRGB(h, s, math.Max(0, math.Min(1, l*lumMod+lumOff)))
+	return fmt.Sprintf("%02X%02X%02X", r, g, b)
+}
+
+// rasterColor returns the color by given RGB hex color.
+func rasterColor(hexColor string) color.RGBA {
+	rgb, _ := strconv.ParseUint(hexColor, 16, 32)
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xFF}
+}
+
+// newRasterCanvas provides a function to create the canvas filled in white
+// by given width and height in points and the pixels per point.
+func newRasterCanvas(width, height, scale float64) *rasterCanvas {
+	w, h := int(math.Ceil(width*scale-1e-6)), int(math.Ceil(height*scale-1e-6))
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	return &rasterCanvas{img: img, scale: scale, images: make(map[*pdfImage]image.Image)}
+}
+
+// bounds returns the current clipping rectangle in pixels.
+func (rc *rasterCanvas) bounds() image.Rectangle {
+	if n := len(rc.clips); n > 0 {
+		return rc.clips[n-1]
+	}
+	return rc.img.Bounds()
+}
+
+// pixels returns the rectangle in pixels by given rectangle in points.
+func (rc *rasterCanvas) pixels(rect [4]float64) image.Rectangle {
+	return image.Rect(
+		int(math.Round(rect[0]*rc.scale)), int(math.Round(rect[1]*rc.scale)),
+		int(math.Round((rect[0]+rect[2])*rc.scale)), int(math.Round((rect[1]+rect[3])*rc.scale)),
+	)
+}
+
+// clip provides a function to save the clipping rectangle and intersect the
+// clipping rectangle with the given rectangle.
+func (rc *rasterCanvas) clip(rect [4]float64) {
+	rc.clips = append(rc.clips, rc.pixels(rect).Intersect(rc.bounds()))
+}
+
+// restore provides a function to restore the clipping rectangle saved by the
+// clip function.
+func (rc *rasterCanvas) restore() {
+	if n := len(rc.clips); n > 0 {
+		rc.clips = rc.clips[:n-1]
+	}
+}
+
+// fillRect provides a function to fill the rectangle aligned to the pixels
+// by given color.
+func (rc *rasterCanvas) fillRect(rect [4]float64, hexColor string) {
+	draw.Draw(rc.img, rc.pixels(rect).Intersect(rc.bounds()), image.NewUniform(rasterColor(hexColor)), image.Point{}, draw.Over)
+}
+
+// strokeLines provides a function to stroke the lines by given line width,
+// dash pattern and color. The horizontal and vertical lines will be aligned
+// to the pixels before the positions of the lines, and the line width is at
+// least one pixel.
+func (rc *rasterCanvas) strokeLines(lines [][4]float64, width float64, dash, hexColor string) {
+	w := math.Max(width*rc.scale, 1)
+	var pattern []float64
+	for _, field := range strings.Fields(dash) {
+		if length, err := strconv.ParseFloat(field, 64); err == nil && length > 0 {
+			pattern = append(pattern, math.Max(length*rc.scale, 1))
+		}
+	}
+	for _, line := range lines {
+		x1, y1, x2, y2 := line[0]*rc.scale, line[1]*rc.scale, line[2]*rc.scale, line[3]*rc.scale
+		length := math.Hypot(x2-x1, y2-y1)
+		if length == 0 {
+			continue
+		}
+		dx, dy := (x2-x1)/length, (y2-y1)/length
+		for pos, i := 0.0, 0; pos < length; i++ {
+			end := length
+			if len(pattern) > 0 {
+				end = math.Min(length, pos+pattern[i%len(pattern)])
+			}
+			if len(pattern) == 0 || i%2 == 0 {
+				rc.strokeSegment(x1+dx*pos, y1+dy*pos, x1+dx*end, y1+dy*end, w, hexColor)
+			}
+			pos = end
+		}
+	}
+}
+
+// strokeSegment provides a function to stroke the line segment by given
+// positions and line width in pixels and color.
+func (rc *rasterCanvas) strokeSegment(x1, y1, x2, y2, w float64, hexColor string) {
+	if x1 == x2 || y1 == y2 {
+		size := math.Max(math.Round(w), 1)
+		start := func(pos float64) int { return int(math.Ceil(pos - size/2 - 0.5)) }
+		rect := image.Rect(int(math.Round(math.Min(x1, x2))), start(y1), int(math.Round(math.Max(x1, x2))), start(y1)+int(size))
+		if x1 == x2 {
+			rect = image.Rect(start(x1), int(math.Round(math.Min(y1, y2))), start(x1)+int(size), int(math.Round(math.Max(y1, y2))))
+		}
+		draw.Draw(rc.img, rect.Intersect(rc.bounds()), image.NewUniform(rasterColor(hexColor)), image.Point{}, draw.Over)
+		return
+	}
+	length := math.Hypot(x2-x1, y2-y1)
+	nx, ny := -(y2-y1)/length*w/2, (x2-x1)/length*w/2
+	rc.fillPixels([][2]float64{{x1 + nx, y1 + ny}, {x2 + nx, y2 + ny}, {x2 - nx, y2 - ny}, {x1 - nx, y1 - ny}}, hexColor)
+}
+
+// strokePolyline provides a function to stroke the connected line segments
+// by given points, line width and color, the joints of the line segments
+// will be rounded.
+func (rc *rasterCanvas) strokePolyline(points [][2]float64, width float64, hexColor string) {
+	w := math.Max(width*rc.scale, 1)
+	for i := 1; i < len(points); i++ {
+		x1, y1, x2, y2 := points[i-1][0]*rc.scale, points[i-1][1]*rc.scale, points[i][0]*rc.scale, points[i][1]*rc.scale
+		if length := math.Hypot(x2-x1, y2-y1); length > 0 {
+			nx, ny := -(y2-y1)/length*w/2, (x2-x1)/length*w/2
+			rc.fillPixels([][2]float64{{x1 + nx, y1 + ny}, {x2 + nx, y2 + ny}, {x2 - nx, y2 - ny}, {x1 - nx, y1 - ny}}, hexColor)
+		}
+		if i < len(points)-1 && w > 1.5 {
+			rc.fillPolygon(rasterSector(points[i][0], points[i][1], 0, width/2, 0, 2*math.Pi), hexColor)
+		}
+	}
+}
+
+// fillPolygon provides a function to fill the polygon by given points in
+// points and color.
+func (rc *rasterCanvas) fillPolygon(points [][2]float64, hexColor string) {
+	pixels := make([][2]float64, len(points))
+	for i, p := range points {
+		pixels[i] = [2]float64{p[0] * rc.scale, p[1] * rc.scale}
+	}
+	rc.fillPixels(pixels, hexColor)
+}
+
+// fillPixels provides a function to fill the polygon by given points in
+// pixels and color.
+func (rc *rasterCanvas) fillPixels(points [][2]float64, hexColor string) {
+	if len(points) < 3 {
+		return
+	}
+	path := make(sfnt.Segments, len(points))
+	for i, p := range points {
+		path[i] = sfnt.Segment{Op: sfnt.SegmentOpLineTo, Args: [3]fixed.Point26_6{rasterPoint(p[0], p[1])}}
+	}
+	path[0].Op = sfnt.SegmentOpMoveTo
+	rc.fillSegments(path, hexColor)
+}
+
+// fillSegments provides a function to fill the path by given segments in
+// pixels and color, the path will be clipped by the clipping rectangle.
+func (rc *rasterCanvas) fillSegments(path sfnt.Segments, hexColor string) {
+	b := path.Bounds()
+	box := image.Rect(b.Min.X.Floor(), b.Min.Y.Floor(), b.Max.X.Ceil(), b.Max.Y.Ceil()).Intersect(rc.bounds())
+	if box.Empty() {
+		return
+	}
+	z := vector.NewRasterizer(box.Dx(), box.Dy())
+	pt := func(p fixed.Point26_6) (float32, float32) {
+		return float32(p.X)/64 - float32(box.Min.X), float32(p.Y)/64 - float32(box.Min.Y)
+	}
+	for i, seg := range path {
+		x1, y1 := pt(seg.Args[0])
+		x2, y2 := pt(seg.Args[1])
+		x3, y3 := pt(seg.Args[2])
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			if i > 0 {
+				z.ClosePath()
+			}
+			z.MoveTo(x1, y1)
+		case sfnt.SegmentOpLineTo:
+			z.LineTo(x1, y1)
+		case sfnt.SegmentOpQuadTo:
+			z.QuadTo(x1, y1, x2, y2)
+		case sfnt.SegmentOpCubeTo:
+			z.CubeTo(x1, y1, x2, y2, x3, y3)
+		}
+	}
+	z.ClosePath()
+	z.Draw(rc.img, box, image.NewUniform(rasterColor(hexColor)), image.Point{})
+}
+
+// fillText provides a function to draw the text by given font, font size,
+// the origin of the baseline, the counterclockwise rotation angle in radians
+// and color.
+func (rc *rasterCanvas) fillText(pf *pdfFont, size float64, text string, x, y, angle float64, hexColor string) {
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	ppem := fixed.Int26_6(size * rc.scale * 64)
+	var path sfnt.Segments
+	var advance float64
+	for _, r := range text {
+		idx, width := pf.glyph(r)
+		segments, err := pf.font.LoadGlyph(&pf.buf, idx, ppem, nil)
+		for _, seg := range segments {
+			for i := range seg.Args {
+				gx, gy := float64(seg.Args[i].X)/64+advance*rc.scale, float64(seg.Args[i].Y)/64
+				seg.Args[i] = rasterPoint(x*rc.scale+gx*cos+gy*sin, y*rc.scale-gx*sin+gy*cos)
+			}
+			if err == nil {
+				path = append(path, seg)
+			}
+		}
+		advance += width * size / 1000
+	}
+	if len(path) > 0 {
+		rc.fillSegments(path, hexColor)
+	}
+}
+
+// drawImage provides a function to draw the embedded picture in the
+// rectangle.
+func (rc *rasterCanvas) drawImage(img *pdfImage, rect [4]float64) {
+	src, ok := rc.images[img]
+	if !ok {
+		src = img.decode()
+		rc.images[img] = src
+	}
+	if src != nil {
+		rc.drawRaster(src, rect)
+	}
+}
+
+// drawRaster provides a function to scale and draw the image in the
+// rectangle.
+func (rc *rasterCanvas) drawRaster(src image.Image, rect [4]float64) {
+	dst := rc.img.SubImage(rc.bounds()).(*image.RGBA)
+	draw.ApproxBiLinear.Scale(dst, rc.pixels(rect), src, src.Bounds(), draw.Over, nil)
+}
+
+// decode returns the decoded image of the embedded picture, it returns nil
+// if the picture could not be decoded.
+func (img *pdfImage) decode() image.Image {
+	if img.filter == "DCTDecode" {
+		src, _, err := image.Decode(bytes.NewReader(img.data))
+		if err != nil {
+			return nil
+		}
+		return src
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, img.width, img.height))
+	for i := 0; i < img.width*img.height && i*3+2 < len(img.data); i++ {
+		dst.Pix[i*4], dst.Pix[i*4+1], dst.Pix[i*4+2], dst.Pix[i*4+3] = img.data[i*3], img.data[i*3+1], img.data[i*3+2], 0xFF
+		if i < len(img.mask) {
+			dst.Pix[i*4+3] = img.mask[i]
+		}
+	}
+	return dst
+}
+
+// rasterPoint returns the fixed-point position by given position in pixels.
+func rasterPoint(x, y float64) fixed.Point26_6 {
+	return fixed.Point26_6{X: fixed.Int26_6(math.Round(x * 64)), Y: fixed.Int26_6(math.Round(y * 64))}
+}
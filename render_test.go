@@ -0,0 +1,154 @@
+package excelize
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderImage(t *testing.T) {
+	f := NewFile()
+	for cell, value := range map[string]interface{}{
+		"A1": "Name", "B1": "Value", "A2": "Apple", "B2": 12.5, "A3": "Banana", "B3": 30,
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	style, err := f.NewStyle(&Style{
+		Fill:   Fill{Type: "pattern", Pattern: 1, Color: []string{"FF0000"}},
+		Border: []Border{{Type: "bottom", Color: "0000FF", Style: 2}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "B1", style))
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "B", 12))
+	assert.NoError(t, f.MergeCell("Sheet1", "A4", "B5"))
+
+	img, err := f.RenderImage("Sheet1", RenderOptions{Fonts: testFonts, FallbackFont: "Go"})
+	assert.NoError(t, err)
+	// The column width of 12 characters is 89 pixels, and the default row
+	// height is 20 pixels
+	assert.Equal(t, image.Rect(0, 0, 180, 100), img.Bounds())
+	assert.Equal(t, color.RGBA{R: 0xFF, A: 0xFF}, img.At(40, 5))
+	assert.Equal(t, color.RGBA{B: 0xFF, A: 0xFF}, img.At(40, 19))
+	assert.Equal(t, color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}, img.At(40, 60))
+
+	// Test render image with scale and range reference
+	img, err = f.RenderImage("Sheet1", RenderOptions{RangeRef: "A1:A2", Scale: 2, Fonts: testFonts, FallbackFont: "Go"})
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 180, 80), img.Bounds())
+
+	// Test render image without gridlines
+	assert.NoError(t, f.SetSheetView("Sheet1", 0, &ViewOptions{ShowGridLines: boolPtr(false)}))
+	img, err = f.RenderImage("Sheet1", RenderOptions{RangeRef: "A2:A3", Fonts: testFonts, FallbackFont: "Go"})
+	assert.NoError(t, err)
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		assert.NotEqual(t, color.RGBA{R: 0xBF, G: 0xBF, B: 0xBF, A: 0xFF}, img.At(88, y))
+	}
+
+	// Test render image on the empty worksheet
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	img, err = f.RenderImage("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 64, 20), img.Bounds())
+
+	// Test render image with invalid options
+	_, err = f.RenderImage("Sheet1")
+	assert.EqualError(t, err, "font data of font family Calibri does not exist")
+	_, err = f.RenderImage("SheetN")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	_, err = f.RenderImage("Sheet1", RenderOptions{RangeRef: "A:B1"})
+	assert.Error(t, err)
+	_, err = f.RenderImage("Sheet1", RenderOptions{Chart: "A"})
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	_, err = f.RenderImage("Sheet1", RenderOptions{Chart: "E1"})
+	assert.EqualError(t, err, "chart at cell E1 does not exist")
+	assert.NoError(t, f.Close())
+}
+
+func TestRenderImageChart(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{nil, "Apple", "Orange", "Pear"}, {"Small", 2, 3, 3}, {"Normal", 5, 2, 4}, {"Large", 6, 7, 8},
+	} {
+		cell, err := CoordinatesToCellName(1, idx+1)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	series := []ChartSeries{
+		{
+			Name: "Sheet1!$A$2", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$2:$D$2",
+			Fill:   Fill{Type: "pattern", Color: []string{"FF0000"}, Pattern: 1},
+			Marker: ChartMarker{Fill: Fill{Type: "pattern", Color: []string{"FF0000"}, Pattern: 1}},
+		},
+		{Name: "Sheet1!$A$3", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$3:$D$3"},
+		{Name: "Sheet1!$A$4", Categories: "Sheet1!$B$1:$D$1", Values: "Sheet1!$B$4:$D$4", Sizes: "Sheet1!$B$4:$D$4"},
+	}
+	for chartType := Area; chartType <= Bubble3D; chartType++ {
+		sheet := "Chart" + string(rune('A'+chartType/26)) + string(rune('A'+chartType%26))
+		_, err := f.NewSheet(sheet)
+		assert.NoError(t, err)
+		assert.NoError(t, f.AddChart(sheet, "B2", &Chart{
+			Type: chartType, Series: series, Title: []RichTextRun{{Text: "Fruit"}},
+			Legend: ChartLegend{Position: "bottom"},
+		}))
+		img, err := f.RenderImage(sheet, RenderOptions{Chart: "B2", Fonts: testFonts, FallbackFont: "Go"})
+		assert.NoError(t, err)
+		// The default chart size is 480 x 260 pixels
+		assert.Equal(t, image.Rect(0, 0, 480, 260), img.Bounds(), chartType)
+		if chartType < Surface3D || chartType > WireframeContour {
+			assert.True(t, hasTestImageColor(img, color.RGBA{R: 0xFF, A: 0xFF}), chartType)
+		}
+	}
+	// Test render the range with the chart
+	img, err := f.RenderImage("ChartAZ", RenderOptions{RangeRef: "A1:J20", Fonts: testFonts, FallbackFont: "Go"})
+	assert.NoError(t, err)
+	assert.True(t, hasTestImageColor(img, color.RGBA{R: 0xFF, A: 0xFF}))
+
+	// Test render the chart with the cached data after the referenced
+	// worksheet been deleted
+	assert.NoError(t, f.DeleteSheet("Sheet1"))
+	img, err = f.RenderImage("ChartAV", RenderOptions{Chart: "B2", Fonts: testFonts, FallbackFont: "Go"})
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 480, 260), img.Bounds())
+
+	// Test render the chart with unsupported charset
+	f.Pkg.Store("xl/charts/chart1.xml", MacintoshCyrillicCharset)
+	_, err = f.RenderImage("ChartAA", RenderOptions{Chart: "B2"})
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestExportPNG(t *testing.T) {
+	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportPNG("Sheet1", &buf, RenderOptions{RangeRef: "A1:F10", Fonts: testFonts, FallbackFont: "Go"}))
+	img, err := png.Decode(&buf)
+	assert.NoError(t, err)
+	assert.False(t, img.Bounds().Empty())
+	file, err := os.Create(filepath.Join("test", "TestExportPNG.png"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.ExportPNG("Sheet2", file, RenderOptions{Fonts: testFonts, FallbackFont: "Go"}))
+	assert.NoError(t, file.Close())
+	assert.EqualError(t, f.ExportPNG("SheetN", &buf), "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+}
+
+// hasTestImageColor returns if the image contains the pixel in the given
+// color.
+func hasTestImageColor(img image.Image, clr color.Color) bool {
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			if img.At(x, y) == clr {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -93,6 +93,28 @@ type Rows struct {
 	decoder                 *xml.Decoder
 	token                   xml.Token
 	curRowOpts, seekRowOpts RowOpts
+	sharedFormulas          map[int]xlsxC
+	hyperlinks              map[string]string
+	hyperlinkRanges         []hyperlinkRange
+}
+
+// CellInfo directly maps the reference, value, type, style, formula and
+// hyperlink of a cell, which returned by the rows iterator.
+type CellInfo struct {
+	Cell      string
+	RawValue  string
+	Value     string
+	Type      CellType
+	StyleID   int
+	Formula   string
+	Hyperlink string
+}
+
+// hyperlinkRange directly maps the coordinates and target of the hyperlink
+// which refers to a range of cells.
+type hyperlinkRange struct {
+	coordinates []int
+	target      string
 }
 
 // Next will return true if it finds the next row element.
@@ -149,14 +171,153 @@ func (rows *Rows) Close() error {
 // data as a stream, returns each cell in a row as is, and will not skip empty
 // rows in the tail of the worksheet.
 func (rows *Rows) Columns(opts ...Options) ([]string, error) {
+	var rowIterator rowXMLIterator
+	rows.rawCellValue = rows.f.getOptions(opts...).RawCellValue
+	return rowIterator.cells, rows.readRow(&rowIterator)
+}
+
+// rowCells returns the current row's cells, the value of each cell is the
+// raw cell value, and the reference of each cell is set.
+func (rows *Rows) rowCells() ([]xlsxC, error) {
+	rowIterator := rowXMLIterator{keepCells: true}
+	rows.rawCellValue = true
+	return rowIterator.xlsxCells, rows.readRow(&rowIterator)
+}
+
+// Cells return the current row's cells with the reference, raw value, cell
+// type, formatted value, style ID, formula and hyperlink of each cell. This
+// fetches the worksheet data as a stream, and the cells without value, style
+// and formula will be skipped. For example:
+//
+//	rows, err := f.Rows("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for rows.Next() {
+//	    cells, err := rows.Cells()
+//	    if err != nil {
+//	        fmt.Println(err)
+//	        break
+//	    }
+//	    for _, cell := range cells {
+//	        fmt.Println(cell.Cell, cell.Type, cell.Value, cell.Formula)
+//	    }
+//	}
+//	if err = rows.Close(); err != nil {
+//	    fmt.Println(err)
+//	}
+func (rows *Rows) Cells() ([]CellInfo, error) {
+	if rows.hyperlinks == nil {
+		if err := rows.readHyperlinks(); err != nil {
+			return nil, err
+		}
+	}
+	rowIterator := rowXMLIterator{keepCellInfo: true}
+	err := rows.readRow(&rowIterator)
+	return rowIterator.cellInfos, err
+}
+
+// readHyperlinks provides a function to read the hyperlinks of the worksheet
+// by skip the sheet data, and resolve the target of each hyperlink.
+func (rows *Rows) readHyperlinks() error {
+	needClose, decoder, tempFile, err := rows.f.xmlDecoder(rows.sheet)
+	if needClose && err == nil {
+		defer tempFile.Close()
+	}
+	if err != nil {
+		return err
+	}
+	rels, err := rows.f.relsReader("xl/worksheets/_rels/" + strings.TrimPrefix(rows.sheet, "xl/worksheets/") + ".rels")
+	if err != nil {
+		return err
+	}
+	rows.hyperlinks = make(map[string]string)
+	for {
+		token, _ := decoder.Token()
+		if token == nil {
+			return nil
+		}
+		xmlElement, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if xmlElement.Name.Local == "sheetData" {
+			if err = decoder.Skip(); err != nil {
+				return err
+			}
+			continue
+		}
+		if xmlElement.Name.Local != "hyperlink" {
+			continue
+		}
+		var link xlsxHyperlink
+		if err = decoder.DecodeElement(&link, &xmlElement); err != nil {
+			return err
+		}
+		target := link.Location
+		if link.RID != "" && rels != nil {
+			for _, rel := range rels.Relationships {
+				if rel.ID == link.RID {
+					target = rel.Target
+					break
+				}
+			}
+		}
+		if !strings.Contains(link.Ref, ":") {
+			rows.hyperlinks[link.Ref] = target
+			continue
+		}
+		coordinates, err := rangeRefToCoordinates(link.Ref)
+		if err != nil {
+			return err
+		}
+		_ = sortCoordinates(coordinates)
+		rows.hyperlinkRanges = append(rows.hyperlinkRanges, hyperlinkRange{coordinates: coordinates, target: target})
+	}
+}
+
+// cellInfo returns the typed cell information by given cell and column
+// number.
+func (rows *Rows) cellInfo(c xlsxC, col int) CellInfo {
+	cell, _ := CoordinatesToCellName(col, rows.curRow)
+	info := CellInfo{Cell: cell, Type: cellTypes[c.T], StyleID: c.S}
+	info.RawValue, _ = c.getValueFrom(rows.f, rows.sst, true)
+	info.Value, _ = c.getValueFrom(rows.f, rows.sst, false)
+	if c.F != nil {
+		info.Formula = c.F.Content
+		if c.F.T == STCellFormulaTypeShared && c.F.Si != nil {
+			if rows.sharedFormulas == nil {
+				rows.sharedFormulas = make(map[int]xlsxC)
+			}
+			if c.F.Ref != "" {
+				rows.sharedFormulas[*c.F.Si] = xlsxC{R: cell, F: c.F}
+			} else if master, ok := rows.sharedFormulas[*c.F.Si]; ok {
+				info.Formula = shiftSharedFormula(master.R, master.F.Content, cell)
+			}
+		}
+	}
+	var ok bool
+	if info.Hyperlink, ok = rows.hyperlinks[cell]; !ok {
+		for _, link := range rows.hyperlinkRanges {
+			if cellInRange([]int{col, rows.curRow}, link.coordinates) {
+				info.Hyperlink = link.target
+				break
+			}
+		}
+	}
+	return info
+}
+
+// readRow provides a function to parse the current row's cells by the
+// worksheet row SAX parser.
+func (rows *Rows) readRow(rowIterator *rowXMLIterator) error {
 	if rows.curRow > rows.seekRow {
-		return nil, nil
+		return nil
 	}
-	var rowIterator rowXMLIterator
 	var token xml.Token
-	rows.rawCellValue = rows.f.getOptions(opts...).RawCellValue
 	if rows.sst, rowIterator.err = rows.f.sharedStringsReader(); rowIterator.err != nil {
-		return rowIterator.cells, rowIterator.err
+		return rowIterator.err
 	}
 	for {
 		if rows.token != nil {
@@ -178,21 +339,21 @@ func (rows *Rows) Columns(opts ...Options) ([]string, error) {
 				rows.seekRowOpts = extractRowOpts(xmlElement.Attr)
 				if rows.curRow > rows.seekRow {
 					rows.token = nil
-					return rowIterator.cells, rowIterator.err
+					return rowIterator.err
 				}
 			}
-			if rows.rowXMLHandler(&rowIterator, &xmlElement, rows.rawCellValue); rowIterator.err != nil {
+			if rows.rowXMLHandler(rowIterator, &xmlElement, rows.rawCellValue); rowIterator.err != nil {
 				rows.token = nil
-				return rowIterator.cells, rowIterator.err
+				return rowIterator.err
 			}
 			rows.token = nil
 		case xml.EndElement:
 			if xmlElement.Name.Local == "sheetData" {
-				return rowIterator.cells, rowIterator.err
+				return rowIterator.err
 			}
 		}
 	}
-	return rowIterator.cells, rowIterator.err
+	return rowIterator.err
 }
 
 // extractRowOpts extract row element attributes.
@@ -224,6 +385,10 @@ type rowXMLIterator struct {
 	inElement        string
 	cellCol, cellRow int
 	cells            []string
+	keepCells        bool
+	xlsxCells        []xlsxC
+	keepCellInfo     bool
+	cellInfos        []CellInfo
 }
 
 // rowXMLHandler parse the row XML element of the worksheet.
@@ -237,9 +402,21 @@ func (rows *Rows) rowXMLHandler(rowIterator *rowXMLIterator, xmlElement *xml.Sta
 				return
 			}
 		}
+		if rowIterator.keepCellInfo {
+			if !colCell.hasValue() {
+				return
+			}
+			rowIterator.cellInfos = append(rowIterator.cellInfos, rows.cellInfo(colCell, rowIterator.cellCol))
+			return
+		}
 		blank := rowIterator.cellCol - len(rowIterator.cells)
 		if val, _ := colCell.getValueFrom(rows.f, rows.sst, raw); val != "" || colCell.F != nil {
 			rowIterator.cells = append(appendSpace(blank, rowIterator.cells), val)
+			if rowIterator.keepCells {
+				colCell.R, _ = CoordinatesToCellName(rowIterator.cellCol, rows.curRow)
+				colCell.V = val
+				rowIterator.xlsxCells = append(rowIterator.xlsxCells, colCell)
+			}
 		}
 	}
 }
@@ -455,6 +632,7 @@ func (f *File) sharedStringsReader() (*xlsxSST, error) {
 			sharedStrings.UniqueCount = sharedStrings.Count
 		}
 		f.SharedStrings = &sharedStrings
+		f.trackPart(defaultXMLPathSharedStrings, f.SharedStrings)
 		for i := range sharedStrings.SI {
 			if sharedStrings.SI[i].T != nil {
 				f.sharedStringsMap[sharedStrings.SI[i].T.Val] = i
@@ -571,6 +749,7 @@ func (f *File) RemoveRow(sheet string, row int) error {
 		return newInvalidRowNumberError(row)
 	}
 
+	defer f.pinWorksheets()()
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
@@ -610,6 +789,7 @@ func (f *File) InsertRows(sheet string, row, n int) error {
 	if n < 1 {
 		return ErrParameterInvalid
 	}
+	defer f.pinWorksheets()()
 	return f.adjustHelper(sheet, rows, row, n)
 }
 
@@ -639,6 +819,7 @@ func (f *File) DuplicateRowTo(sheet string, row, row2 int) error {
 		return newInvalidRowNumberError(row)
 	}
 
+	defer f.pinWorksheets()()
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
@@ -247,6 +247,75 @@ func TestColumns(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRowsCells(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(&Style{NumFmt: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", 1.5, true}))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", style))
+	formulaType, ref := STCellFormulaTypeShared, "D1:D2"
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "B1*2", FormulaOpts{Ref: &ref, Type: &formulaType}))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A1", "https://github.com/xuri/excelize", "External"))
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A2", "Sheet1!A1", "Location"))
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).Hyperlinks.Hyperlink = append(ws.(*xlsxWorksheet).Hyperlinks.Hyperlink,
+		xlsxHyperlink{Ref: "C2:B3", Location: "Sheet1!B1"})
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", 2))
+
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	var results [][]CellInfo
+	for rows.Next() {
+		cells, err := rows.Cells()
+		assert.NoError(t, err)
+		results = append(results, cells)
+	}
+	assert.NoError(t, rows.Close())
+	assert.Equal(t, [][]CellInfo{
+		{
+			{Cell: "A1", RawValue: "Name", Value: "Name", Type: CellTypeSharedString, Hyperlink: "https://github.com/xuri/excelize"},
+			{Cell: "B1", RawValue: "1.5", Value: "1.50", StyleID: style},
+			{Cell: "C1", RawValue: "1", Value: "TRUE", Type: CellTypeBool},
+			{Cell: "D1", Type: CellTypeFormula, Formula: "B1*2"},
+		},
+		{
+			{Cell: "B2", RawValue: "2", Value: "2", Hyperlink: "Sheet1!B1"},
+			{Cell: "D2", Formula: "B2*2"},
+		},
+	}, results)
+
+	// Test get cells with invalid hyperlink range reference
+	ws.(*xlsxWorksheet).Hyperlinks.Hyperlink = append(ws.(*xlsxWorksheet).Hyperlinks.Hyperlink,
+		xlsxHyperlink{Ref: "A:B1"})
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	_, err = rows.Cells()
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	assert.NoError(t, rows.Close())
+
+	// Test get cells with invalid worksheet XML
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(`<worksheet><sheetData><row r="1"></sheetData></worksheet>`))
+	_, err = rows.Cells()
+	assert.EqualError(t, err, "XML syntax error on line 1: element <row> closed by </sheetData>")
+	assert.NoError(t, rows.Close())
+
+	// Test get cells with unsupported charset worksheet relationships
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", []byte(`<worksheet><sheetData/></worksheet>`))
+	f.Relationships.Delete("xl/worksheets/_rels/sheet1.xml.rels")
+	f.Pkg.Store("xl/worksheets/_rels/sheet1.xml.rels", MacintoshCyrillicCharset)
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	_, err = rows.Cells()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, rows.Close())
+	assert.NoError(t, f.Close())
+}
+
 func TestSharedStringsReader(t *testing.T) {
 	f := NewFile()
 	// Test read shared string with unsupported charset
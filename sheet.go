@@ -15,6 +15,7 @@ import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"os"
 	"path"
@@ -24,6 +25,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"unicode/utf16"
 	"unicode/utf8"
 
@@ -76,6 +78,7 @@ func (f *File) contentTypesReader() (*xlsxTypes, error) {
 			Decode(f.ContentTypes); err != nil && err != io.EOF {
 			return f.ContentTypes, err
 		}
+		f.trackPart(defaultXMLPathContentTypes, f.ContentTypes)
 	}
 	return f.ContentTypes, nil
 }
@@ -85,7 +88,9 @@ func (f *File) contentTypesReader() (*xlsxTypes, error) {
 func (f *File) contentTypesWriter() {
 	if f.ContentTypes != nil {
 		output, _ := xml.Marshal(f.ContentTypes)
-		f.saveFileList(defaultXMLPathContentTypes, output)
+		if f.partModified(defaultXMLPathContentTypes, output) {
+			f.saveFileList(defaultXMLPathContentTypes, output)
+		}
 	}
 }
 
@@ -144,29 +149,16 @@ func (f *File) workSheetWriter() {
 	f.Sheet.Range(func(p, ws interface{}) bool {
 		if ws != nil {
 			sheet := ws.(*xlsxWorksheet)
-			if sheet.MergeCells != nil && len(sheet.MergeCells.Cells) > 0 {
-				_ = f.mergeOverlapCells(sheet)
-			}
-			if sheet.Cols != nil && len(sheet.Cols.Col) > 0 {
-				f.mergeExpandedCols(sheet)
-			}
-			sheet.SheetData.Row = trimRow(&sheet.SheetData)
-			if sheet.SheetPr != nil || sheet.Drawing != nil || sheet.Hyperlinks != nil || sheet.Picture != nil || sheet.TableParts != nil {
-				f.addNameSpaces(p.(string), SourceRelationship)
-			}
-			if sheet.DecodeAlternateContent != nil {
-				sheet.AlternateContent = &xlsxAlternateContent{
-					Content: sheet.DecodeAlternateContent.Content,
-					XMLNSMC: SourceRelationshipCompatibility.Value,
-				}
+			if !f.sheetPager.modified(p.(string), sheet) {
+				f.Sheet.Delete(p.(string))
+				f.sheetPager.remove(p.(string))
+				return true
 			}
-			sheet.DecodeAlternateContent = nil
-			// reusing buffer
-			_ = encoder.Encode(sheet)
-			f.saveFileList(p.(string), replaceRelationshipsBytes(f.replaceNameSpaceBytes(p.(string), buffer.Bytes())))
+			f.saveFileList(p.(string), f.encodeWorksheet(p.(string), sheet, buffer, encoder))
 			_, ok := f.checked.Load(p.(string))
 			if ok {
 				f.Sheet.Delete(p.(string))
+				f.sheetPager.remove(p.(string))
 				f.checked.Store(p.(string), false)
 			}
 			buffer.Reset()
@@ -175,6 +167,215 @@ func (f *File) workSheetWriter() {
 	})
 }
 
+// encodeWorksheet provides a function to prepare the worksheet for saving and
+// returns the serialized XML part of the worksheet without the XML header.
+func (f *File) encodeWorksheet(path string, sheet *xlsxWorksheet, buffer *bytes.Buffer, encoder *xml.Encoder) []byte {
+	if sheet.MergeCells != nil && len(sheet.MergeCells.Cells) > 0 {
+		_ = f.mergeOverlapCells(sheet)
+	}
+	if sheet.Cols != nil && len(sheet.Cols.Col) > 0 {
+		f.mergeExpandedCols(sheet)
+	}
+	sheet.SheetData.Row = trimRow(&sheet.SheetData)
+	if sheet.SheetPr != nil || sheet.Drawing != nil || sheet.Hyperlinks != nil || sheet.Picture != nil || sheet.TableParts != nil {
+		f.addNameSpaces(path, SourceRelationship)
+	}
+	if sheet.DecodeAlternateContent != nil {
+		sheet.AlternateContent = &xlsxAlternateContent{
+			Content: sheet.DecodeAlternateContent.Content,
+			XMLNSMC: SourceRelationshipCompatibility.Value,
+		}
+	}
+	sheet.DecodeAlternateContent = nil
+	// reusing buffer
+	_ = encoder.Encode(sheet)
+	return replaceRelationshipsBytes(f.replaceNameSpaceBytes(path, buffer.Bytes()))
+}
+
+// worksheetPager directly maps the decoded worksheets which are tracked by the
+// memory budget of the worksheets, in the least recently used order.
+type worksheetPager struct {
+	mu     sync.Mutex
+	order  []string
+	sheets map[string]loadedWorksheet
+	size   int64
+	pins   int
+}
+
+// loadedWorksheet directly maps the size of the XML part and the fingerprint
+// of a decoded worksheet. The fingerprint is zero for the worksheets which
+// were not decoded from the XML part, such as the new created worksheets.
+type loadedWorksheet struct {
+	size int64
+	hash uint64
+}
+
+// worksheetFingerprint returns the fingerprint of the worksheet for detecting
+// if the worksheet has been modified since it was decoded.
+func worksheetFingerprint(ws *xlsxWorksheet) uint64 {
+	h := fnv.New64a()
+	_ = xml.NewEncoder(h).Encode(ws)
+	return h.Sum64()
+}
+
+// touch provides a function to move the worksheet by given XML path to the
+// end of the least recently used order.
+func (p *worksheetPager) touch(name string) {
+	for i, n := range p.order {
+		if n == name {
+			p.order = append(p.order[:i], p.order[i+1:]...)
+			break
+		}
+	}
+	p.order = append(p.order, name)
+}
+
+// remove provides a function to stop tracking the worksheet by given XML path.
+func (p *worksheetPager) remove(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if sheet, ok := p.sheets[name]; ok {
+		p.size -= sheet.size
+		delete(p.sheets, name)
+		for i, n := range p.order {
+			if n == name {
+				p.order = append(p.order[:i], p.order[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// modified provides a function to check if the tracked worksheet by given XML
+// path has been modified since it was decoded. The worksheets which are not
+// tracked are always considered modified.
+func (p *worksheetPager) modified(name string, ws *xlsxWorksheet) bool {
+	p.mu.Lock()
+	sheet, ok := p.sheets[name]
+	p.mu.Unlock()
+	return !ok || sheet.hash == 0 || worksheetFingerprint(ws) != sheet.hash
+}
+
+// trackWorksheet provides a function to record the access of the worksheet by
+// given XML path when the worksheet memory limit was set. The size of the XML
+// part should be a non-negative value if the worksheet has just been decoded.
+// The least recently used worksheets will be unloaded from memory when the
+// total size of the decoded worksheets exceeds the limit.
+func (f *File) trackWorksheet(name string, ws *xlsxWorksheet, size int64) {
+	if f.options == nil || (f.options.WorksheetMemoryLimit <= 0 && !f.options.IncrementalSave) {
+		return
+	}
+	p := &f.sheetPager
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.sheets == nil {
+		p.sheets = make(map[string]loadedWorksheet)
+	}
+	p.touch(name)
+	if _, ok := p.sheets[name]; ok && size < 0 {
+		return
+	}
+	sheet := loadedWorksheet{size: size}
+	if size < 0 {
+		sheet.size = 0
+	} else {
+		sheet.hash = worksheetFingerprint(ws)
+	}
+	p.size += sheet.size - p.sheets[name].size
+	p.sheets[name] = sheet
+	if p.pins == 0 {
+		f.evictWorksheets()
+	}
+}
+
+// pinWorksheets provides a function to keep the decoded worksheets in memory
+// until the returned function was called. The operations which hold a
+// worksheet while accessing the other worksheets should pin the worksheets,
+// otherwise the held worksheet may be unloaded in the middle of the
+// operation, and the changes of it will be lost. The least recently used
+// worksheets will be unloaded when the last pin was released.
+func (f *File) pinWorksheets() func() {
+	p := &f.sheetPager
+	p.mu.Lock()
+	p.pins++
+	p.mu.Unlock()
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.pins--; p.pins == 0 && f.options != nil {
+			f.evictWorksheets()
+		}
+	}
+}
+
+// evictWorksheets provides a function to unload the least recently used
+// worksheets from memory until the total size of the decoded worksheets
+// doesn't exceed the limit. The most recently used worksheet is always kept.
+// The mutex of the pager should be held by the caller.
+func (f *File) evictWorksheets() {
+	p := &f.sheetPager
+	for i := 0; f.options.WorksheetMemoryLimit > 0 && p.size > f.options.WorksheetMemoryLimit && i < len(p.order)-1; {
+		victim := p.order[i]
+		if !f.unloadWorksheet(victim, p.sheets[victim]) {
+			i++
+			continue
+		}
+		p.size -= p.sheets[victim].size
+		delete(p.sheets, victim)
+		p.order = append(p.order[:i], p.order[i+1:]...)
+	}
+}
+
+// unloadWorksheet provides a function to unload the decoded worksheet by
+// given XML path from memory. The modified worksheet will be serialized to a
+// temporary file, and the XML part of the worksheet which hasn't been
+// modified will be moved to a temporary file if it was stored in memory, so
+// the worksheet will be decoded from the temporary file on next access. It
+// returns false if the worksheet is in use and can't be unloaded.
+func (f *File) unloadWorksheet(name string, sheet loadedWorksheet) bool {
+	if _, ok := f.streams.Load(name); ok {
+		return false
+	}
+	val, ok := f.Sheet.Load(name)
+	if !ok || val == nil {
+		return true
+	}
+	ws := val.(*xlsxWorksheet)
+	if !ws.mu.TryLock() {
+		return false
+	}
+	defer ws.mu.Unlock()
+	var content []byte
+	if sheet.hash == 0 || worksheetFingerprint(ws) != sheet.hash {
+		var buffer bytes.Buffer
+		content = append([]byte(xml.Header), f.encodeWorksheet(name, ws, &buffer, xml.NewEncoder(&buffer))...)
+	} else if val, ok := f.Pkg.Load(name); ok && val != nil {
+		content = val.([]byte)
+	}
+	if content != nil {
+		tmp, err := os.CreateTemp(os.TempDir(), "excelize-")
+		if err != nil {
+			return false
+		}
+		_, err = tmp.Write(content)
+		if closeErr := tmp.Close(); err != nil || closeErr != nil {
+			_ = os.Remove(tmp.Name())
+			return false
+		}
+		if path, ok := f.tempFiles.Load(name); ok {
+			_ = os.Remove(path.(string))
+		}
+		f.tempFiles.Store(name, tmp.Name())
+		if part, ok := f.sourceParts.Load(name); ok && sameBytes(content, part.(*sourcePart).content) {
+			f.sourceParts.Store(name, &sourcePart{file: part.(*sourcePart).file, temp: tmp.Name()})
+		}
+		f.Pkg.Delete(name)
+	}
+	f.checked.Delete(name)
+	f.Sheet.Delete(name)
+	return true
+}
+
 // trimRow provides a function to trim empty rows.
 func trimRow(sheetData *xlsxSheetData) []xlsxRow {
 	var (
@@ -249,6 +450,9 @@ func (f *File) relsWriter() {
 	f.Relationships.Range(func(path, rel interface{}) bool {
 		if rel != nil {
 			output, _ := xml.Marshal(rel.(*xlsxRelationships))
+			if !f.partModified(path.(string), output) {
+				return true
+			}
 			if strings.HasPrefix(path.(string), "xl/worksheets/sheet/rels/sheet") {
 				output = f.replaceNameSpaceBytes(path.(string), output)
 			}
@@ -592,6 +796,10 @@ func (f *File) DeleteSheet(sheet string) error {
 		f.Pkg.Delete(rels)
 		f.Relationships.Delete(rels)
 		f.Sheet.Delete(sheetXML)
+		f.sheetPager.remove(sheetXML)
+		if path, ok := f.tempFiles.LoadAndDelete(sheetXML); ok {
+			_ = os.Remove(path.(string))
+		}
 		f.xmlAttr.Delete(sheetXML)
 		f.SheetCount--
 	}
@@ -1763,6 +1971,7 @@ func (f *File) GroupSheets(sheets []string) error {
 	if !inActiveSheet {
 		return ErrGroupSheets
 	}
+	defer f.pinWorksheets()()
 	// check worksheet exists
 	var wss []*xlsxWorksheet
 	for _, sheet := range sheets {
@@ -1930,6 +2139,7 @@ func (f *File) relsReader(path string) (*xlsxRelationships, error) {
 				Decode(&c); err != nil && err != io.EOF {
 				return nil, err
 			}
+			f.trackPart(path, &c)
 			f.Relationships.Store(path, &c)
 		}
 	}
@@ -0,0 +1,249 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Snapshot directly maps an immutable read-only view of the workbook, which
+// holds the cell values and the formula calculation results of all
+// worksheets at the time the snapshot was taken. The snapshot doesn't refer
+// to the workbook after it was created, so it's safe to read the snapshot
+// from multiple goroutines without locking, and the changes of the workbook
+// will not be reflected in the snapshot.
+type Snapshot struct {
+	list   []string
+	sheets map[string]*snapshotSheet
+}
+
+// snapshotSheet directly maps the formatted and raw cell values and the
+// formula calculation results of a worksheet in the snapshot.
+type snapshotSheet struct {
+	values    [][]string
+	rawValues [][]string
+	results   map[string]snapshotResult
+}
+
+// snapshotResult directly maps the formatted and raw formula calculation
+// result of a cell in the snapshot.
+type snapshotResult struct {
+	value    string
+	rawValue string
+	err      error
+}
+
+// Snapshot provides a function to create an immutable read-only view of the
+// workbook, the cell values of all worksheets are read, and the formulas of
+// all worksheets are calculated with the given options on creation, so the
+// GetCellValue, GetRows and CalcCellValue functions of the snapshot are
+// lock-free lookups which are safe for heavy concurrent use. The chart sheets,
+// dialog sheets and macro sheets are not included in the snapshot. Note that
+// the workbook should not be modified while the snapshot is being created.
+// For example, serve the cell values of a workbook to many goroutines:
+//
+//	snapshot, err := f.Snapshot()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	var wg sync.WaitGroup
+//	for i := 1; i <= 100; i++ {
+//	    wg.Add(1)
+//	    go func(row int) {
+//	        defer wg.Done()
+//	        value, err := snapshot.CalcCellValue("Sheet1", fmt.Sprintf("B%d", row))
+//	        if err != nil {
+//	            fmt.Println(err)
+//	            return
+//	        }
+//	        fmt.Println(value)
+//	    }(i)
+//	}
+//	wg.Wait()
+func (f *File) Snapshot(opts ...Options) (*Snapshot, error) {
+	options := f.getOptions(opts...)
+	s := &Snapshot{sheets: make(map[string]*snapshotSheet)}
+	for _, sheet := range f.GetSheetList() {
+		if name, _ := f.getSheetXMLPath(sheet); !strings.HasPrefix(name, "xl/worksheets/") {
+			continue
+		}
+		ss, err := f.snapshotSheet(sheet, options)
+		if err != nil {
+			return nil, err
+		}
+		s.list, s.sheets[sheet] = append(s.list, sheet), ss
+	}
+	return s, nil
+}
+
+// snapshotSheet provides a function to read the cell values and calculate the
+// formulas of the worksheet by given worksheet name and options for the
+// snapshot.
+func (f *File) snapshotSheet(sheet string, options *Options) (*snapshotSheet, error) {
+	var (
+		ss                  = &snapshotSheet{results: make(map[string]snapshotResult)}
+		formatOpts, rawOpts Options
+		err                 error
+	)
+	if options != nil {
+		formatOpts, rawOpts = *options, *options
+	}
+	formatOpts.RawCellValue, rawOpts.RawCellValue = false, true
+	if ss.values, err = f.GetRows(sheet, formatOpts); err != nil {
+		return nil, err
+	}
+	if ss.rawValues, err = f.GetRows(sheet, rawOpts); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	ws, err := f.workSheetReader(sheet)
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	var cells []string
+	ws.mu.Lock()
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			if c.F != nil {
+				cells = append(cells, c.R)
+			}
+		}
+	}
+	ws.mu.Unlock()
+	for _, cell := range cells {
+		var result snapshotResult
+		token, err := f.calcCellValue(newCalcContext(fmt.Sprintf("%s!%s", sheet, cell), options), sheet, cell)
+		if err != nil {
+			result.value, result.rawValue, result.err = token.String, token.String, err
+			ss.results[cell] = result
+			continue
+		}
+		if result.value, result.err = f.formatCalcResult(sheet, cell, token, false); result.err == nil {
+			result.rawValue, result.err = f.formatCalcResult(sheet, cell, token, true)
+		}
+		ss.results[cell] = result
+	}
+	return ss, nil
+}
+
+// sheet returns the worksheet in the snapshot by given worksheet name, the
+// worksheet name is case-insensitive.
+func (s *Snapshot) sheet(sheet string) (*snapshotSheet, error) {
+	if err := checkSheetName(sheet); err != nil {
+		return nil, err
+	}
+	if ss, ok := s.sheets[sheet]; ok {
+		return ss, nil
+	}
+	for name, ss := range s.sheets {
+		if strings.EqualFold(name, sheet) {
+			return ss, nil
+		}
+	}
+	return nil, ErrSheetNotExist{sheet}
+}
+
+// value returns the formatted or raw cell value by given column and row
+// number.
+func (ss *snapshotSheet) value(col, row int, raw bool) string {
+	values := ss.values
+	if raw {
+		values = ss.rawValues
+	}
+	if row > len(values) || col > len(values[row-1]) {
+		return ""
+	}
+	return values[row-1][col-1]
+}
+
+// GetSheetList provides a function to get the names of the worksheets in the
+// snapshot in the order of the workbook.
+func (s *Snapshot) GetSheetList() []string {
+	return append([]string(nil), s.list...)
+}
+
+// GetCellValue provides a function to get the formatted value of the cell
+// in the snapshot by given worksheet name and cell reference. The raw value
+// of the cell will be returned if the RawCellValue option was set. This
+// function is lock-free and concurrency safe.
+func (s *Snapshot) GetCellValue(sheet, cell string, opts ...Options) (string, error) {
+	ss, err := s.sheet(sheet)
+	if err != nil {
+		return "", err
+	}
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return "", err
+	}
+	return ss.value(col, row, getSnapshotOptions(opts...).RawCellValue), err
+}
+
+// GetRows returns all the rows of the worksheet in the snapshot by given
+// worksheet name, the same as the GetRows function of the workbook. The
+// returned slices are copies which can be modified by the caller. This
+// function is lock-free and concurrency safe.
+func (s *Snapshot) GetRows(sheet string, opts ...Options) ([][]string, error) {
+	ss, err := s.sheet(sheet)
+	if err != nil {
+		return nil, err
+	}
+	values := ss.values
+	if getSnapshotOptions(opts...).RawCellValue {
+		values = ss.rawValues
+	}
+	rows := make([][]string, len(values))
+	for i, row := range values {
+		rows[i] = append([]string(nil), row...)
+	}
+	return rows, err
+}
+
+// CalcCellValue provides a function to get the formula calculation result of
+// the cell in the snapshot by given worksheet name and cell reference. The
+// formulas were calculated when the snapshot was created, and the value of
+// the cell will be returned if the cell doesn't contain a formula. The raw
+// result will be returned if the RawCellValue option was set. This function
+// is lock-free and concurrency safe.
+func (s *Snapshot) CalcCellValue(sheet, cell string, opts ...Options) (string, error) {
+	ss, err := s.sheet(sheet)
+	if err != nil {
+		return "", err
+	}
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return "", err
+	}
+	raw := getSnapshotOptions(opts...).RawCellValue
+	if cell, err = CoordinatesToCellName(col, row); err != nil {
+		return "", err
+	}
+	if result, ok := ss.results[cell]; ok {
+		if raw {
+			return result.rawValue, result.err
+		}
+		return result.value, result.err
+	}
+	return ss.value(col, row, raw), err
+}
+
+// getSnapshotOptions provides a function to parse the optional settings for
+// reading the snapshot.
+func getSnapshotOptions(opts ...Options) Options {
+	var options Options
+	for _, opt := range opts {
+		options = opt
+	}
+	return options
+}
@@ -0,0 +1,125 @@
+package excelize
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	f := NewFile()
+	for row := 1; row <= 100; row++ {
+		cell, err := CoordinatesToCellName(1, row)
+		assert.NoError(t, err)
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, row))
+		assert.NoError(t, f.SetCellFormula("Sheet1", fmt.Sprintf("B%d", row), fmt.Sprintf("A%d*2", row)))
+	}
+	style, err := f.NewStyle(&Style{NumFmt: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "B1", style))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "1/0"))
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet2", "B2", "Hello"))
+	assert.NoError(t, f.AddChartSheet("Chart1", &Chart{
+		Type:   Col,
+		Series: []ChartSeries{{Name: "Sheet1!$A$1", Values: "Sheet1!$A$1:$A$3"}},
+	}))
+
+	snapshot, err := f.Snapshot()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Sheet2"}, snapshot.GetSheetList())
+
+	// Test the snapshot isn't affected by the changes of the workbook
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 10))
+	assert.NoError(t, f.SetCellValue("Sheet2", "B2", "World"))
+
+	var wg sync.WaitGroup
+	for row := 1; row <= 100; row++ {
+		wg.Add(1)
+		go func(row int) {
+			defer wg.Done()
+			value, err := snapshot.GetCellValue("Sheet1", fmt.Sprintf("A%d", row))
+			assert.NoError(t, err)
+			result, err := snapshot.CalcCellValue("Sheet1", fmt.Sprintf("B%d", row))
+			assert.NoError(t, err)
+			if row == 1 {
+				assert.Equal(t, "1.00", value)
+				assert.Equal(t, "2.00", result)
+				return
+			}
+			assert.Equal(t, fmt.Sprint(row), value)
+			assert.Equal(t, fmt.Sprint(row*2), result)
+			rows, err := snapshot.GetRows("Sheet1")
+			assert.NoError(t, err)
+			assert.Len(t, rows, 100)
+		}(row)
+	}
+	wg.Wait()
+
+	value, err := snapshot.GetCellValue("sheet1", "A1", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", value)
+	result, err := snapshot.CalcCellValue("Sheet1", "$B$1", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "2", result)
+	result, err = snapshot.CalcCellValue("Sheet1", "C1")
+	assert.EqualError(t, err, "#DIV/0!")
+	assert.Empty(t, result)
+	result, err = snapshot.CalcCellValue("Sheet2", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", result)
+	for _, cell := range []string{"A1", "C2", "Z100"} {
+		value, err = snapshot.GetCellValue("Sheet2", cell)
+		assert.NoError(t, err)
+		assert.Empty(t, value, cell)
+	}
+	rows, err := snapshot.GetRows("Sheet2", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{nil, {"", "Hello"}}, rows)
+	// Test the returned rows are copies of the snapshot
+	rows[1][1] = "World"
+	value, err = snapshot.GetCellValue("Sheet2", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", value)
+
+	// Test create snapshot with the raw cell value options
+	f.options.RawCellValue = true
+	for _, opts := range [][]Options{nil, {{RawCellValue: true}}} {
+		snapshot, err := f.Snapshot(opts...)
+		assert.NoError(t, err)
+		value, err = snapshot.GetCellValue("Sheet1", "A1")
+		assert.NoError(t, err)
+		assert.Equal(t, "10.00", value)
+		value, err = snapshot.GetCellValue("Sheet1", "A1", Options{RawCellValue: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "10", value)
+	}
+	f.options.RawCellValue = false
+
+	// Test read the snapshot with invalid arguments
+	_, err = snapshot.GetCellValue("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	_, err = snapshot.GetCellValue("Chart1", "A1")
+	assert.EqualError(t, err, "sheet Chart1 does not exist")
+	_, err = snapshot.GetRows("Sheet:1")
+	assert.Equal(t, ErrSheetNameInvalid, err)
+	_, err = snapshot.CalcCellValue("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	_, err = snapshot.GetCellValue("Sheet1", "A")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	_, err = snapshot.CalcCellValue("Sheet1", "A")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	assert.NoError(t, f.Close())
+
+	// Test create snapshot with unsupported charset
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked = sync.Map{}
+	_, err = f.Snapshot()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
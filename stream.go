@@ -12,6 +12,7 @@
 package excelize
 
 import (
+	"archive/zip"
 	"bytes"
 	"encoding/xml"
 	"fmt"
@@ -29,25 +30,66 @@ type StreamWriter struct {
 	Sheet           string
 	SheetID         int
 	sheetWritten    bool
-	cols            strings.Builder
 	worksheet       *xlsxWorksheet
 	rawData         bufferedWriter
 	rows            int
+	flushedRows     int
+	lastRow         []xlsxC
 	mergeCellsCount int
 	mergeCells      strings.Builder
-	tableParts      string
+	hyperlinks      map[string]int
+}
+
+// StreamOptions defines the options for the stream writer.
+//
+// Writer specifies the output of the workbook. When it's set, the worksheet
+// will be compressed into the output directly as the rows arrive instead of
+// using temporary files, and the other parts of the workbook will be written
+// into the output when the workbook is closed by the 'Close' function. Only
+// one of these stream writers can be used at a time, and you must call the
+// 'Flush' function before create the next one on the same output. Note that
+// the workbook can't be saved by 'Save', 'SaveAs', 'Write', 'WriteTo' or
+// 'WriteToBuffer' in this mode, these functions will return an error.
+//
+// BufferSize specifies the maximum size in bytes of the in-memory buffer of
+// the stream writer, the default value is 16 MiB. Data over the size will be
+// written to the output or the temporary file.
+//
+// Append specifies if keep the existing rows and merged cells of the
+// worksheet, the new rows must be streamed after the last existing row when
+// this option was enabled. The existing rows of the worksheet will be
+// overwritten by default.
+type StreamOptions struct {
+	Writer     io.Writer
+	BufferSize int
+	Append     bool
+}
+
+// streamZip directly maps the zip archive which the stream writers write the
+// worksheets into the output directly.
+type streamZip struct {
+	out io.Writer
+	zw  *zip.Writer
+	sw  *StreamWriter
 }
 
 // NewStreamWriter returns stream writer struct by given worksheet name used for
-// writing data on a new existing empty worksheet with large amounts of data.
-// Note that after writing data with the stream writer for the worksheet, you
-// must call the 'Flush' method to end the streaming writing process, ensure
-// that the order of row numbers is ascending when set rows, and the normal
-// mode functions and stream mode functions can not be work mixed to writing
-// data on the worksheets. The stream writer will try to use temporary files on
-// disk to reduce the memory usage when in-memory chunks data over 16MB, and
-// you can't get cell value at this time. For example, set data for worksheet
-// of size 102400 rows x 50 columns with numbers and style:
+// writing data on a worksheet with large amounts of data. The existing rows
+// of the worksheet will be overwritten, unless the Append option was set, and
+// the columns, conditional formats and drawings of the worksheet are kept.
+// Note that after writing
+// data with the stream writer for the worksheet, you must call the 'Flush'
+// method to end the streaming writing process, ensure that the order of row
+// numbers is ascending when set rows, and the normal mode functions can not be
+// used to change the worksheet after the stream writer has been created. The
+// stream writer will try to use temporary files on disk to reduce the memory
+// usage when in-memory chunks data over 16MB, and you can't get cell value at
+// this time. For example, set data for worksheet of size 102400 rows x 50
+// columns with numbers and style:
+//
+// The stream writers of different worksheets can be used concurrently in
+// separate goroutines, and the finished worksheets will be compressed in
+// parallel when saving the workbook.
 //
 //	f := excelize.NewFile()
 //	defer func() {
@@ -112,12 +154,53 @@ type StreamWriter struct {
 //	err := sw.SetRow("A1", []interface{}{
 //	    excelize.Cell{Value: 1}},
 //	    excelize.RowOpts{StyleID: styleID, Height: 20, Hidden: false});
-func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
+//
+// Compress the worksheet into the output directly without temporary files:
+//
+//	f := excelize.NewFile()
+//	sw, err := f.NewStreamWriter("Sheet1", excelize.StreamOptions{Writer: w})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := sw.SetRow("A1", []interface{}{"Data"}); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := sw.Flush(); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := f.Close(); err != nil {
+//	    fmt.Println(err)
+//	}
+//
+// Append rows after the existing rows of a worksheet in a template:
+//
+//	f, err := excelize.OpenFile("Template.xlsx")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	sw, err := f.NewStreamWriter("Sheet1", excelize.StreamOptions{Append: true})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	cell, err := excelize.CoordinatesToCellName(1, sw.LastRow()+1)
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = sw.SetRow(cell, []interface{}{"Data"})
+func (f *File) NewStreamWriter(sheet string, opts ...StreamOptions) (*StreamWriter, error) {
 	if err := checkSheetName(sheet); err != nil {
 		return nil, err
 	}
+	f.mu.Lock()
 	sheetID := f.getSheetID(sheet)
 	if sheetID == -1 {
+		f.mu.Unlock()
 		return nil, ErrSheetNotExist{sheet}
 	}
 	sw := &StreamWriter{
@@ -127,21 +210,54 @@ func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
 	}
 	var err error
 	sw.worksheet, err = f.workSheetReader(sheet)
+	f.mu.Unlock()
 	if err != nil {
 		return nil, err
 	}
 
+	var appendRows bool
 	sheetXMLPath, _ := f.getSheetXMLPath(sheet)
-	if f.streams == nil {
-		f.streams = make(map[string]*StreamWriter)
+	for _, opt := range opts {
+		sw.rawData.size, appendRows = opt.BufferSize, opt.Append
+		if opt.Writer != nil {
+			if sw.rawData.out, err = f.createStreamZipPart(sheetXMLPath, opt.Writer, sw); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if appendRows {
+		sw.appendExisting()
+	} else {
+		sw.worksheet.SheetData.Row = nil
 	}
-	f.streams[sheetXMLPath] = sw
+	f.streams.Store(sheetXMLPath, sw)
 
 	_, _ = sw.rawData.WriteString(xml.Header + `<worksheet` + templateNamespaceIDMap)
 	bulkAppendFields(&sw.rawData, sw.worksheet, 2, 3)
 	return sw, err
 }
 
+// appendExisting provides a function to keep the existing rows and merged
+// cells of the worksheet for appending rows after the last existing row.
+func (sw *StreamWriter) appendExisting() {
+	sw.worksheet.SheetData.Row = trimRow(&sw.worksheet.SheetData)
+	if rows := sw.worksheet.SheetData.Row; len(rows) > 0 {
+		sw.rows = rows[len(rows)-1].R
+	}
+	if sw.worksheet.MergeCells == nil {
+		return
+	}
+	for _, mergeCell := range sw.worksheet.MergeCells.Cells {
+		if mergeCell == nil {
+			continue
+		}
+		sw.mergeCellsCount++
+		_, _ = sw.mergeCells.WriteString(`<mergeCell ref="`)
+		_, _ = sw.mergeCells.WriteString(mergeCell.Ref)
+		_, _ = sw.mergeCells.WriteString(`"/>`)
+	}
+}
+
 // AddTable creates an Excel table for the StreamWriter using the given
 // cell range and format set. For example, create a table of A1:D5:
 //
@@ -163,8 +279,10 @@ func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
 // Note that the table must be at least two lines including the header. The
 // header cells must contain strings and must be unique.
 //
-// Currently, only one table is allowed for a StreamWriter. AddTable must be
-// called after the rows are written but before Flush.
+// AddTable must be called after the rows are written but before Flush. If the
+// stream writer writes to the output directly, AddTable must be called before
+// the header row has been flushed to the output, or right after the header row
+// is written.
 //
 // See File.AddTable for details on the table format.
 func (sw *StreamWriter) AddTable(table *Table) error {
@@ -190,6 +308,9 @@ func (sw *StreamWriter) AddTable(table *Table) error {
 	}
 
 	// create table columns using the first row
+	sw.writeSheetData()
+	sw.file.streamMu.Lock()
+	defer sw.file.streamMu.Unlock()
 	tableHeaders, err := sw.getRowValues(coordinates[1], coordinates[0], coordinates[2])
 	if err != nil {
 		return err
@@ -239,7 +360,13 @@ func (sw *StreamWriter) AddTable(table *Table) error {
 	sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(sheetPath, "xl/worksheets/") + ".rels"
 	rID := sw.file.addRels(sheetRels, SourceRelationshipTable, sheetRelationshipsTableXML, "")
 
-	sw.tableParts = fmt.Sprintf(`<tableParts count="1"><tablePart r:id="rId%d"></tablePart></tableParts>`, rID)
+	if sw.worksheet.TableParts == nil {
+		sw.worksheet.TableParts = &xlsxTableParts{}
+	}
+	sw.worksheet.TableParts.Count++
+	sw.worksheet.TableParts.TableParts = append(sw.worksheet.TableParts.TableParts, &xlsxTablePart{
+		RID: "rId" + strconv.Itoa(rID),
+	})
 
 	if err = sw.file.addContentTypePart(tableID, "table"); err != nil {
 		return err
@@ -252,7 +379,18 @@ func (sw *StreamWriter) AddTable(table *Table) error {
 // Extract values from a row in the StreamWriter.
 func (sw *StreamWriter) getRowValues(hRow, hCol, vCol int) (res []string, err error) {
 	res = make([]string, vCol-hCol+1)
-
+	sst, err := sw.file.sharedStringsReader()
+	if err != nil {
+		return nil, err
+	}
+	// The rows have been written to the output can't be read back, except the
+	// last row which cells are kept by the stream writer
+	if sw.rawData.out != nil && hRow <= sw.flushedRows {
+		if hRow != sw.rows {
+			return nil, ErrStreamTableHeader
+		}
+		return res, sw.setRowValues(res, sw.lastRow, sst, hCol, vCol)
+	}
 	r, err := sw.rawData.Reader()
 	if err != nil {
 		return nil, err
@@ -276,18 +414,24 @@ func (sw *StreamWriter) getRowValues(hRow, hCol, vCol int) (res []string, err er
 		if err := dec.DecodeElement(&row, &startElement); err != nil {
 			return nil, err
 		}
-		for _, c := range row.C {
-			col, _, err := CellNameToCoordinates(c.R)
-			if err != nil {
-				return nil, err
-			}
-			if col < hCol || col > vCol {
-				continue
-			}
-			res[col-hCol], _ = c.getValueFrom(sw.file, nil, false)
+		return res, sw.setRowValues(res, row.C, sst, hCol, vCol)
+	}
+}
+
+// setRowValues provides a function to set the values of the cells in the given
+// columns range into the slice.
+func (sw *StreamWriter) setRowValues(res []string, cells []xlsxC, sst *xlsxSST, hCol, vCol int) error {
+	for _, c := range cells {
+		col, _, err := CellNameToCoordinates(c.R)
+		if err != nil {
+			return err
 		}
-		return res, nil
+		if col < hCol || col > vCol {
+			continue
+		}
+		res[col-hCol], _ = c.getValueFrom(sw.file, sst, false)
 	}
+	return nil
 }
 
 // Check if the token is an XLSX row with the matching row number.
@@ -405,6 +549,7 @@ func (sw *StreamWriter) SetRow(cell string, values []interface{}, opts ...RowOpt
 	_, _ = sw.rawData.WriteString(`"`)
 	_, _ = sw.rawData.WriteString(attrs.String())
 	_, _ = sw.rawData.WriteString(`>`)
+	sw.lastRow = sw.lastRow[:0]
 	for i, val := range values {
 		if val == nil {
 			continue
@@ -428,9 +573,18 @@ func (sw *StreamWriter) SetRow(cell string, values []interface{}, opts ...RowOpt
 			return err
 		}
 		writeCell(&sw.rawData, c)
+		if sw.rawData.out != nil {
+			sw.lastRow = append(sw.lastRow, c)
+		}
 	}
 	_, _ = sw.rawData.WriteString(`</row>`)
-	return sw.rawData.Sync()
+	if err = sw.rawData.Sync(); err != nil {
+		return err
+	}
+	if sw.rawData.out != nil && sw.rawData.buf.Len() == 0 {
+		sw.flushedRows = sw.rows
+	}
+	return err
 }
 
 // SetColWidth provides a function to set the width of a single column or
@@ -452,14 +606,7 @@ func (sw *StreamWriter) SetColWidth(minVal, maxVal int, width float64) error {
 	if minVal > maxVal {
 		minVal, maxVal = maxVal, minVal
 	}
-
-	sw.cols.WriteString(`<col min="`)
-	sw.cols.WriteString(strconv.Itoa(minVal))
-	sw.cols.WriteString(`" max="`)
-	sw.cols.WriteString(strconv.Itoa(maxVal))
-	sw.cols.WriteString(`" width="`)
-	sw.cols.WriteString(strconv.FormatFloat(width, 'f', -1, 64))
-	sw.cols.WriteString(`" customWidth="1"/>`)
+	sw.worksheet.setColWidth(minVal, maxVal, width)
 	return nil
 }
 
@@ -498,6 +645,117 @@ func (sw *StreamWriter) MergeCell(topLeftCell, bottomRightCell string) error {
 	return nil
 }
 
+// AddComment provides the method to add comments in the worksheet for the
+// StreamWriter by giving the cell reference, and format set (such as author
+// and text). Note that you must call the 'AddComment' function before the
+// 'Flush' function. For example, add a comment in Sheet1!A5:
+//
+//	err := sw.AddComment(excelize.Comment{
+//	    Cell:   "A5",
+//	    Author: "Excelize",
+//	    Text:   "This is a comment.",
+//	})
+//
+// See File.AddComment for details on the comment format.
+func (sw *StreamWriter) AddComment(opts Comment) error {
+	sw.file.streamMu.Lock()
+	defer sw.file.streamMu.Unlock()
+	return sw.file.AddComment(sw.Sheet, opts)
+}
+
+// SetCellHyperLink provides a function to set cell hyperlink for the
+// StreamWriter by given cell reference and link URL address. LinkType defines
+// two types of hyperlink "External" for website or "Location" for moving to
+// one of cell in this workbook. Maximum limit hyperlinks in a worksheet is
+// 65530. This function is only used to set the hyperlink of the cell and
+// doesn't affect the value of the cell. Note that you must call the
+// 'SetCellHyperLink' function before the 'Flush' function. For example:
+//
+//	display, tooltip := "https://github.com/xuri/excelize", "Excelize on GitHub"
+//	err := sw.SetCellHyperLink("A3", display, "External", excelize.HyperlinkOpts{
+//	    Display: &display,
+//	    Tooltip: &tooltip,
+//	})
+func (sw *StreamWriter) SetCellHyperLink(cell, link, linkType string, opts ...HyperlinkOpts) error {
+	if _, _, err := SplitCellName(cell); err != nil {
+		return err
+	}
+	if sw.worksheet.Hyperlinks == nil {
+		sw.worksheet.Hyperlinks = new(xlsxHyperlinks)
+	}
+	if sw.hyperlinks == nil {
+		sw.hyperlinks = make(map[string]int)
+		for i, hyperlink := range sw.worksheet.Hyperlinks.Hyperlink {
+			sw.hyperlinks[hyperlink.Ref] = i
+		}
+	}
+	var rID string
+	idx, ok := sw.hyperlinks[cell]
+	if ok {
+		rID = sw.worksheet.Hyperlinks.Hyperlink[idx].RID
+	}
+	if !ok && len(sw.worksheet.Hyperlinks.Hyperlink) > TotalSheetHyperlinks {
+		return ErrTotalSheetHyperlinks
+	}
+	sw.file.streamMu.Lock()
+	linkData, err := sw.file.prepareHyperlink(sw.Sheet, cell, link, linkType, rID, opts...)
+	sw.file.streamMu.Unlock()
+	if err != nil {
+		return err
+	}
+	if ok {
+		sw.worksheet.Hyperlinks.Hyperlink[idx] = linkData
+		return err
+	}
+	sw.hyperlinks[cell] = len(sw.worksheet.Hyperlinks.Hyperlink)
+	sw.worksheet.Hyperlinks.Hyperlink = append(sw.worksheet.Hyperlinks.Hyperlink, linkData)
+	return err
+}
+
+// AddDataValidation provides a function to set data validation on a range of
+// the worksheet for the StreamWriter. Note that you must call the
+// 'AddDataValidation' function before the 'Flush' function. For example, set
+// data validation on A1:A1048576 with a drop list:
+//
+//	dv := excelize.NewDataValidation(true)
+//	dv.Sqref = "A1:A1048576"
+//	if err := dv.SetDropList([]string{"1", "2", "3"}); err != nil {
+//	    fmt.Println(err)
+//	}
+//	err := sw.AddDataValidation(dv)
+//
+// See File.AddDataValidation for details on the data validation settings.
+func (sw *StreamWriter) AddDataValidation(dv *DataValidation) error {
+	sw.file.streamMu.Lock()
+	defer sw.file.streamMu.Unlock()
+	return sw.file.AddDataValidation(sw.Sheet, dv)
+}
+
+// SetConditionalFormat provides a function to create conditional formatting
+// rule for cell value for the StreamWriter. Note that you must call the
+// 'SetConditionalFormat' function before the 'Flush' function. For example,
+// highlight cells greater than 6000 in the range A1:A1048576:
+//
+//	format, err := f.NewConditionalStyle(&excelize.Style{
+//	    Font: &excelize.Font{Color: "9A0511"},
+//	})
+//	if err != nil {
+//	    fmt.Println(err)
+//	}
+//	err = sw.SetConditionalFormat("A1:A1048576",
+//	    []excelize.ConditionalFormatOptions{
+//	        {Type: "cell", Criteria: ">", Format: &format, Value: "6000"},
+//	    },
+//	)
+//
+// See File.SetConditionalFormat for details on the conditional format
+// settings.
+func (sw *StreamWriter) SetConditionalFormat(rangeRef string, opts []ConditionalFormatOptions) error {
+	sw.file.streamMu.Lock()
+	defer sw.file.streamMu.Unlock()
+	return sw.file.SetConditionalFormat(sw.Sheet, rangeRef, opts)
+}
+
 // setCellFormula provides a function to set formula of a cell.
 func setCellFormula(c *xlsxC, formula string) {
 	if formula != "" {
@@ -508,7 +766,9 @@ func setCellFormula(c *xlsxC, formula string) {
 // setCellTime provides a function to set number of a cell with a time.
 func (sw *StreamWriter) setCellTime(c *xlsxC, val time.Time) error {
 	var date1904, isNum bool
+	sw.file.mu.Lock()
 	wb, err := sw.file.workbookReader()
+	sw.file.mu.Unlock()
 	if err != nil {
 		return err
 	}
@@ -533,9 +793,9 @@ func (sw *StreamWriter) setCellValFunc(c *xlsxC, val interface{}) error {
 	case float64:
 		c.T, c.V = setCellFloat(val, -1, 64)
 	case string:
-		c.setCellValue(val)
+		err = sw.setCellStr(c, val)
 	case []byte:
-		c.setCellValue(string(val))
+		err = sw.setCellStr(c, string(val))
 	case time.Duration:
 		c.T, c.V = setCellDuration(val)
 	case time.Time:
@@ -548,11 +808,25 @@ func (sw *StreamWriter) setCellValFunc(c *xlsxC, val interface{}) error {
 		c.T, c.IS = "inlineStr", &xlsxSI{}
 		c.IS.R, err = setRichText(val)
 	default:
-		c.setCellValue(fmt.Sprint(val))
+		err = sw.setCellStr(c, fmt.Sprint(val))
 	}
 	return err
 }
 
+// setCellStr provides a function to set string type value of a cell by the
+// string storage policy.
+func (sw *StreamWriter) setCellStr(c *xlsxC, val string) error {
+	if policy, _ := sw.file.getStringStorage(); c.F == nil && policy == StringStorageShared {
+		si, ok, err := sw.file.setSharedString(val)
+		if err != nil || ok {
+			c.T, c.V = "s", strconv.Itoa(si)
+			return err
+		}
+	}
+	c.setCellValue(val)
+	return nil
+}
+
 // setCellIntFunc is a wrapper of SetCellInt.
 func setCellIntFunc(c *xlsxC, val interface{}) (err error) {
 	switch val := val.(type) {
@@ -639,21 +913,33 @@ func writeCell(buf *bufferedWriter, c xlsxC) {
 	_, _ = buf.WriteString(`</c>`)
 }
 
-// writeSheetData prepares the element preceding sheetData and writes the
-// sheetData XML start element to the buffer.
+// writeSheetData prepares the element preceding sheetData, writes the
+// sheetData XML start element and the existing rows of the worksheet to the
+// buffer.
 func (sw *StreamWriter) writeSheetData() {
 	if !sw.sheetWritten {
-		bulkAppendFields(&sw.rawData, sw.worksheet, 4, 5)
-		if sw.cols.Len() > 0 {
-			_, _ = sw.rawData.WriteString("<cols>")
-			_, _ = sw.rawData.WriteString(sw.cols.String())
-			_, _ = sw.rawData.WriteString("</cols>")
+		if sw.worksheet.Cols != nil && len(sw.worksheet.Cols.Col) > 0 {
+			sw.file.mergeExpandedCols(sw.worksheet)
 		}
+		bulkAppendFields(&sw.rawData, sw.worksheet, 4, 6)
 		_, _ = sw.rawData.WriteString(`<sheetData>`)
+		enc := xml.NewEncoder(&sw.rawData)
+		for _, row := range sw.worksheet.SheetData.Row {
+			_ = enc.EncodeElement(row, xml.StartElement{Name: xml.Name{Local: "row"}})
+		}
+		sw.worksheet.SheetData.Row = nil
 		sw.sheetWritten = true
 	}
 }
 
+// LastRow returns the row number of the last row which has been written to
+// the worksheet, including the existing rows of the worksheet before the
+// stream writer was created with the Append option. It returns 0 if no row
+// has been written.
+func (sw *StreamWriter) LastRow() int {
+	return sw.rows
+}
+
 // Flush ending the streaming writing process.
 func (sw *StreamWriter) Flush() error {
 	sw.writeSheetData()
@@ -669,8 +955,7 @@ func (sw *StreamWriter) Flush() error {
 	}
 	_, _ = sw.rawData.WriteString(mergeCells.String())
 	bulkAppendFields(&sw.rawData, sw.worksheet, 17, 38)
-	_, _ = sw.rawData.WriteString(sw.tableParts)
-	bulkAppendFields(&sw.rawData, sw.worksheet, 40, 40)
+	bulkAppendFields(&sw.rawData, sw.worksheet, 40, 41)
 	_, _ = sw.rawData.WriteString(`</worksheet>`)
 	if err := sw.rawData.Flush(); err != nil {
 		return err
@@ -680,29 +965,104 @@ func (sw *StreamWriter) Flush() error {
 	sw.file.Sheet.Delete(sheetPath)
 	sw.file.checked.Delete(sheetPath)
 	sw.file.Pkg.Delete(sheetPath)
-
+	sw.file.sheetPager.remove(sheetPath)
+	if path, ok := sw.file.tempFiles.LoadAndDelete(sheetPath); ok {
+		_ = os.Remove(path.(string))
+	}
+	if sw.rawData.out != nil {
+		sw.file.streamMu.Lock()
+		sw.file.streamZip.sw = nil
+		sw.file.streamMu.Unlock()
+	}
 	return nil
 }
 
+// discard provides a function to discard the stream writer which hasn't been
+// flushed successfully and clean up the temporary file, the rows written by
+// the stream writer will not be saved.
+func (sw *StreamWriter) discard() {
+	sheetPath, _ := sw.file.getSheetXMLPath(sw.Sheet)
+	sw.file.streams.Delete(sheetPath)
+	_ = sw.rawData.Close()
+}
+
+// createStreamZipPart provides a function to create the zip archive on the
+// output by given writer if not exists, and create the worksheet part in the
+// zip archive for the stream writer.
+func (f *File) createStreamZipPart(path string, w io.Writer, sw *StreamWriter) (io.Writer, error) {
+	f.streamMu.Lock()
+	defer f.streamMu.Unlock()
+	if f.streamZip == nil {
+		f.streamZip = &streamZip{out: w, zw: zip.NewWriter(w)}
+	}
+	if f.streamZip.out != w || f.streamZip.sw != nil {
+		return nil, ErrStreamOutput
+	}
+	fi, err := f.streamZip.zw.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	f.streamZip.sw = sw
+	return fi, err
+}
+
+// isStreamZip returns if the stream writers write the workbook to the output
+// directly.
+func (f *File) isStreamZip() bool {
+	f.streamMu.Lock()
+	defer f.streamMu.Unlock()
+	return f.streamZip != nil
+}
+
+// closeStreamZip provides a function to flush the stream writer which writes
+// to the output directly, write the other parts of the workbook and close the
+// zip archive on the output.
+func (f *File) closeStreamZip() error {
+	if f.streamZip == nil {
+		return nil
+	}
+	zw := f.streamZip.zw
+	if sw := f.streamZip.sw; sw != nil {
+		if err := sw.Flush(); err != nil {
+			return err
+		}
+	}
+	f.streamZip = nil
+	if err := f.writeToZip(zw); err != nil {
+		_ = zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
 // bulkAppendFields bulk-appends fields in a worksheet by specified field
 // names order range.
 func bulkAppendFields(w io.Writer, ws *xlsxWorksheet, from, to int) {
+	var buf bytes.Buffer
 	s := reflect.ValueOf(ws).Elem()
-	enc := xml.NewEncoder(w)
+	enc := xml.NewEncoder(&buf)
 	for i := 0; i < s.NumField(); i++ {
 		if from <= i && i <= to {
-			_ = enc.Encode(s.Field(i).Interface())
+			var name xml.Name
+			name.Local = strings.Split(s.Type().Field(i).Tag.Get("xml"), ",")[0]
+			if idx := strings.LastIndex(name.Local, " "); idx != -1 {
+				name.Space, name.Local = name.Local[:idx], name.Local[idx+1:]
+			}
+			_ = enc.EncodeElement(s.Field(i).Interface(), xml.StartElement{Name: name})
 		}
 	}
+	_, _ = w.Write(replaceRelationshipsBytes(buf.Bytes()))
 }
 
 // bufferedWriter uses a temp file to store an extended buffer. Writes are
 // always made to an in-memory buffer, which will always succeed. The buffer
-// is written to the temp file with Sync, which may return an error.
-// Therefore, Sync should be periodically called and the error checked.
+// is written to the temp file or the output with Sync, which may return an
+// error. Therefore, Sync should be periodically called and the error checked.
 type bufferedWriter struct {
-	tmp *os.File
-	buf bytes.Buffer
+	tmp  *os.File
+	out  io.Writer
+	size int
+	buf  bytes.Buffer
 }
 
 // Write to the in-memory buffer. The error is always nil.
@@ -731,14 +1091,18 @@ func (bw *bufferedWriter) Reader() (io.Reader, error) {
 	return io.NewSectionReader(bw.tmp, 0, fi.Size()), nil
 }
 
-// Sync will write the in-memory buffer to a temp file, if the in-memory
-// buffer has grown large enough. Any error will be returned.
+// Sync will write the in-memory buffer to a temp file or the output, if the
+// in-memory buffer has grown large enough. Any error will be returned.
 func (bw *bufferedWriter) Sync() (err error) {
-	// Try to use local storage
-	if bw.buf.Len() < StreamChunkSize {
+	size := StreamChunkSize
+	if bw.size > 0 {
+		size = bw.size
+	}
+	if bw.buf.Len() < size {
 		return nil
 	}
-	if bw.tmp == nil {
+	// Try to use local storage
+	if bw.out == nil && bw.tmp == nil {
 		bw.tmp, err = os.CreateTemp(os.TempDir(), "excelize-")
 		if err != nil {
 			// can not use local storage
@@ -748,9 +1112,13 @@ func (bw *bufferedWriter) Sync() (err error) {
 	return bw.Flush()
 }
 
-// Flush the entire in-memory buffer to the temp file, if a temp file is being
-// used.
+// Flush the entire in-memory buffer to the temp file or the output, if a temp
+// file or the output is being used.
 func (bw *bufferedWriter) Flush() error {
+	if bw.out != nil {
+		_, err := bw.buf.WriteTo(bw.out)
+		return err
+	}
 	if bw.tmp == nil {
 		return nil
 	}
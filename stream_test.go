@@ -1,12 +1,14 @@
 package excelize
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -273,6 +275,313 @@ func TestNewStreamWriter(t *testing.T) {
 	assert.Equal(t, ErrSheetNameInvalid, err)
 }
 
+func TestStreamWriterAppend(t *testing.T) {
+	f := NewFile()
+	style, err := f.NewStyle(&Style{Font: &Font{Bold: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Report"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Name", "Value"}))
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "B2", style))
+	assert.NoError(t, f.MergeCell("Sheet1", "A1", "B1"))
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "B", 20))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "B3:B10", []ConditionalFormatOptions{
+		{Type: "data_bar", Criteria: "=", MinType: "min", MaxType: "max", BarColor: "638EC6", BarSolid: true},
+	}))
+	assert.NoError(t, f.AddPicture("Sheet1", "D1", filepath.Join("test", "images", "excel.png"), nil))
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestStreamWriterAppend.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestStreamWriterAppend.xlsx"))
+	assert.NoError(t, err)
+	sw, err := f.NewStreamWriter("Sheet1", StreamOptions{Append: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sw.LastRow())
+	// Test append row before the last existing row
+	assert.Equal(t, newStreamSetRowError(2), sw.SetRow("A2", []interface{}{"Apple", 1}))
+	assert.NoError(t, sw.SetColWidth(3, 3, 10))
+	assert.NoError(t, sw.MergeCell("A5", "B5"))
+	for r := 3; r <= 5; r++ {
+		assert.NoError(t, sw.SetRow(fmt.Sprintf("A%d", r), []interface{}{"Apple", r}))
+	}
+	assert.Equal(t, 5, sw.LastRow())
+	assert.NoError(t, sw.AddTable(&Table{Range: "A2:B5"}))
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestStreamWriterAppend.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestStreamWriterAppend.xlsx"))
+	assert.NoError(t, err)
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Report"}, {"Name", "Value"}, {"Apple", "3"}, {"Apple", "4"}, {"Apple", "5"},
+	}, rows)
+	styleID, err := f.GetCellStyle("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 2)
+	assert.Equal(t, "B1", mergeCells[0].GetEndAxis())
+	assert.Equal(t, "A5", mergeCells[1].GetStartAxis())
+	for col, expected := range map[string]float64{"A": 20, "B": 20, "C": 10} {
+		width, err := f.GetColWidth("Sheet1", col)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, width)
+	}
+	conditionalFormats, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, conditionalFormats["B3:B10"], 1)
+	assert.True(t, conditionalFormats["B3:B10"][0].BarSolid)
+	pics, err := f.GetPictures("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Len(t, pics, 1)
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, tables, 1)
+	assert.Equal(t, "A2:B5", tables[0].Range)
+
+	// Test overwrite the existing rows of the worksheet by default
+	sw, err = f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sw.LastRow())
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"Banana", 1}))
+	assert.NoError(t, sw.Flush())
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Banana", "1"}}, rows)
+	mergeCells, err = f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, mergeCells)
+	assert.NoError(t, f.Close())
+}
+
+func TestStreamWorksheetObjects(t *testing.T) {
+	f := NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	for r := 1; r <= 3; r++ {
+		assert.NoError(t, sw.SetRow(fmt.Sprintf("A%d", r), []interface{}{"https://github.com/xuri/excelize", r}))
+	}
+	assert.NoError(t, sw.AddComment(Comment{Cell: "A1", Author: "Excelize", Text: "This is a comment."}))
+	display, tooltip := "Excelize", "Excelize on GitHub"
+	assert.NoError(t, sw.SetCellHyperLink("A1", "https://github.com/xuri", "External"))
+	assert.NoError(t, sw.SetCellHyperLink("A1", "https://github.com/xuri/excelize", "External", HyperlinkOpts{Display: &display, Tooltip: &tooltip}))
+	assert.NoError(t, sw.SetCellHyperLink("A2", "Sheet1!B3", "Location"))
+	dv := NewDataValidation(true)
+	dv.Sqref = "B1:B3"
+	assert.NoError(t, dv.SetDropList([]string{"1", "2", "3"}))
+	assert.NoError(t, sw.AddDataValidation(dv))
+	format, err := f.NewConditionalStyle(&Style{Font: &Font{Color: "9A0511"}})
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetConditionalFormat("B1:B3", []ConditionalFormatOptions{
+		{Type: "cell", Criteria: ">", Format: &format, Value: "2"},
+	}))
+	// Test set cell hyperlink with invalid cell reference and link type
+	assert.Equal(t, newInvalidCellNameError("A"), sw.SetCellHyperLink("A", "Sheet1!B3", "Location"))
+	assert.Equal(t, newInvalidLinkTypeError(""), sw.SetCellHyperLink("A3", "Sheet1!B3", ""))
+	// Test set conditional format with invalid range reference
+	assert.Equal(t, ErrParameterRequired, sw.SetConditionalFormat("", nil))
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestStreamWorksheetObjects.xlsx")))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenFile(filepath.Join("test", "TestStreamWorksheetObjects.xlsx"))
+	assert.NoError(t, err)
+	comments, err := f.GetComments("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "This is a comment.", comments[0].Text)
+	ok, target, err := f.GetCellHyperLink("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "https://github.com/xuri/excelize", target)
+	ok, target, err = f.GetCellHyperLink("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Sheet1!B3", target)
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 1)
+	assert.Equal(t, "B1:B3", dvs[0].Sqref)
+	conditionalFormats, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, conditionalFormats["B1:B3"], 1)
+	assert.NoError(t, f.Close())
+
+	// Test set cell hyperlink on the worksheet with existing hyperlinks
+	f = NewFile()
+	assert.NoError(t, f.SetCellHyperLink("Sheet1", "A1", "Sheet1!B3", "Location"))
+	sw, err = f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetCellHyperLink("A1", "Sheet1!C3", "Location"))
+	assert.Len(t, sw.worksheet.Hyperlinks.Hyperlink, 1)
+	assert.Equal(t, "Sheet1!C3", sw.worksheet.Hyperlinks.Hyperlink[0].Location)
+	// Test set cell hyperlink exceeds maximum limit
+	sw.worksheet.Hyperlinks.Hyperlink = make([]xlsxHyperlink, TotalSheetHyperlinks+1)
+	assert.Equal(t, ErrTotalSheetHyperlinks, sw.SetCellHyperLink("A2", "Sheet1!C3", "Location"))
+	assert.NoError(t, f.Close())
+}
+
+func TestStreamWriterConcurrency(t *testing.T) {
+	f := NewFile()
+	sheets := []string{"Sheet1", "Sheet2", "Sheet3", "Sheet4"}
+	for _, sheet := range sheets[1:] {
+		_, err := f.NewSheet(sheet)
+		assert.NoError(t, err)
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, len(sheets))
+	for i, sheet := range sheets {
+		wg.Add(1)
+		go func(i int, sheet string) {
+			defer wg.Done()
+			errs[i] = func() error {
+				sw, err := f.NewStreamWriter(sheet)
+				if err != nil {
+					return err
+				}
+				if err = sw.SetRow("A1", []interface{}{"Name", "Date"}); err != nil {
+					return err
+				}
+				for r := 2; r <= 1000; r++ {
+					row := []interface{}{fmt.Sprintf("%s-%d", sheet, r), time.Date(2024, 1, r%28+1, 0, 0, 0, 0, time.UTC)}
+					if err = sw.SetRow(fmt.Sprintf("A%d", r), row); err != nil {
+						return err
+					}
+				}
+				if err = sw.SetCellHyperLink("A2", "https://github.com/xuri/excelize", "External"); err != nil {
+					return err
+				}
+				if err = sw.AddTable(&Table{Range: "A1:B1000", Name: "Table" + sheet}); err != nil {
+					return err
+				}
+				dv := NewDataValidation(true)
+				dv.Sqref = "C2:C1000"
+				if err = dv.SetDropList([]string{sheet, "Other"}); err != nil {
+					return err
+				}
+				if err = sw.AddDataValidation(dv); err != nil {
+					return err
+				}
+				return sw.Flush()
+			}()
+		}(i, sheet)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, f.Write(&buf))
+	assert.NoError(t, f.Close())
+
+	f, err := OpenReader(&buf)
+	assert.NoError(t, err)
+	for _, sheet := range sheets {
+		rows, err := f.GetRows(sheet)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1000)
+		assert.Equal(t, sheet+"-1000", rows[999][0])
+		tables, err := f.GetTables(sheet)
+		assert.NoError(t, err)
+		assert.Len(t, tables, 1)
+		ok, target, err := f.GetCellHyperLink(sheet, "A2")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "https://github.com/xuri/excelize", target)
+		dvs, err := f.GetDataValidations(sheet)
+		assert.NoError(t, err)
+		assert.Len(t, dvs, 1)
+		assert.Equal(t, "C2:C1000", dvs[0].Sqref)
+		assert.Equal(t, fmt.Sprintf("\"%s,Other\"", sheet), dvs[0].Formula1)
+	}
+	assert.NoError(t, f.Close())
+}
+
+func TestStreamWriterOutput(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet2", "A1", "Normal"))
+	sw, err := f.NewStreamWriter("Sheet1", StreamOptions{Writer: &buf, BufferSize: 1024})
+	assert.NoError(t, err)
+	// Test create another stream writer on the output before flush
+	_, err = f.NewStreamWriter("Sheet2", StreamOptions{Writer: &buf})
+	assert.Equal(t, ErrStreamOutput, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"Name", "Value"}))
+	assert.NoError(t, sw.AddTable(&Table{Range: "A1:B1000"}))
+	for r := 2; r <= 1000; r++ {
+		assert.NoError(t, sw.SetRow(fmt.Sprintf("A%d", r), []interface{}{"Apple", r}))
+	}
+	// Test the rows have been compressed into the output without temporary file
+	assert.NotZero(t, buf.Len())
+	assert.Nil(t, sw.rawData.tmp)
+	assert.NoError(t, sw.SetCellHyperLink("A2", "https://github.com/xuri/excelize", "External"))
+	assert.NoError(t, sw.Flush())
+	// Test save the workbook which writes to the output directly
+	assert.Equal(t, ErrStreamSave, f.SaveAs(filepath.Join("test", "TestStreamWriterOutput.xlsx")))
+	assert.NoFileExists(t, filepath.Join("test", "TestStreamWriterOutput.xlsx"))
+	assert.Equal(t, ErrStreamSave, f.Write(&bytes.Buffer{}))
+	_, err = f.WriteToBuffer()
+	assert.Equal(t, ErrStreamSave, err)
+	// Test add table after the header row has been flushed to the output
+	assert.Equal(t, ErrStreamTableHeader, sw.AddTable(&Table{Range: "A1:B2"}))
+	// Test create stream writer with another output
+	_, err = f.NewStreamWriter("Sheet2", StreamOptions{Writer: &bytes.Buffer{}})
+	assert.Equal(t, ErrStreamOutput, err)
+	// Test create another stream writer on the output, and close the workbook
+	// without flush
+	sw, err = f.NewStreamWriter("Sheet2", StreamOptions{Writer: &buf, Append: true})
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A2", []interface{}{"Stream"}))
+	assert.NoError(t, f.Close())
+
+	f, err = OpenReader(&buf)
+	assert.NoError(t, err)
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1000)
+	assert.Equal(t, []string{"Apple", "1000"}, rows[999])
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, tables, 1)
+	ok, target, err := f.GetCellHyperLink("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "https://github.com/xuri/excelize", target)
+	rows, err = f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Normal"}, {"Stream"}}, rows)
+	assert.NoError(t, f.Close())
+
+	// Test add table after the header row has been written to the output
+	buf.Reset()
+	f = NewFile()
+	sw, err = f.NewStreamWriter("Sheet1", StreamOptions{Writer: &buf, BufferSize: 64})
+	assert.NoError(t, err)
+	assert.NoError(t, sw.SetRow("A1", []interface{}{"Name", "Value"}))
+	assert.NoError(t, sw.AddTable(&Table{Range: "A1:B3"}))
+	assert.NoError(t, sw.SetRow("A2", []interface{}{"Apple", 1}))
+	assert.NoError(t, sw.SetRow("A3", []interface{}{"Banana", 2}))
+	assert.Equal(t, ErrStreamTableHeader, sw.AddTable(&Table{Range: "A2:B3"}))
+	assert.NoError(t, f.Close())
+	f, err = OpenReader(&buf)
+	assert.NoError(t, err)
+	content, ok := f.Pkg.Load("xl/tables/table1.xml")
+	assert.True(t, ok)
+	assert.Contains(t, string(content.([]byte)), `<tableColumn id="1" name="Name"></tableColumn><tableColumn id="2" name="Value"></tableColumn>`)
+	assert.NoError(t, f.Close())
+
+	// Test close the workbook with error on writing the output
+	f = NewFile()
+	_, err = f.NewStreamWriter("Sheet1", StreamOptions{Writer: &buf})
+	assert.NoError(t, err)
+	f.Pkg.Store("/d/", []byte("s"))
+	assert.EqualError(t, f.Close(), "zip: write to directory")
+}
+
 func TestStreamMarshalAttrs(t *testing.T) {
 	var r *RowOpts
 	attrs, err := r.marshalAttrs()
@@ -342,6 +651,37 @@ func TestStreamSetRowWithStyle(t *testing.T) {
 	}
 }
 
+func TestStreamWriterStringStorage(t *testing.T) {
+	f := NewFile(Options{StringStorage: StringStorageShared, MaxSharedStrings: 2})
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	for rowID, row := range [][]interface{}{
+		{"A", []byte("B")}, {"A", "C"}, {Cell{Formula: "\"A\"&\"B\"", Value: "AB"}, 1},
+	} {
+		cell, err := CoordinatesToCellName(1, rowID+1)
+		assert.NoError(t, err)
+		assert.NoError(t, sw.SetRow(cell, row))
+	}
+	assert.NoError(t, sw.Flush())
+	data := f.readXML("xl/worksheets/sheet1.xml")
+	assert.Contains(t, string(data), `<c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c>`)
+	assert.Contains(t, string(data), `<c r="A2" t="s"><v>0</v></c><c r="B2" t="inlineStr"><is><t>C</t></is></c>`)
+	assert.Contains(t, string(data), `<c r="A3" t="str"><f>&#34;A&#34;&amp;&#34;B&#34;</f><v>AB</v></c>`)
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"A", "B"}, {"A", "C"}, {"AB", "1"}}, rows)
+	assert.NoError(t, f.Close())
+
+	// Test set string cell value with unsupported charset shared strings table
+	f = NewFile(Options{StringStorage: StringStorageShared})
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	sw, err = f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.EqualError(t, sw.SetRow("A1", []interface{}{"A"}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
 func TestStreamSetCellValFunc(t *testing.T) {
 	f := NewFile()
 	defer func() {
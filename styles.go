@@ -32,6 +32,7 @@ func (f *File) stylesReader() (*xlsxStyleSheet, error) {
 			Decode(f.Styles); err != nil && err != io.EOF {
 			return f.Styles, err
 		}
+		f.trackPart(defaultXMLPathStyles, f.Styles)
 	}
 	return f.Styles, nil
 }
@@ -41,7 +42,9 @@ func (f *File) stylesReader() (*xlsxStyleSheet, error) {
 func (f *File) styleSheetWriter() {
 	if f.Styles != nil {
 		output, _ := xml.Marshal(f.Styles)
-		f.saveFileList(defaultXMLPathStyles, f.replaceNameSpaceBytes(defaultXMLPathStyles, output))
+		if f.partModified(defaultXMLPathStyles, output) {
+			f.saveFileList(defaultXMLPathStyles, f.replaceNameSpaceBytes(defaultXMLPathStyles, output))
+		}
 	}
 }
 
@@ -118,7 +121,9 @@ func (f *File) themeWriter() {
 func (f *File) sharedStringsWriter() {
 	if f.SharedStrings != nil {
 		output, _ := xml.Marshal(f.SharedStrings)
-		f.saveFileList(defaultXMLPathSharedStrings, f.replaceNameSpaceBytes(defaultXMLPathSharedStrings, output))
+		if f.partModified(defaultXMLPathSharedStrings, output) {
+			f.saveFileList(defaultXMLPathSharedStrings, f.replaceNameSpaceBytes(defaultXMLPathSharedStrings, output))
+		}
 	}
 }
 
@@ -0,0 +1,491 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/mohae/deepcopy"
+)
+
+var (
+	// templateRangeExp defined the regular expression to match the range
+	// action with the optional variable declarations in the template.
+	templateRangeExp = regexp.MustCompile(`\{\{-?\s*range\s+(?:(\$\w*(?:\s*,\s*\$\w*)?\s*:?=)\s*)?(.+?)\s*-?\}\}`)
+	// templateOpenExp defined the regular expression to match the actions
+	// which should be closed by the end action in the template.
+	templateOpenExp = regexp.MustCompile(`\{\{-?\s*(?:range|if|with|block|define)\b`)
+	// templateEndExp defined the regular expression to match the end action
+	// in the template.
+	templateEndExp = regexp.MustCompile(`\{\{-?\s*end\s*-?\}\}`)
+)
+
+// TemplateOptions directly maps the settings of the template rendering.
+// Funcs specifies the additional functions which could be called in the
+// placeholders of the template.
+type TemplateOptions struct {
+	Funcs template.FuncMap
+}
+
+// templateCell defined the cell which contains the placeholders of the
+// template.
+type templateCell struct {
+	cell string
+	row  int
+	text string
+}
+
+// templateBlock defined the repeating rows of the template, which starting
+// with the range action and ending with the end action.
+type templateBlock struct {
+	start, end int
+	decl, expr string
+	startCell  templateCell
+	endCell    templateCell
+}
+
+// templateRenderer defined the renderer for rendering the placeholders of
+// the template.
+type templateRenderer struct {
+	f     *File
+	sheet string
+	data  interface{}
+	funcs template.FuncMap
+	item  map[int]interface{}
+	value interface{}
+}
+
+// RenderTemplate provides a function to render the template worksheet by
+// given worksheet name and data. The placeholders in the cells, headers,
+// footers and comments of the worksheet will be evaluated as the Go
+// text/template actions with the given data. The cell with only one
+// placeholder will keep the type of the evaluated value, for example, the
+// number will be set as the numeric cell value, and the style of the cells
+// will be kept. For example, render a worksheet with the data:
+//
+//	type Item struct {
+//	    Name  string
+//	    Price float64
+//	}
+//	err := f.RenderTemplate("Sheet1", map[string]interface{}{
+//	    "Customer": map[string]string{"Name": "Excelize"},
+//	    "Items":    []Item{{Name: "Apple", Price: 1.5}, {Name: "Orange", Price: 2}},
+//	})
+//
+// The rows between a cell containing the range action, such as
+// {{range .Items}}, and the cell containing the corresponding {{end}} action
+// are the repeating rows block, which will be duplicated for each element of
+// the slice or array, and rendered with the element as the dot. The variable
+// declarations such as {{range $i, $item := .Items}} are also supported, and
+// the $ variable refers to the given data. The rows of the block will be
+// inserted like the DuplicateRowTo function, so the styles, merged cells,
+// formulas, tables, conditional formats and data validations will be
+// adjusted, and the rows block will be removed if the slice is empty. The
+// placeholders split into multiple cells or rich text runs are not
+// supported.
+func (f *File) RenderTemplate(sheet string, data interface{}, opts ...TemplateOptions) error {
+	defer f.pinWorksheets()()
+	tr := &templateRenderer{f: f, sheet: sheet, data: data, funcs: template.FuncMap{}}
+	for _, opt := range opts {
+		for name, fn := range opt.Funcs {
+			tr.funcs[name] = fn
+		}
+	}
+	tr.funcs["_excelizeItem"] = func() map[int]interface{} { return tr.item }
+	tr.funcs["_excelizeValue"] = func(v interface{}) string {
+		tr.value = v
+		return ""
+	}
+	for row := 1; ; {
+		cells, err := tr.getCells(row)
+		if err != nil {
+			return err
+		}
+		block, err := tr.getBlock(cells)
+		if err != nil {
+			return err
+		}
+		if block == nil {
+			if err = tr.renderCells(cells); err != nil {
+				return err
+			}
+			break
+		}
+		for i, cell := range cells {
+			if cell.row >= block.start {
+				cells = cells[:i]
+				break
+			}
+		}
+		if err = tr.renderCells(cells); err != nil {
+			return err
+		}
+		if row, err = tr.renderBlock(block); err != nil {
+			return err
+		}
+	}
+	if err := tr.renderHeaderFooter(); err != nil {
+		return err
+	}
+	return tr.renderComments()
+}
+
+// getCells provides a function to get the cells which contain the
+// placeholders of the template starting from the given row number.
+func (tr *templateRenderer) getCells(fromRow int) ([]templateCell, error) {
+	sst, err := tr.f.sharedStringsReader()
+	if err != nil {
+		return nil, err
+	}
+	tr.f.mu.Lock()
+	ws, err := tr.f.workSheetReader(tr.sheet)
+	if err != nil {
+		tr.f.mu.Unlock()
+		return nil, err
+	}
+	tr.f.mu.Unlock()
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	var cells []templateCell
+	for _, row := range ws.SheetData.Row {
+		if row.R < fromRow {
+			continue
+		}
+		for _, c := range row.C {
+			if c.F != nil || (c.T != "s" && c.T != "inlineStr") {
+				continue
+			}
+			val, err := c.getValueFrom(tr.f, sst, true)
+			if err != nil {
+				return nil, err
+			}
+			if strings.Contains(val, "{{") {
+				cells = append(cells, templateCell{cell: c.R, row: row.R, text: val})
+			}
+		}
+	}
+	return cells, nil
+}
+
+// getBlock provides a function to get the first repeating rows block in the
+// given cells, and return nil if the block not exists.
+func (tr *templateRenderer) getBlock(cells []templateCell) (*templateBlock, error) {
+	var block *templateBlock
+	for _, cell := range cells {
+		opens := len(templateOpenExp.FindAllString(cell.text, -1))
+		ends := len(templateEndExp.FindAllString(cell.text, -1))
+		if block == nil {
+			if opens > ends {
+				if match := templateRangeExp.FindStringSubmatch(cell.text); match != nil {
+					block = &templateBlock{start: cell.row, decl: match[1], expr: match[2], startCell: cell}
+				}
+			}
+			continue
+		}
+		if ends > opens {
+			block.end, block.endCell = cell.row, cell
+			return block, nil
+		}
+	}
+	if block != nil {
+		return nil, newUnclosedTemplateRangeError(block.startCell.cell)
+	}
+	return nil, nil
+}
+
+// renderBlock provides a function to expand the repeating rows block for each
+// element of the evaluated range expression, render the rows of the block,
+// and returns the row number next to the expanded rows.
+func (tr *templateRenderer) renderBlock(block *templateBlock) (int, error) {
+	items, err := tr.getItems(block)
+	if err != nil {
+		return 0, err
+	}
+	size := block.end - block.start + 1
+	if len(items) == 0 {
+		for row := block.end; row >= block.start; row-- {
+			if err = tr.f.RemoveRow(tr.sheet, row); err != nil {
+				return 0, err
+			}
+		}
+		return block.start, nil
+	}
+	if err = tr.removeBlockMarkers(block); err != nil {
+		return 0, err
+	}
+	mergeCells, err := tr.getBlockMergeCells(block)
+	if err != nil {
+		return 0, err
+	}
+	if err = tr.duplicateBlock(block, len(items)-1); err != nil {
+		return 0, err
+	}
+	for k := 1; k < len(items); k++ {
+		for _, coordinates := range mergeCells {
+			from, _ := CoordinatesToCellName(coordinates[0], coordinates[1]+k*size)
+			to, _ := CoordinatesToCellName(coordinates[2], coordinates[3]+k*size)
+			if err = tr.f.MergeCell(tr.sheet, from, to); err != nil {
+				return 0, err
+			}
+		}
+	}
+	next := block.start + len(items)*size
+	cells, err := tr.getCells(block.start)
+	if err != nil {
+		return 0, err
+	}
+	for _, cell := range cells {
+		if cell.row >= next {
+			break
+		}
+		k := (cell.row - block.start) / size
+		tr.item = map[int]interface{}{k: items[k]}
+		if err = tr.setCellValue(cell, block.decl); err != nil {
+			return 0, err
+		}
+	}
+	tr.item = nil
+	return next, nil
+}
+
+// duplicateBlock provides a function to insert the rows for the given number
+// of copies of the repeating rows block at once, and copy the rows of the
+// block into the inserted rows like the DuplicateRowTo function, so the rows
+// below the block will be shifted only once.
+func (tr *templateRenderer) duplicateBlock(block *templateBlock, copies int) error {
+	if copies < 1 {
+		return nil
+	}
+	size := block.end - block.start + 1
+	tr.f.mu.Lock()
+	ws, err := tr.f.workSheetReader(tr.sheet)
+	tr.f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	var blockRows []xlsxRow
+	for _, row := range ws.SheetData.Row {
+		if row.R >= block.start && row.R <= block.end {
+			blockRows = append(blockRows, deepcopy.Copy(row).(xlsxRow))
+		}
+	}
+	if err = tr.f.InsertRows(tr.sheet, block.end+1, copies*size); err != nil {
+		return err
+	}
+	ws.prepareSheetXML(0, block.end+copies*size)
+	for k := 1; k <= copies; k++ {
+		for _, blockRow := range blockRows {
+			row := deepcopy.Copy(blockRow).(xlsxRow)
+			row.adjustSingleRowDimensions(k * size)
+			_ = tr.f.adjustSingleRowFormulas(tr.sheet, tr.sheet, &row, blockRow.R, k*size, true)
+			ws.SheetData.Row[row.R-1] = row
+		}
+	}
+	for k := 1; k <= copies; k++ {
+		for row := block.start; row <= block.end; row++ {
+			for _, fn := range duplicateHelperFunc {
+				if err = fn(tr.f, ws, tr.sheet, row, row+k*size); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return err
+}
+
+// removeBlockMarkers provides a function to remove the range action and the
+// corresponding end action from the cells of the repeating rows block.
+func (tr *templateRenderer) removeBlockMarkers(block *templateBlock) error {
+	text := block.startCell.text
+	loc := templateRangeExp.FindStringIndex(text)
+	if err := tr.f.SetCellStr(tr.sheet, block.startCell.cell, text[:loc[0]]+text[loc[1]:]); err != nil {
+		return err
+	}
+	text = block.endCell.text
+	locs := templateEndExp.FindAllStringIndex(text, -1)
+	loc = locs[len(locs)-1]
+	return tr.f.SetCellStr(tr.sheet, block.endCell.cell, text[:loc[0]]+text[loc[1]:])
+}
+
+// getItems provides a function to evaluate the range expression of the
+// repeating rows block with the given data, and returns the elements.
+func (tr *templateRenderer) getItems(block *templateBlock) ([]interface{}, error) {
+	if _, err := tr.execute(block.startCell.cell, "{{_excelizeValue ("+block.expr+")}}"); err != nil {
+		return nil, err
+	}
+	val := reflect.ValueOf(tr.value)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		val = val.Elem()
+	}
+	if !val.IsValid() {
+		return nil, nil
+	}
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return nil, newTemplateRangeTypeError(block.startCell.cell, val.Type().String())
+	}
+	items := make([]interface{}, val.Len())
+	for i := range items {
+		items[i] = val.Index(i).Interface()
+	}
+	return items, nil
+}
+
+// getBlockMergeCells provides a function to get the coordinates of the merged
+// cells across multiple rows inside the repeating rows block, which will not
+// be duplicated by the DuplicateRowTo function.
+func (tr *templateRenderer) getBlockMergeCells(block *templateBlock) ([][]int, error) {
+	mergeCells, err := tr.f.GetMergeCells(tr.sheet)
+	if err != nil {
+		return nil, err
+	}
+	var coordinates [][]int
+	for _, mergeCell := range mergeCells {
+		rect, err := rangeRefToCoordinates(mergeCell[0])
+		if err != nil {
+			return nil, err
+		}
+		if rect[1] >= block.start && rect[3] <= block.end && rect[1] != rect[3] {
+			coordinates = append(coordinates, rect)
+		}
+	}
+	return coordinates, nil
+}
+
+// renderCells provides a function to render the given cells with the data.
+func (tr *templateRenderer) renderCells(cells []templateCell) error {
+	for _, cell := range cells {
+		if err := tr.setCellValue(cell, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setCellValue provides a function to render the placeholders of the given
+// cell and set the cell value. The cell in the repeating rows block will be
+// rendered with the current element as the dot. The cell with only one
+// placeholder will keep the type of the evaluated value.
+func (tr *templateRenderer) setCellValue(cell templateCell, decl string) error {
+	prefix, suffix := "", ""
+	if tr.item != nil {
+		prefix, suffix = "{{range "+decl+" _excelizeItem}}", "{{end}}"
+	}
+	text := prefix + cell.text + suffix
+	tmpl, err := template.New(cell.cell).Funcs(tr.funcs).Parse(text)
+	if err != nil {
+		return err
+	}
+	nodes := tmpl.Tree.Root.Nodes
+	if tr.item != nil {
+		nodes = nodes[0].(*parse.RangeNode).List.Nodes
+	}
+	var single bool
+	if len(nodes) == 1 {
+		action, ok := nodes[0].(*parse.ActionNode)
+		if single = ok && len(action.Pipe.Decl) == 0; single {
+			text = prefix + "{{_excelizeValue (" + action.Pipe.String() + ")}}" + suffix
+		}
+	}
+	result, err := tr.execute(cell.cell, text)
+	if err != nil {
+		return err
+	}
+	if !single {
+		return tr.f.SetCellStr(tr.sheet, cell.cell, result)
+	}
+	if val := reflect.ValueOf(tr.value); val.IsValid() {
+		value, err := getStructFieldValue(val)
+		if err != nil {
+			value = fmt.Sprint(tr.value)
+		}
+		return tr.f.SetCellValue(tr.sheet, cell.cell, value)
+	}
+	return tr.f.SetCellValue(tr.sheet, cell.cell, nil)
+}
+
+// execute provides a function to parse and execute the given template text
+// with the data, and returns the result text.
+func (tr *templateRenderer) execute(name, text string) (string, error) {
+	tmpl, err := template.New(name).Funcs(tr.funcs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	tr.value = nil
+	err = tmpl.Execute(&buf, tr.data)
+	return buf.String(), err
+}
+
+// renderHeaderFooter provides a function to render the placeholders in the
+// headers and footers of the worksheet.
+func (tr *templateRenderer) renderHeaderFooter() error {
+	tr.f.mu.Lock()
+	ws, err := tr.f.workSheetReader(tr.sheet)
+	tr.f.mu.Unlock()
+	if err != nil || ws.HeaderFooter == nil {
+		return err
+	}
+	for _, text := range []*string{
+		&ws.HeaderFooter.OddHeader, &ws.HeaderFooter.OddFooter,
+		&ws.HeaderFooter.EvenHeader, &ws.HeaderFooter.EvenFooter,
+		&ws.HeaderFooter.FirstHeader, &ws.HeaderFooter.FirstFooter,
+	} {
+		if strings.Contains(*text, "{{") {
+			if *text, err = tr.execute(tr.sheet, *text); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// renderComments provides a function to render the placeholders in the
+// comments of the worksheet.
+func (tr *templateRenderer) renderComments() error {
+	sheetXMLPath, _ := tr.f.getSheetXMLPath(tr.sheet)
+	commentsXML := tr.f.getSheetComments(filepath.Base(sheetXMLPath))
+	if !strings.HasPrefix(commentsXML, "/") {
+		commentsXML = "xl" + strings.TrimPrefix(commentsXML, "..")
+	}
+	cmts, err := tr.f.commentsReader(strings.TrimPrefix(commentsXML, "/"))
+	if err != nil || cmts == nil {
+		return err
+	}
+	render := func(text *string) error {
+		if strings.Contains(*text, "{{") {
+			*text, err = tr.execute(tr.sheet, *text)
+		}
+		return err
+	}
+	for i := range cmts.CommentList.Comment {
+		cmt := &cmts.CommentList.Comment[i]
+		if cmt.Text.T != nil {
+			if err = render(cmt.Text.T); err != nil {
+				return err
+			}
+		}
+		for _, run := range cmt.Text.R {
+			if run.T != nil {
+				if err = render(&run.T.Val); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return err
+}
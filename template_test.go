@@ -0,0 +1,145 @@
+package excelize
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	type item struct {
+		Name  string
+		Price float64
+		Qty   *int
+	}
+	qty := 3
+	data := map[string]interface{}{
+		"Customer": map[string]string{"Name": "Excelize"},
+		"Items":    []item{{Name: "Apple", Price: 1.5, Qty: &qty}, {Name: "Orange", Price: 2}, {Name: "Pear", Price: 2.5}},
+		"Empty":    []item{},
+		"Author":   "Tester",
+	}
+	f := NewFile()
+	for cell, value := range map[string]interface{}{
+		"A1": "Invoice for {{.Customer.Name}}",
+		"A3": "{{range .Items}}{{.Name}}", "B3": "{{.Price}}", "C3": "{{.Qty}}{{end}}", "D3": "{{upper $.Customer.Name}}",
+		"A4": "Total",
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B4", "SUM(B2:B4)"))
+	assert.NoError(t, f.MergeCell("Sheet1", "D3", "E3"))
+	style, err := f.NewStyle(&Style{NumFmt: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B3", "B3", style))
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "C3", []ConditionalFormatOptions{
+		{Type: "cell", Criteria: ">", Format: &style, Value: "1"},
+	}))
+	assert.NoError(t, f.SetHeaderFooter("Sheet1", &HeaderFooterOptions{OddHeader: "&C{{.Customer.Name}}", OddFooter: "&RPage &P"}))
+	assert.NoError(t, f.AddComment("Sheet1", Comment{Cell: "A1", Author: "Excelize", Text: "By {{.Author}}"}))
+	assert.NoError(t, f.RenderTemplate("Sheet1", data, TemplateOptions{Funcs: template.FuncMap{"upper": strings.ToUpper}}))
+
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Invoice for Excelize"},
+		nil,
+		{"Apple", "1.50", "3", "EXCELIZE"},
+		{"Orange", "2.00", "", "EXCELIZE"},
+		{"Pear", "2.50", "", "EXCELIZE"},
+		{"Total", ""},
+	}, rows)
+	cellType, err := f.GetCellType("Sheet1", "B4")
+	assert.NoError(t, err)
+	assert.Equal(t, CellTypeUnset, cellType)
+	styleID, err := f.GetCellStyle("Sheet1", "B5")
+	assert.NoError(t, err)
+	assert.Equal(t, style, styleID)
+	formula, err := f.GetCellFormula("Sheet1", "B6")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(B2:B6)", formula)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 3)
+	assert.Equal(t, "D5:E5", mergeCells[2][0])
+	conditionalFormats, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, conditionalFormats, 3)
+	headerFooter, err := f.GetHeaderFooter("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "&CExcelize", headerFooter.OddHeader)
+	assert.Equal(t, "&RPage &P", headerFooter.OddFooter)
+	comments, err := f.GetComments("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "By Tester", comments[0].Text)
+
+	// Test render the multiple rows block with the variable declarations and
+	// the merged cells across rows, and the empty block
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	for cell, value := range map[string]interface{}{
+		"A1": "{{range $i, $v := .Items}}No. {{$i}}", "B1": "{{$v.Name}}", "B2": "{{$v.Price}}{{end}}",
+		"A3": "{{range .Empty}}{{.Name}}{{end}}", "A4": "{{ range .Empty }}{{.Name}}", "A5": "{{end}}",
+		"A6": "{{len .Items}}",
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet2", cell, value))
+	}
+	assert.NoError(t, f.MergeCell("Sheet2", "A1", "A2"))
+	assert.NoError(t, f.RenderTemplate("Sheet2", data))
+	rows, err = f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"No. 0", "Apple"}, {"", "1.5"},
+		{"No. 1", "Orange"}, {"", "2"},
+		{"No. 2", "Pear"}, {"", "2.5"},
+		nil, {"3"},
+	}, rows)
+	mergeCells, err = f.GetMergeCells("Sheet2")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 3)
+	assert.Equal(t, "A5:A6", mergeCells[2][0])
+
+	// Test render the block with large number of elements
+	_, err = f.NewSheet("Sheet3")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet3", "A1", "{{range .}}{{.}}"))
+	assert.NoError(t, f.SetCellValue("Sheet3", "B1", "{{end}}"))
+	assert.NoError(t, f.SetCellValue("Sheet3", "A2", "Total"))
+	items := make([]int, 10000)
+	for i := range items {
+		items[i] = i + 1
+	}
+	assert.NoError(t, f.RenderTemplate("Sheet3", items))
+	for cell, expected := range map[string]string{"A1": "1", "A5000": "5000", "A10000": "10000", "A10001": "Total"} {
+		value, err := f.GetCellValue("Sheet3", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+
+	// Test render template with the invalid templates
+	for _, c := range []struct {
+		value, err string
+	}{
+		{"{{range .Items}}", "template range action in cell A1 is not closed"},
+		{"{{range .Author}}{{end}}{{range .Author}}", "template range action in cell A1 is not closed"},
+		{"{{index .Items 5}}", "template: A1:1:18: executing \"A1\" at <index .Items 5>: error calling index: index out of range: 5"},
+		{"{{.Name", "template: A1:1: unclosed action"},
+	} {
+		f := NewFile()
+		assert.NoError(t, f.SetCellValue("Sheet1", "A1", c.value))
+		assert.EqualError(t, f.RenderTemplate("Sheet1", data), c.err)
+	}
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "{{range .Author}}"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", "{{end}}"))
+	assert.EqualError(t, f.RenderTemplate("Sheet1", data), "template range action in cell A1 can't iterate over string")
+	// Test render template on not exists worksheet
+	assert.EqualError(t, f.RenderTemplate("SheetN", data), "sheet SheetN does not exist")
+	// Test render template with unsupported charset shared strings table
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.RenderTemplate("Sheet1", data), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
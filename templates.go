@@ -87,7 +87,9 @@ const (
 	SourceRelationshipVBAProject                  = "http://schemas.microsoft.com/office/2006/relationships/vbaProject"
 	SourceRelationshipWorkSheet                   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet"
 	StrictNameSpaceDocumentPropertiesVariantTypes = "http://purl.oclc.org/ooxml/officeDocument/docPropsVTypes"
+	StrictNameSpaceDrawingMLChart                 = "http://purl.oclc.org/ooxml/drawingml/chart"
 	StrictNameSpaceDrawingMLMain                  = "http://purl.oclc.org/ooxml/drawingml/main"
+	StrictNameSpaceDrawingMLSpreadSheet           = "http://purl.oclc.org/ooxml/drawingml/spreadsheetDrawing"
 	StrictNameSpaceExtendedProperties             = "http://purl.oclc.org/ooxml/officeDocument/extendedProperties"
 	StrictNameSpaceSpreadSheet                    = "http://purl.oclc.org/ooxml/spreadsheetml/main"
 	StrictSourceRelationship                      = "http://purl.oclc.org/ooxml/officeDocument/relationships"
@@ -202,7 +202,12 @@ func (f *File) workbookReader() (*xlsxWorkbook, error) {
 			if attrs == nil {
 				attrs = []xml.Attr{}
 			}
-			attrs = append(attrs.([]xml.Attr), getRootElement(d)...)
+			for _, attr := range getRootElement(d) {
+				if attr.Name.Space == "" && attr.Name.Local == "conformance" {
+					continue
+				}
+				attrs = append(attrs.([]xml.Attr), attr)
+			}
 			f.xmlAttr.Store(wbPath, attrs)
 			f.addNameSpaces(wbPath, SourceRelationship)
 		}
@@ -225,8 +230,15 @@ func (f *File) workBookWriter() {
 			}
 		}
 		f.WorkBook.DecodeAlternateContent = nil
+		conformance := f.WorkBook.Conformance
+		f.WorkBook.Conformance = ""
 		output, _ := xml.Marshal(f.WorkBook)
-		f.saveFileList(f.getWorkbookPath(), replaceRelationshipsBytes(f.replaceNameSpaceBytes(f.getWorkbookPath(), output)))
+		f.WorkBook.Conformance = conformance
+		output = replaceRelationshipsBytes(f.replaceNameSpaceBytes(f.getWorkbookPath(), output))
+		if conformance != "" {
+			output = bytes.Replace(output, []byte("<workbook"), []byte(`<workbook conformance="`+conformance+`"`), 1)
+		}
+		f.saveFileList(f.getWorkbookPath(), output)
 	}
 }
 
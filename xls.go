@@ -0,0 +1,762 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/richardlehane/mscfb"
+)
+
+// BIFF8 record types enumeration.
+const (
+	xlsFormula     = 0x0006
+	xlsEOF         = 0x000A
+	xlsExternSheet = 0x0017
+	xlsName        = 0x0018
+	xlsDateMode    = 0x0022
+	xlsFilePass    = 0x002F
+	xlsFont        = 0x0031
+	xlsContinue    = 0x003C
+	xlsColInfo     = 0x007D
+	xlsBoundSheet  = 0x0085
+	xlsPalette     = 0x0092
+	xlsMulRk       = 0x00BD
+	xlsMulBlank    = 0x00BE
+	xlsXF          = 0x00E0
+	xlsMergeCells  = 0x00E5
+	xlsSST         = 0x00FC
+	xlsLabelSST    = 0x00FD
+	xlsSupBook     = 0x01AE
+	xlsBlank       = 0x0201
+	xlsNumber      = 0x0203
+	xlsLabel       = 0x0204
+	xlsBoolErr     = 0x0205
+	xlsString      = 0x0207
+	xlsRow         = 0x0208
+	xlsArray       = 0x0221
+	xlsRk          = 0x027E
+	xlsFormat      = 0x041E
+	xlsShrFmla     = 0x04BC
+	xlsBOF         = 0x0809
+)
+
+// xlsBuiltInNames defined the built-in defined names in the BIFF8 workbook.
+var xlsBuiltInNames = []string{
+	"Consolidate_Area", "Auto_Open", "Auto_Close", "Extract", "Database",
+	"Criteria", "Print_Area", "Print_Titles", "Recorder", "Data_Form",
+	"Auto_Activate", "Auto_Deactivate", "Sheet_Title", "_FilterDatabase",
+}
+
+// xlsDefaultPalette defined the fixed colors of the first 8 color indexes in
+// the BIFF8 workbook.
+var xlsDefaultPalette = []string{"000000", "FFFFFF", "FF0000", "00FF00", "0000FF", "FFFF00", "FF00FF", "00FFFF"}
+
+// xlsRecord defined the BIFF8 record with data of the continue records.
+type xlsRecord struct {
+	id        uint16
+	offset    int
+	data      []byte
+	continues [][]byte
+}
+
+// bytes returns the data of the record with the continue records.
+func (r *xlsRecord) bytes() []byte {
+	if len(r.continues) == 0 {
+		return r.data
+	}
+	return bytes.Join(append([][]byte{r.data}, r.continues...), nil)
+}
+
+// xlsContinueReader defined the reader for the record data which may be split
+// into continue records, the characters of the string in each continue record
+// starts with a new option flags byte.
+type xlsContinueReader struct {
+	segments [][]byte
+	seg, off int
+	err      error
+}
+
+// next returns the next n bytes of the reader across the continue records.
+func (r *xlsContinueReader) next(n int) []byte {
+	var b []byte
+	for n > 0 && r.err == nil {
+		if r.off == len(r.segments[r.seg]) {
+			if r.seg++; r.seg == len(r.segments) {
+				r.err = ErrWorkbookFileFormat
+				break
+			}
+			r.off = 0
+		}
+		size := len(r.segments[r.seg]) - r.off
+		if size > n {
+			size = n
+		}
+		b = append(b, r.segments[r.seg][r.off:r.off+size]...)
+		r.off, n = r.off+size, n-size
+	}
+	if r.err != nil {
+		return make([]byte, 8)
+	}
+	return b
+}
+
+// u8 returns the next unsigned 8-bit integer of the reader.
+func (r *xlsContinueReader) u8() uint8 { return r.next(1)[0] }
+
+// u16 returns the next unsigned 16-bit integer of the reader.
+func (r *xlsContinueReader) u16() uint16 { return binary.LittleEndian.Uint16(r.next(2)) }
+
+// u32 returns the next unsigned 32-bit integer of the reader.
+func (r *xlsContinueReader) u32() uint32 { return binary.LittleEndian.Uint32(r.next(4)) }
+
+// chars returns the next n characters of the reader by given high byte flag.
+func (r *xlsContinueReader) chars(n int, highByte bool) string {
+	var u []uint16
+	for n > 0 && r.err == nil {
+		if r.off == len(r.segments[r.seg]) {
+			if r.seg+1 == len(r.segments) {
+				r.err = ErrWorkbookFileFormat
+				break
+			}
+			r.seg, r.off = r.seg+1, 0
+			highByte = r.u8()&0x01 != 0
+		}
+		size, avail := 1, len(r.segments[r.seg])-r.off
+		if highByte {
+			size = 2
+		}
+		count := avail / size
+		if count > n {
+			count = n
+		}
+		if count == 0 {
+			r.err = ErrWorkbookFileFormat
+			break
+		}
+		b := r.next(count * size)
+		for i := 0; i < count; i++ {
+			if highByte {
+				u = append(u, binary.LittleEndian.Uint16(b[i*2:]))
+				continue
+			}
+			u = append(u, uint16(b[i]))
+		}
+		n -= count
+	}
+	return string(utf16.Decode(u))
+}
+
+// str returns the next Unicode string with the rich text and phonetic data
+// of the reader by given length of the characters.
+func (r *xlsContinueReader) str(n int) string {
+	flags := r.u8()
+	var runs, ext int
+	if flags&0x08 != 0 {
+		runs = int(r.u16())
+	}
+	if flags&0x04 != 0 {
+		ext = int(r.u32())
+	}
+	s := r.chars(n, flags&0x01 != 0)
+	r.next(runs*4 + ext)
+	return s
+}
+
+// xlsReader defined the context of converting the BIFF8 workbook stream to
+// the XML parts.
+type xlsReader struct {
+	records      []xlsRecord
+	formula      binaryFormulaParser
+	sst          []string
+	cellXfs      map[int]int
+	styleXfs     map[int]int
+	palette      []xlsxColor
+	styles       *xlsxStyleSheet
+	wb           *xlsxWorkbook
+	sheetOffsets []int
+}
+
+// readXls provides a function to convert the Workbook stream of the Excel
+// 97-2003 (BIFF8) workbook into the XML parts of the spreadsheet package,
+// returns nil if the compound file doesn't contain the Workbook stream.
+func (f *File) readXls(raw []byte) (map[string][]byte, int, error) {
+	doc, err := mscfb.New(bytes.NewReader(raw))
+	if err != nil {
+		return nil, 0, nil
+	}
+	var stream []byte
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		if entry.Name == "Workbook" {
+			stream = make([]byte, entry.Size)
+			if _, err = io.ReadFull(doc, stream); err != nil {
+				return nil, 0, ErrWorkbookFileFormat
+			}
+			break
+		}
+	}
+	if stream == nil {
+		return nil, 0, nil
+	}
+	x := &xlsReader{cellXfs: map[int]int{}, styleXfs: map[int]int{}, formula: binaryFormulaParser{biff8: true, selfSupBooks: map[int]bool{}}}
+	if err = x.readRecords(stream); err != nil {
+		return nil, 0, err
+	}
+	if err = x.readGlobals(); err != nil {
+		return nil, 0, err
+	}
+	return x.writeParts()
+}
+
+// readRecords provides a function to split the BIFF8 workbook stream into
+// records.
+func (x *xlsReader) readRecords(stream []byte) error {
+	for off := 0; off+4 <= len(stream); {
+		id, size := binary.LittleEndian.Uint16(stream[off:]), int(binary.LittleEndian.Uint16(stream[off+2:]))
+		if off+4+size > len(stream) {
+			return ErrWorkbookFileFormat
+		}
+		data := stream[off+4 : off+4+size]
+		if id == xlsContinue && len(x.records) > 0 {
+			last := &x.records[len(x.records)-1]
+			last.continues = append(last.continues, data)
+		} else {
+			x.records = append(x.records, xlsRecord{id: id, offset: off, data: data})
+		}
+		off += 4 + size
+	}
+	if len(x.records) == 0 || x.records[0].id != xlsBOF || len(x.records[0].data) < 4 ||
+		binary.LittleEndian.Uint16(x.records[0].data) != 0x0600 {
+		return ErrWorkbookFileFormat
+	}
+	return nil
+}
+
+// readGlobals provides a function to read the workbook globals substream.
+func (x *xlsReader) readGlobals() error {
+	x.wb = &xlsxWorkbook{WorkbookPr: &xlsxWorkbookPr{}}
+	x.styles = &xlsxStyleSheet{
+		NumFmts: &xlsxNumFmts{}, Fonts: &xlsxFonts{}, CellStyleXfs: &xlsxCellStyleXfs{}, CellXfs: &xlsxCellXfs{},
+		Fills:      &xlsxFills{Fill: []*xlsxFill{{PatternFill: &xlsxPatternFill{PatternType: "none"}}, {PatternFill: &xlsxPatternFill{PatternType: "gray125"}}}},
+		Borders:    &xlsxBorders{Border: []*xlsxBorder{{}}},
+		CellStyles: &xlsxCellStyles{CellStyle: []*xlsxCellStyle{{Name: "Normal", XfID: 0, BuiltInID: intPtr(0)}}},
+	}
+	type definedName struct {
+		xlsxDefinedName
+		rgce, rgcb []byte
+	}
+	var (
+		definedNames []definedName
+		supBooks     int
+		xfs          int
+	)
+	for i := 1; i < len(x.records); i++ {
+		rec := &x.records[i]
+		if rec.id == xlsEOF {
+			break
+		}
+		r := &binaryReader{b: rec.bytes()}
+		switch rec.id {
+		case xlsFilePass:
+			return ErrWorkbookFileFormat
+		case xlsDateMode:
+			x.wb.WorkbookPr.Date1904 = r.u16() == 1
+		case xlsBoundSheet:
+			offset, state, typ := int(r.u32()), r.u8(), r.u8()
+			name := r.biff8String(int(r.u8()))
+			x.formula.sheets = append(x.formula.sheets, name)
+			if typ != 0 {
+				continue
+			}
+			x.sheetOffsets = append(x.sheetOffsets, offset)
+			x.wb.Sheets.Sheet = append(x.wb.Sheets.Sheet, xlsxSheet{
+				Name: name, SheetID: len(x.wb.Sheets.Sheet) + 1, State: map[uint8]string{1: "hidden", 2: "veryHidden"}[state&0x03],
+			})
+		case xlsSupBook:
+			r.u16()
+			if r.u16() == 0x0401 {
+				x.formula.selfSupBooks[supBooks] = true
+			}
+			supBooks++
+		case xlsExternSheet:
+			for j, n := 0, int(r.u16()); j < n && r.err == nil; j++ {
+				x.formula.externSheets = append(x.formula.externSheets, binaryExternSheet{
+					supBook: int(r.u16()), firstSheet: int(int16(r.u16())), lastSheet: int(int16(r.u16())),
+				})
+			}
+		case xlsName:
+			flags, _, cch, cce := r.u16(), r.u8(), int(r.u8()), int(r.u16())
+			r.next(2)
+			itab := int(r.u16())
+			r.next(4)
+			name := definedName{xlsxDefinedName: xlsxDefinedName{Name: r.biff8String(cch), Hidden: flags&0x01 != 0, Function: flags&0x02 != 0}}
+			if flags&0x20 != 0 && len(name.Name) == 1 && int(name.Name[0]) < len(xlsBuiltInNames) {
+				name.Name = "_xlnm." + xlsBuiltInNames[name.Name[0]]
+			}
+			if itab > 0 {
+				name.LocalSheetID = intPtr(itab - 1)
+			}
+			name.rgce = r.next(cce)
+			name.rgcb = r.b[r.off:]
+			definedNames = append(definedNames, name)
+			x.formula.names = append(x.formula.names, name.Name)
+		case xlsFormat:
+			id := int(r.u16())
+			code := r.biff8String(int(r.u16()))
+			if _, ok := builtInNumFmt[id]; !ok {
+				x.styles.NumFmts.NumFmt = append(x.styles.NumFmts.NumFmt, &xlsxNumFmt{NumFmtID: id, FormatCode: code})
+			}
+		case xlsFont:
+			x.styles.Fonts.Font = append(x.styles.Fonts.Font, readXlsFont(r))
+		case xlsPalette:
+			x.palette = nil
+			for j, n := 0, int(r.u16()); j < n && r.err == nil; j++ {
+				rgb := r.next(4)
+				x.palette = append(x.palette, xlsxColor{RGB: fmt.Sprintf("FF%02X%02X%02X", rgb[0], rgb[1], rgb[2])})
+			}
+		case xlsXF:
+			x.readXf(r, xfs)
+			xfs++
+		}
+		if r.err != nil {
+			return r.err
+		}
+	}
+	for _, name := range definedNames {
+		var err error
+		if name.Data, err = x.formula.parse(name.rgce, name.rgcb, 0, 0); err != nil {
+			continue
+		}
+		if x.wb.DefinedNames == nil {
+			x.wb.DefinedNames = &xlsxDefinedNames{}
+		}
+		x.wb.DefinedNames.DefinedName = append(x.wb.DefinedNames.DefinedName, name.xlsxDefinedName)
+	}
+	return x.readSST()
+}
+
+// readSST provides a function to read the shared strings table record.
+func (x *xlsReader) readSST() error {
+	for i := 1; i < len(x.records) && x.records[i].id != xlsEOF; i++ {
+		if rec := x.records[i]; rec.id == xlsSST {
+			r := &xlsContinueReader{segments: append([][]byte{rec.data}, rec.continues...)}
+			r.next(4)
+			for j, n := 0, int(r.u32()); j < n && r.err == nil; j++ {
+				x.sst = append(x.sst, r.str(int(r.u16())))
+			}
+			return r.err
+		}
+	}
+	return nil
+}
+
+// readXlsFont returns the font by given FONT record of the BIFF8 workbook.
+func readXlsFont(r *binaryReader) *xlsxFont {
+	height, flags, color, weight := r.u16(), r.u16(), int(r.u16()), r.u16()
+	r.next(2)
+	underline, family, charset := r.u8(), r.u8(), r.u8()
+	r.next(1)
+	font := &xlsxFont{Sz: &attrValFloat{Val: float64Ptr(float64(height) / 20)}, Name: &attrValString{Val: stringPtr(r.biff8String(int(r.u8())))}}
+	if color < 64 {
+		font.Color = &xlsxColor{Indexed: color}
+	}
+	for bit, val := range map[uint16]**attrValBool{0x02: &font.I, 0x08: &font.Strike, 0x10: &font.Outline, 0x20: &font.Shadow} {
+		if flags&bit != 0 {
+			*val = &attrValBool{Val: boolPtr(true)}
+		}
+	}
+	if weight >= 700 {
+		font.B = &attrValBool{Val: boolPtr(true)}
+	}
+	if u, ok := binaryUnderlineTypes[underline]; ok {
+		font.U = &attrValString{Val: stringPtr(u)}
+	}
+	if family > 0 {
+		font.Family = &attrValInt{Val: intPtr(int(family))}
+	}
+	if charset > 0 {
+		font.Charset = &attrValInt{Val: intPtr(int(charset))}
+	}
+	return font
+}
+
+// readXf provides a function to read the XF record of the BIFF8 workbook by
+// given index of the cell format.
+func (x *xlsReader) readXf(r *binaryReader, idx int) {
+	font, numFmt, flags := int(r.u16()), int(r.u16()), r.u16()
+	align, rotation, indent, apply := r.u8(), int(r.u8()), r.u8(), r.u8()
+	border1, border2, fill := r.u32(), r.u32(), r.u16()
+	if r.err != nil {
+		return
+	}
+	if font >= 4 {
+		font-- // the font index 4 is omitted in the BIFF8 workbook
+	}
+	xf := xlsxXf{NumFmtID: intPtr(numFmt), FontID: intPtr(font), FillID: intPtr(x.addFill(int(border2>>26), int(fill&0x7F), int(fill>>7)&0x7F))}
+	xf.BorderID = intPtr(x.addBorder(border1, border2))
+	alignment := &xlsxAlignment{
+		WrapText: align&0x08 != 0, JustifyLastLine: align&0x80 != 0, TextRotation: rotation,
+		Indent: int(indent & 0x0F), ShrinkToFit: indent&0x10 != 0, ReadingOrder: uint64(indent>>6) & 0x03,
+	}
+	if h := int(align & 0x07); h < len(binaryHorizontalAlignments) {
+		alignment.Horizontal = binaryHorizontalAlignments[h]
+	}
+	if v := int(align>>4) & 0x07; v < len(binaryVerticalAlignments) {
+		alignment.Vertical = binaryVerticalAlignments[v]
+	}
+	if *alignment != (xlsxAlignment{}) {
+		xf.Alignment = alignment
+	}
+	if locked, hidden := flags&0x01 != 0, flags&0x02 != 0; !locked || hidden {
+		xf.Protection = &xlsxProtection{Locked: boolPtr(locked), Hidden: boolPtr(hidden)}
+	}
+	for bit, val := range map[uint8]**bool{0x04: &xf.ApplyNumberFormat, 0x08: &xf.ApplyFont, 0x10: &xf.ApplyAlignment, 0x20: &xf.ApplyBorder, 0x40: &xf.ApplyFill, 0x80: &xf.ApplyProtection} {
+		if apply&bit != 0 {
+			*val = boolPtr(true)
+		}
+	}
+	if flags&0x04 != 0 {
+		x.styleXfs[idx] = len(x.styles.CellStyleXfs.Xf)
+		x.styles.CellStyleXfs.Xf = append(x.styles.CellStyleXfs.Xf, xf)
+		return
+	}
+	xf.XfID = intPtr(x.styleXfs[int(flags>>4)])
+	x.cellXfs[idx] = len(x.styles.CellXfs.Xf)
+	x.styles.CellXfs.Xf = append(x.styles.CellXfs.Xf, xf)
+}
+
+// color returns the color by given color index of the BIFF8 workbook.
+func (x *xlsReader) color(icv int) *xlsxColor {
+	if icv < len(xlsDefaultPalette) {
+		return &xlsxColor{RGB: "FF" + xlsDefaultPalette[icv]}
+	}
+	if icv-len(xlsDefaultPalette) < len(x.palette) {
+		return &x.palette[icv-len(xlsDefaultPalette)]
+	}
+	return &xlsxColor{Indexed: icv}
+}
+
+// addFill provides a function to add the fill by given pattern type, pattern
+// foreground and background color index, returns the index of the fill.
+func (x *xlsReader) addFill(pattern, fg, bg int) int {
+	if pattern == 0 || pattern >= len(binaryPatternTypes) {
+		return 0
+	}
+	fill := &xlsxFill{PatternFill: &xlsxPatternFill{PatternType: binaryPatternTypes[pattern], FgColor: x.color(fg), BgColor: x.color(bg)}}
+	return x.addStylePart(fill, len(x.styles.Fills.Fill), func(i int) interface{} { return x.styles.Fills.Fill[i] }, func() {
+		x.styles.Fills.Fill = append(x.styles.Fills.Fill, fill)
+	})
+}
+
+// addBorder provides a function to add the border by given border fields of
+// the XF record, returns the index of the border.
+func (x *xlsReader) addBorder(border1, border2 uint32) int {
+	border := &xlsxBorder{DiagonalDown: border1>>30&0x01 != 0, DiagonalUp: border1>>31 != 0}
+	for _, line := range []struct {
+		line       *xlsxLine
+		style, icv uint32
+	}{
+		{&border.Left, border1 & 0x0F, border1 >> 16 & 0x7F},
+		{&border.Right, border1 >> 4 & 0x0F, border1 >> 23 & 0x7F},
+		{&border.Top, border1 >> 8 & 0x0F, border2 & 0x7F},
+		{&border.Bottom, border1 >> 12 & 0x0F, border2 >> 7 & 0x7F},
+		{&border.Diagonal, border2 >> 21 & 0x0F, border2 >> 14 & 0x7F},
+	} {
+		if line.style > 0 && int(line.style) < len(binaryBorderStyles) {
+			line.line.Style, line.line.Color = binaryBorderStyles[line.style], x.color(int(line.icv))
+		}
+	}
+	if *border == (xlsxBorder{}) {
+		return 0
+	}
+	return x.addStylePart(border, len(x.styles.Borders.Border), func(i int) interface{} { return x.styles.Borders.Border[i] }, func() {
+		x.styles.Borders.Border = append(x.styles.Borders.Border, border)
+	})
+}
+
+// addStylePart provides a function to find the style part in the existing
+// style parts by given count and getter function of the style parts, the
+// style part will be added if not exists, returns the index of the part.
+func (x *xlsReader) addStylePart(part interface{}, count int, get func(i int) interface{}, add func()) int {
+	output, _ := xml.Marshal(part)
+	for i := 0; i < count; i++ {
+		if existing, _ := xml.Marshal(get(i)); bytes.Equal(output, existing) {
+			return i
+		}
+	}
+	add()
+	return count
+}
+
+// readWorksheet provides a function to read the worksheet substream by given
+// stream offset of the worksheet.
+func (x *xlsReader) readWorksheet(offset int) (*xlsxWorksheet, error) {
+	start := sort.Search(len(x.records), func(i int) bool { return x.records[i].offset >= offset })
+	if start == len(x.records) || x.records[start].offset != offset || x.records[start].id != xlsBOF {
+		return nil, ErrWorkbookFileFormat
+	}
+	var (
+		ws      = &xlsxWorksheet{}
+		rows    = map[int]*xlsxRow{}
+		refs    []binaryFormulaRef
+		expRefs [][2]int
+		pending *xlsxC
+	)
+	cell := func(rw, col, xf int) *xlsxC {
+		row, ok := rows[rw]
+		if !ok {
+			row = &xlsxRow{R: rw + 1}
+			rows[rw] = row
+		}
+		ref, _ := CoordinatesToCellName(col+1, rw+1)
+		row.C = append(row.C, xlsxC{R: ref, S: x.cellXfs[xf]})
+		return &row.C[len(row.C)-1]
+	}
+	for i := start + 1; i < len(x.records) && x.records[i].id != xlsEOF; i++ {
+		rec := x.records[i]
+		r := &binaryReader{b: rec.bytes()}
+		switch rec.id {
+		case xlsRow:
+			rw := int(r.u16())
+			r.next(4)
+			height := r.u16()
+			r.next(4)
+			flags, xf := r.u16(), int(r.u16()&0x0FFF)
+			row, ok := rows[rw]
+			if !ok {
+				row = &xlsxRow{R: rw + 1}
+				rows[rw] = row
+			}
+			row.OutlineLevel, row.Collapsed, row.Hidden = uint8(flags&0x07), flags&0x10 != 0, flags&0x20 != 0
+			if row.CustomHeight = flags&0x40 != 0; row.CustomHeight {
+				row.Ht = float64Ptr(float64(height&0x7FFF) / 20)
+			}
+			if row.CustomFormat = flags&0x80 != 0; row.CustomFormat {
+				row.S = x.cellXfs[xf]
+			}
+		case xlsColInfo:
+			first, last, width, xf, flags := int(r.u16()), int(r.u16()), float64(r.u16())/256, int(r.u16()), r.u16()
+			if ws.Cols == nil {
+				ws.Cols = &xlsxCols{}
+			}
+			ws.Cols.Col = append(ws.Cols.Col, xlsxCol{
+				Min: first + 1, Max: last + 1, Width: float64Ptr(width), Style: x.cellXfs[xf], Hidden: flags&0x01 != 0,
+				CustomWidth: flags&0x02 != 0, BestFit: flags&0x04 != 0, Phonetic: flags&0x08 != 0,
+				OutlineLevel: uint8(flags>>8) & 0x07, Collapsed: flags&0x1000 != 0,
+			})
+		case xlsBlank:
+			cell(int(r.u16()), int(r.u16()), int(r.u16()))
+		case xlsMulBlank, xlsMulRk:
+			rw, col, size := int(r.u16()), int(r.u16()), map[bool]int{true: 2, false: 6}[rec.id == xlsMulBlank]
+			for ; r.off+size+2 <= len(r.b) && r.err == nil; col++ {
+				c := cell(rw, col, int(r.u16()))
+				if rec.id == xlsMulRk {
+					c.V = formatBinaryNumber(decodeXlsbRk(r.u32()))
+				}
+			}
+		case xlsNumber:
+			cell(int(r.u16()), int(r.u16()), int(r.u16())).V = formatBinaryNumber(r.f64())
+		case xlsRk:
+			cell(int(r.u16()), int(r.u16()), int(r.u16())).V = formatBinaryNumber(decodeXlsbRk(r.u32()))
+		case xlsBoolErr:
+			c := cell(int(r.u16()), int(r.u16()), int(r.u16()))
+			if v := r.u8(); r.u8() != 0 {
+				c.T, c.V = "e", binaryErrorCodes[v]
+			} else {
+				c.T, c.V = "b", strconv.Itoa(int(v))
+			}
+		case xlsLabelSST:
+			c := cell(int(r.u16()), int(r.u16()), int(r.u16()))
+			if idx := int(r.u32()); idx < len(x.sst) {
+				c.T, c.V = "s", strconv.Itoa(idx)
+			}
+		case xlsLabel:
+			c := cell(int(r.u16()), int(r.u16()), int(r.u16()))
+			c.T, c.IS = "inlineStr", &xlsxSI{T: &xlsxT{Val: r.biff8String(int(r.u16()))}}
+		case xlsFormula:
+			rw, col := int(r.u16()), int(r.u16())
+			c := cell(rw, col, int(r.u16()))
+			if pending = nil; x.readFormulaValue(r, c) {
+				pending = c
+			}
+			r.next(6)
+			rgce := r.next(int(r.u16()))
+			if r.err == nil && len(rgce) > 0 && rgce[0] == 0x01 {
+				expRefs = append(expRefs, [2]int{rw, col})
+				break
+			}
+			if formula, err := x.formula.parse(rgce, r.b[r.off:], rw, col); err == nil {
+				c.F = &xlsxF{Content: formula}
+			}
+		case xlsString:
+			if pending != nil {
+				cr := &xlsContinueReader{segments: append([][]byte{rec.data}, rec.continues...)}
+				n := int(cr.u16())
+				if pending.V = cr.chars(n, cr.u8()&0x01 != 0); cr.err != nil {
+					return ws, cr.err
+				}
+				pending = nil
+			}
+		case xlsShrFmla, xlsArray:
+			coordinates := []int{int(r.u16()), int(r.u16()), int(r.u8()), int(r.u8())}
+			r.next(map[bool]int{true: 2, false: 6}[rec.id == xlsShrFmla])
+			rgce := r.next(int(r.u16()))
+			refs = append(refs, binaryFormulaRef{array: rec.id == xlsArray, coordinates: coordinates, rgce: rgce, rgcb: r.b[r.off:]})
+		case xlsMergeCells:
+			for j, n := 0, int(r.u16()); j < n && r.err == nil; j++ {
+				rwFirst, rwLast, colFirst, colLast := int(r.u16()), int(r.u16()), int(r.u16()), int(r.u16())
+				ref, _ := coordinatesToRangeRef([]int{colFirst + 1, rwFirst + 1, colLast + 1, rwLast + 1})
+				if ws.MergeCells == nil {
+					ws.MergeCells = &xlsxMergeCells{}
+				}
+				ws.MergeCells.Cells = append(ws.MergeCells.Cells, &xlsxMergeCell{Ref: ref})
+				ws.MergeCells.Count = len(ws.MergeCells.Cells)
+			}
+		}
+		if r.err != nil {
+			return ws, r.err
+		}
+	}
+	x.sortRows(ws, rows)
+	x.formula.resolveFormulaRefs(ws, refs, x.formulaCells(ws, expRefs))
+	return ws, nil
+}
+
+// readFormulaValue provides a function to read the cached result of the
+// FORMULA record, returns true if the result is stored in the following
+// STRING record.
+func (x *xlsReader) readFormulaValue(r *binaryReader, c *xlsxC) bool {
+	b := r.next(8)
+	if b[6] != 0xFF || b[7] != 0xFF {
+		c.V = formatBinaryNumber(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+		return false
+	}
+	switch b[0] {
+	case 0x00:
+		c.T = "str"
+		return true
+	case 0x01:
+		c.T, c.V = "b", strconv.Itoa(int(b[2]))
+	case 0x02:
+		c.T, c.V = "e", binaryErrorCodes[b[2]]
+	case 0x03:
+		c.T = "str"
+	}
+	return false
+}
+
+// sortRows provides a function to set the rows of the worksheet in order of
+// the row number, and the cells of each row in order of the column number.
+func (x *xlsReader) sortRows(ws *xlsxWorksheet, rows map[int]*xlsxRow) {
+	for _, row := range rows {
+		sort.SliceStable(row.C, func(i, j int) bool {
+			ci, _, _ := CellNameToCoordinates(row.C[i].R)
+			cj, _, _ := CellNameToCoordinates(row.C[j].R)
+			return ci < cj
+		})
+		ws.SheetData.Row = append(ws.SheetData.Row, *row)
+	}
+	sort.Slice(ws.SheetData.Row, func(i, j int) bool { return ws.SheetData.Row[i].R < ws.SheetData.Row[j].R })
+}
+
+// formulaCells returns the position of the cells which formula is stored in
+// the shared or array formula records by given zero-based row and column
+// number of the cells.
+func (x *xlsReader) formulaCells(ws *xlsxWorksheet, refs [][2]int) []binaryFormulaCell {
+	var cells []binaryFormulaCell
+	for _, ref := range refs {
+		rowIdx := sort.Search(len(ws.SheetData.Row), func(i int) bool { return ws.SheetData.Row[i].R >= ref[0]+1 })
+		if rowIdx == len(ws.SheetData.Row) {
+			continue
+		}
+		name, _ := CoordinatesToCellName(ref[1]+1, ref[0]+1)
+		for cellIdx, c := range ws.SheetData.Row[rowIdx].C {
+			if c.R == name {
+				cells = append(cells, binaryFormulaCell{row: ref[0], col: ref[1], rowIdx: rowIdx, cellIdx: cellIdx})
+			}
+		}
+	}
+	return cells
+}
+
+// writeParts provides a function to create the XML parts of the spreadsheet
+// package by the workbook globals and worksheet substreams.
+func (x *xlsReader) writeParts() (map[string][]byte, int, error) {
+	var (
+		files        = map[string][]byte{}
+		contentTypes xlsxTypes
+		wbRels       xlsxRelationships
+	)
+	_ = xml.Unmarshal([]byte(templateContentTypes), &contentTypes)
+	_ = xml.Unmarshal([]byte(templateWorkbookRels), &wbRels)
+	overrides := contentTypes.Overrides
+	contentTypes.Overrides = nil
+	for _, override := range overrides {
+		if override.ContentType != ContentTypeSpreadSheetMLWorksheet {
+			contentTypes.Overrides = append(contentTypes.Overrides, override)
+		}
+	}
+	wbRels.Relationships = wbRels.Relationships[1:]
+	for i, offset := range x.sheetOffsets {
+		ws, err := x.readWorksheet(offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		sheetXMLPath := "xl/worksheets/sheet" + strconv.Itoa(i+1) + ".xml"
+		rID := "rId" + strconv.Itoa(len(wbRels.Relationships)+2)
+		x.wb.Sheets.Sheet[i].ID = rID
+		wbRels.Relationships = append(wbRels.Relationships, xlsxRelationship{ID: rID, Type: SourceRelationshipWorkSheet, Target: strings.TrimPrefix(sheetXMLPath, "xl/")})
+		contentTypes.Overrides = append(contentTypes.Overrides, xlsxOverride{PartName: "/" + sheetXMLPath, ContentType: ContentTypeSpreadSheetMLWorksheet})
+		files[sheetXMLPath], _ = xml.Marshal(ws)
+	}
+	if len(x.sst) > 0 {
+		sst := xlsxSST{Count: len(x.sst), UniqueCount: len(x.sst)}
+		for _, s := range x.sst {
+			sst.SI = append(sst.SI, xlsxSI{T: &xlsxT{Val: s, Space: xml.Attr{Name: xml.Name{Space: NameSpaceXML, Local: "space"}, Value: "preserve"}}})
+		}
+		wbRels.Relationships = append(wbRels.Relationships, xlsxRelationship{ID: "rId" + strconv.Itoa(len(wbRels.Relationships)+2), Type: SourceRelationshipSharedStrings, Target: "sharedStrings.xml"})
+		contentTypes.Overrides = append(contentTypes.Overrides, xlsxOverride{PartName: "/" + defaultXMLPathSharedStrings, ContentType: ContentTypeSpreadSheetMLSharedStrings})
+		files[defaultXMLPathSharedStrings], _ = xml.Marshal(&sst)
+	}
+	if len(x.palette) > 0 {
+		colors := &xlsxIndexedColors{}
+		for _, rgb := range xlsDefaultPalette {
+			colors.RgbColor = append(colors.RgbColor, xlsxColor{RGB: "FF" + rgb})
+		}
+		x.styles.Colors = &xlsxStyleColors{IndexedColors: colors}
+		colors.RgbColor = append(colors.RgbColor, x.palette...)
+	}
+	x.styles.NumFmts.Count, x.styles.Fonts.Count, x.styles.Fills.Count = len(x.styles.NumFmts.NumFmt), len(x.styles.Fonts.Font), len(x.styles.Fills.Fill)
+	x.styles.Borders.Count, x.styles.CellStyleXfs.Count, x.styles.CellXfs.Count = len(x.styles.Borders.Border), len(x.styles.CellStyleXfs.Xf), len(x.styles.CellXfs.Xf)
+	x.styles.CellStyles.Count = len(x.styles.CellStyles.CellStyle)
+	if x.styles.NumFmts.Count == 0 {
+		x.styles.NumFmts = nil
+	}
+	files[defaultXMLPathStyles], _ = xml.Marshal(x.styles)
+	files[defaultXMLPathWorkbook], _ = xml.Marshal(x.wb)
+	files[defaultXMLPathWorkbookRels], _ = xml.Marshal(&wbRels)
+	files[defaultXMLPathContentTypes], _ = xml.Marshal(&contentTypes)
+	files["_rels/.rels"] = []byte(xml.Header + templateRels)
+	files[defaultXMLPathDocPropsApp] = []byte(xml.Header + templateDocpropsApp)
+	files[defaultXMLPathDocPropsCore] = []byte(xml.Header + templateDocpropsCore)
+	files[defaultXMLPathTheme] = []byte(xml.Header + templateTheme)
+	return files, len(x.sheetOffsets), nil
+}
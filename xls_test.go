@@ -0,0 +1,238 @@
+package excelize
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// xlsTestRecord returns the BIFF8 record by given record type and data.
+func xlsTestRecord(id uint16, data ...[]byte) []byte {
+	body := bytes.Join(data, nil)
+	return bytes.Join([][]byte{xlsbTestU16(id), xlsbTestU16(uint16(len(body))), body}, nil)
+}
+
+// xlsTestString returns the BIFF8 compressed Unicode string with the length
+// of the characters in 8-bit or 16-bit by given string.
+func xlsTestString(s string, wide bool) []byte {
+	n := []byte{byte(len(s))}
+	if wide {
+		n = xlsbTestU16(uint16(len(s)))
+	}
+	return append(append(n, 0), s...)
+}
+
+func xlsTestCell(rw, col, xf uint16) []byte {
+	return bytes.Join([][]byte{xlsbTestU16(rw), xlsbTestU16(col), xlsbTestU16(xf)}, nil)
+}
+
+func xlsTestFormula(rw, col, xf uint16, value []byte, rgce ...byte) []byte {
+	return xlsTestRecord(xlsFormula, xlsTestCell(rw, col, xf), value, xlsbTestU16(0), xlsbTestU32(0), xlsbTestU16(uint16(len(rgce))), rgce)
+}
+
+// prepareTestXls returns the Excel 97-2003 workbook compound file with the
+// Workbook stream for testing, the records before the sheet records in the
+// workbook globals substream can be replaced by given records.
+func prepareTestXls(globals ...[]byte) []byte {
+	bof := func(dt uint16) []byte {
+		return xlsTestRecord(xlsBOF, xlsbTestU16(0x0600), xlsbTestU16(dt), make([]byte, 12))
+	}
+	eof := xlsTestRecord(xlsEOF)
+	font := func(weight, color uint16) []byte {
+		return xlsTestRecord(xlsFont, xlsbTestU16(200), xlsbTestU16(0), xlsbTestU16(color), xlsbTestU16(weight), xlsbTestU16(0), []byte{0, 0, 0, 0}, xlsTestString("Arial", false))
+	}
+	xf := func(font, numFmt, flags uint16, apply byte, border1, border2 uint32, fill uint16) []byte {
+		return xlsTestRecord(xlsXF, xlsbTestU16(font), xlsbTestU16(numFmt), xlsbTestU16(flags), []byte{0x08, 0, 0, apply}, xlsbTestU32(border1), xlsbTestU32(border2), xlsbTestU16(fill))
+	}
+	palette := append(xlsbTestU16(56), bytes.Repeat([]byte{0, 0, 0, 0}, 56)...)
+	copy(palette[2+2*4:], []byte{0x12, 0x34, 0x56})
+	if len(globals) == 0 {
+		globals = [][]byte{
+			xlsTestRecord(xlsDateMode, xlsbTestU16(0)),
+			font(400, 0x7FFF), font(400, 0x7FFF), font(400, 0x7FFF), font(400, 0x7FFF), font(700, 10),
+			xlsTestRecord(xlsFormat, xlsbTestU16(164), xlsTestString("0.000", true)),
+			xlsTestRecord(xlsPalette, palette),
+			xf(0, 0, 0xFFF5, 0, 0, 0, 0),
+			xf(0, 0, 0x0001, 0, 0, 0, 0),
+			// Bold font, custom number format, solid fill and thin left border
+			xf(5, 164, 0x0001, 0xFC, 0x01|8<<16, 1<<26, 10|64<<7),
+		}
+	}
+	sst := bytes.Join([][]byte{
+		xlsbTestU32(3), xlsbTestU32(3), xlsTestString("Name", true), xlsTestString("Value", true),
+		xlsbTestU16(4), {0x01}, xlsbTestU16('C'), xlsbTestU16('a'),
+	}, nil)
+	sheet1 := bytes.Join([][]byte{
+		bof(0x0010),
+		xlsTestRecord(xlsColInfo, xlsbTestU16(0), xlsbTestU16(0), xlsbTestU16(20*256), xlsbTestU16(1), xlsbTestU16(0x02), xlsbTestU16(0)),
+		xlsTestRecord(xlsRow, xlsbTestU16(1), xlsbTestU16(0), xlsbTestU16(2), xlsbTestU16(400), xlsbTestU16(0), xlsbTestU16(0), xlsbTestU16(0x40), xlsbTestU16(0)),
+		xlsTestRecord(xlsLabelSST, xlsTestCell(0, 0, 1), xlsbTestU32(0)),
+		xlsTestRecord(xlsLabelSST, xlsTestCell(0, 1, 1), xlsbTestU32(1)),
+		xlsTestRecord(xlsLabelSST, xlsTestCell(1, 0, 1), xlsbTestU32(2)),
+		xlsTestRecord(xlsNumber, xlsTestCell(1, 1, 2), xlsbTestF64(1.5)),
+		xlsTestRecord(xlsMulRk, xlsbTestU16(2), xlsbTestU16(0), xlsbTestU16(1), xlsbTestU32(2<<2|0x02), xlsbTestU16(1), xlsbTestU32(1234<<2|0x03), xlsbTestU16(1)),
+		xlsTestRecord(xlsBoolErr, xlsTestCell(2, 2, 1), []byte{1, 0}),
+		xlsTestRecord(xlsBoolErr, xlsTestCell(2, 3, 1), []byte{0x07, 1}),
+		// =SUM(B2:B3)
+		xlsTestFormula(3, 1, 1, xlsbTestF64(3.5), bytes.Join([][]byte{{0x25}, xlsbTestU16(1), xlsbTestU16(2), xlsbTestU16(0xC001), xlsbTestU16(0xC001), {0x22, 1}, xlsbTestU16(4)}, nil)...),
+		// ="x"&"y"
+		xlsTestFormula(3, 2, 1, []byte{0, 0, 0, 0, 0, 0, 0xFF, 0xFF}, bytes.Join([][]byte{{0x17}, xlsTestString("x", false), {0x17}, xlsTestString("y", false), {0x08}}, nil)...),
+		xlsTestRecord(xlsString, xlsTestString("xy", true)),
+		// =TRUE
+		xlsTestFormula(3, 3, 1, []byte{1, 0, 1, 0, 0, 0, 0xFF, 0xFF}, 0x1D, 1),
+		xlsTestFormula(4, 1, 1, xlsbTestF64(7), 0x01, 4, 0, 1, 0),
+		// =B4*2 shared by B5:B6
+		xlsTestRecord(xlsShrFmla, xlsbTestU16(4), xlsbTestU16(5), []byte{1, 1, 0, 2}, xlsbTestU16(9), []byte{0x2C, 0xFF, 0xFF, 0x00, 0xC0, 0x1E, 2, 0, 0x05}),
+		xlsTestFormula(5, 1, 1, xlsbTestF64(14), 0x01, 4, 0, 1, 0),
+		xlsTestRecord(xlsMergeCells, xlsbTestU16(1), xlsbTestU16(0), xlsbTestU16(0), xlsbTestU16(2), xlsbTestU16(3)),
+		xlsTestRecord(xlsBlank, xlsTestCell(6, 0, 2)),
+		eof,
+	}, nil)
+	chart := append(bof(0x0020), eof...)
+	// =Sheet1!A2
+	sheet2 := bytes.Join([][]byte{
+		bof(0x0010),
+		xlsTestFormula(0, 0, 1, []byte{0, 0, 0, 0, 0, 0, 0xFF, 0xFF}, bytes.Join([][]byte{{0x3A}, xlsbTestU16(0), xlsbTestU16(1), xlsbTestU16(0xC000)}, nil)...),
+		xlsTestRecord(xlsString, xlsbTestU16(4), []byte{1}, xlsbTestU16('C'), xlsbTestU16('a'), xlsbTestU16('f'), xlsbTestU16(0xE9)),
+		eof,
+	}, nil)
+	boundSheet := func(offset int, state, typ byte, name string) []byte {
+		return xlsTestRecord(xlsBoundSheet, xlsbTestU32(uint32(offset)), []byte{state, typ}, xlsTestString(name, false))
+	}
+	tail := bytes.Join([][]byte{
+		xlsTestRecord(xlsSupBook, xlsbTestU16(3), xlsbTestU16(0x0401)),
+		xlsTestRecord(xlsExternSheet, xlsbTestU16(1), xlsbTestU16(0), xlsbTestU16(0), xlsbTestU16(0)),
+		// Total =Sheet1!$B$2:$B$3
+		xlsTestRecord(xlsName, xlsbTestU16(0), []byte{0, 5}, xlsbTestU16(11), xlsbTestU16(0), xlsbTestU16(0), make([]byte, 4), []byte{0}, []byte("Total"),
+			[]byte{0x3B}, xlsbTestU16(0), xlsbTestU16(1), xlsbTestU16(2), xlsbTestU16(1), xlsbTestU16(1)),
+		// _xlnm.Print_Area =Sheet1!$A$1:$B$2
+		xlsTestRecord(xlsName, xlsbTestU16(0x20), []byte{0, 1}, xlsbTestU16(11), xlsbTestU16(0), xlsbTestU16(1), make([]byte, 4), []byte{0, 0x06},
+			[]byte{0x3B}, xlsbTestU16(0), xlsbTestU16(0), xlsbTestU16(1), xlsbTestU16(0), xlsbTestU16(1)),
+		xlsTestRecord(xlsSST, sst),
+		xlsTestRecord(xlsContinue, []byte{0}, []byte("f\xe9")),
+		eof,
+	}, nil)
+	head := append(bof(0x0005), bytes.Join(globals, nil)...)
+	size := len(head) + len(boundSheet(0, 0, 0, "Sheet1")) + len(boundSheet(0, 0, 2, "Chart1")) + len(boundSheet(0, 1, 0, "Sheet 2")) + len(tail)
+	stream := bytes.Join([][]byte{
+		head,
+		boundSheet(size, 0, 0, "Sheet1"),
+		boundSheet(size+len(sheet1), 0, 2, "Chart1"),
+		boundSheet(size+len(sheet1)+len(chart), 1, 0, "Sheet 2"),
+		tail, sheet1, chart, sheet2,
+	}, nil)
+	compoundFile := &cfb{
+		paths:   []string{"Root Entry/"},
+		sectors: []sector{{name: "Root Entry", typeID: 5}},
+	}
+	compoundFile.put("Workbook", stream)
+	return compoundFile.write()
+}
+
+func TestOpenXls(t *testing.T) {
+	f, err := OpenReader(bytes.NewReader(prepareTestXls()))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Sheet 2"}, f.GetSheetList())
+	visible, err := f.GetSheetVisible("Sheet 2")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	expected := [][]string{
+		{"Name", "Value"},
+		{"Café", "1.500"},
+		{"2", "12.34", "TRUE", "#DIV/0!"},
+		{"", "3.5", "xy", "TRUE"},
+		{"", "7"},
+		{"", "14"},
+	}
+	assert.Equal(t, expected, rows)
+	for cell, formula := range map[string]string{"B4": "SUM(B2:B3)", "C4": `"x"&"y"`, "D4": "TRUE", "B5": "B4*2", "B6": "B5*2"} {
+		result, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, formula, result, cell)
+	}
+	result, err := f.GetCellValue("Sheet 2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Café", result)
+	result, err = f.GetCellFormula("Sheet 2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sheet1!A2", result)
+	result, err = f.CalcCellValue("Sheet1", "B6")
+	assert.NoError(t, err)
+	assert.Equal(t, "55.36", result)
+
+	styleID, err := f.GetCellStyle("Sheet1", "B2")
+	assert.NoError(t, err)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.000", *style.CustomNumFmt)
+	assert.True(t, style.Font.Bold)
+	assert.Equal(t, Fill{Type: "pattern", Pattern: 1, Color: []string{"123456"}}, style.Fill)
+	assert.Equal(t, []Border{{Type: "left", Color: "000000", Style: 1}}, style.Border)
+	assert.True(t, style.Alignment.WrapText)
+	width, err := f.GetColWidth("Sheet1", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	height, err := f.GetRowHeight("Sheet1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, height)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "C1:D1", mergeCells[0][0])
+	assert.Equal(t, []DefinedName{
+		{Name: "Total", RefersTo: "Sheet1!$B$2:$B$3", Scope: "Workbook"},
+		{Name: "_xlnm.Print_Area", RefersTo: "Sheet1!$A$1:$B$2", Scope: "Sheet1"},
+	}, f.GetDefinedName())
+
+	// Test save the Excel 97-2003 workbook as XLSX
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestOpenXls.xlsx")))
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestOpenXls.xlsx"))
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, rows)
+	assert.NoError(t, f.Close())
+
+	// Test open the Excel 97-2003 workbook which was written by Microsoft Excel
+	f, err = OpenFile(filepath.Join("test", "Book1.xls"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Test sheet 1", "Test sheet 2", "Sheet3"}, f.GetSheetList())
+	for sheet, expected := range map[string][][]string{
+		"Test sheet 1": {{"Test1", "Lorem", "Ipsum"}, {"Avocado", "1", "2"}, {"", "3", "5"}, {"", "4", "7"}},
+		"Test sheet 2": {{"Test2"}},
+		"Sheet3":       {},
+	} {
+		rows, err = f.GetRows(sheet)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, rows, sheet)
+	}
+	assert.NoError(t, f.Close())
+
+	// Test open the encrypted Excel 97-2003 workbook
+	_, err = OpenReader(bytes.NewReader(prepareTestXls(xlsTestRecord(xlsFilePass))))
+	assert.Equal(t, ErrWorkbookFileFormat, err)
+	// Test open the Excel 97-2003 workbook with invalid XF record
+	_, err = OpenReader(bytes.NewReader(prepareTestXls(xlsTestRecord(xlsXF))))
+	assert.Equal(t, ErrWorkbookFileFormat, err)
+	// Test open the compound file without Workbook stream
+	compoundFile := &cfb{
+		paths:   []string{"Root Entry/"},
+		sectors: []sector{{name: "Root Entry", typeID: 5}},
+	}
+	compoundFile.put("Book", []byte{0})
+	_, err = OpenReader(bytes.NewReader(compoundFile.write()))
+	assert.Equal(t, ErrWorkbookFileFormat, err)
+}
+
+func TestReadXlsRecords(t *testing.T) {
+	x := &xlsReader{}
+	assert.Equal(t, ErrWorkbookFileFormat, x.readRecords([]byte{0x09, 0x08, 0x10, 0x00}))
+	x = &xlsReader{}
+	assert.Equal(t, ErrWorkbookFileFormat, x.readRecords(xlsTestRecord(xlsBOF, xlsbTestU16(0x0500), make([]byte, 14))))
+}
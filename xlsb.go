@@ -0,0 +1,668 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"encoding/xml"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Binary workbook record types enumeration.
+const (
+	xlsbRowHdr          = 0
+	xlsbCellBlank       = 1
+	xlsbCellRk          = 2
+	xlsbCellError       = 3
+	xlsbCellBool        = 4
+	xlsbCellReal        = 5
+	xlsbCellSt          = 6
+	xlsbCellIsst        = 7
+	xlsbFmlaString      = 8
+	xlsbFmlaNum         = 9
+	xlsbFmlaBool        = 10
+	xlsbFmlaError       = 11
+	xlsbShortBlank      = 12
+	xlsbShortRk         = 13
+	xlsbShortError      = 14
+	xlsbShortBool       = 15
+	xlsbShortReal       = 16
+	xlsbShortSt         = 17
+	xlsbShortIsst       = 18
+	xlsbSSTItem         = 19
+	xlsbName            = 39
+	xlsbFont            = 43
+	xlsbFmt             = 44
+	xlsbFill            = 45
+	xlsbBorder          = 46
+	xlsbXF              = 47
+	xlsbStyle           = 48
+	xlsbColInfo         = 60
+	xlsbWbProp          = 153
+	xlsbBundleSh        = 156
+	xlsbMergeCell       = 176
+	xlsbSupSelf         = 355
+	xlsbSupSame         = 356
+	xlsbSupBookSrc      = 357
+	xlsbExternSheet     = 362
+	xlsbArrFmla         = 426
+	xlsbShrFmla         = 427
+	xlsbBeginCellXFs    = 617
+	xlsbBeginCellStyles = 626
+	xlsbSupAddin        = 667
+)
+
+// Binary workbook part content types enumeration.
+const (
+	ContentTypeXlsbMain          = "application/vnd.ms-excel.sheet.binary.macroEnabled.main"
+	ContentTypeXlsbSharedStrings = "application/vnd.ms-excel.sharedStrings"
+	ContentTypeXlsbStyles        = "application/vnd.ms-excel.styles"
+	ContentTypeXlsbWorksheet     = "application/vnd.ms-excel.worksheet"
+)
+
+// xlsbPartContentTypes defined the XML content types of the converted binary
+// workbook parts.
+var xlsbPartContentTypes = map[string]string{
+	ContentTypeXlsbMain:          ContentTypeSheetML,
+	ContentTypeXlsbSharedStrings: ContentTypeSpreadSheetMLSharedStrings,
+	ContentTypeXlsbStyles:        "application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml",
+	ContentTypeXlsbWorksheet:     ContentTypeSpreadSheetMLWorksheet,
+}
+
+// readXlsbRecords provides a function to iterate the records of the binary
+// workbook part by given callback function with record type and data.
+func readXlsbRecords(b []byte, fn func(id int, data []byte) error) error {
+	for off := 0; off < len(b); {
+		var id, size int
+		for i := 0; i < 2 && off < len(b); i++ {
+			id |= int(b[off]&0x7F) << (7 * i)
+			if off++; b[off-1]&0x80 == 0 {
+				break
+			}
+		}
+		for i := 0; i < 4 && off < len(b); i++ {
+			size |= int(b[off]&0x7F) << (7 * i)
+			if off++; b[off-1]&0x80 == 0 {
+				break
+			}
+		}
+		if off+size > len(b) {
+			return ErrWorkbookFileFormat
+		}
+		if err := fn(id, b[off:off+size]); err != nil {
+			return err
+		}
+		off += size
+	}
+	return nil
+}
+
+// decodeXlsbRk returns the number of the RK number in the binary workbook.
+func decodeXlsbRk(v uint32) float64 {
+	var n float64
+	if v&0x02 != 0 {
+		n = float64(int32(v) >> 2)
+	} else {
+		n = math.Float64frombits(uint64(v&0xFFFFFFFC) << 32)
+	}
+	if v&0x01 != 0 {
+		n /= 100
+	}
+	return n
+}
+
+// readXlsbColor returns the color by given color structure of the binary
+// workbook.
+func readXlsbColor(r *binaryReader) *xlsxColor {
+	flags, index, tint := r.u8(), r.u8(), int16(r.u16())
+	rgb := r.next(4)
+	color := &xlsxColor{Tint: float64(tint) / 32767}
+	switch flags >> 1 {
+	case 0:
+		color.Auto = true
+	case 1:
+		color.Indexed = int(index)
+	case 2:
+		color.RGB = strings.ToUpper(strconv.FormatUint(uint64(rgb[3])<<24|uint64(rgb[0])<<16|uint64(rgb[1])<<8|uint64(rgb[2]), 16))
+		color.RGB = strings.Repeat("0", 8-len(color.RGB)) + color.RGB
+	case 3:
+		color.Theme = intPtr(int(index))
+	}
+	return color
+}
+
+// xlsbReader defined the context of converting the binary workbook parts to
+// the XML parts.
+type xlsbReader struct {
+	f            *File
+	formula      binaryFormulaParser
+	partTypes    map[string]string
+	deletedParts map[string]bool
+}
+
+// readXlsb provides a function to convert the workbook, worksheets, shared
+// strings table and styles binary parts of the binary workbook (XLSB) into
+// the XML parts, so that the workbook can be read in the same way as the XLSX
+// workbook. The other binary parts which can't be converted will be removed.
+func (f *File) readXlsb() error {
+	contentTypes, err := f.contentTypesReader()
+	if err != nil {
+		return err
+	}
+	wbPath := f.getWorkbookPath()
+	x := &xlsbReader{f: f, partTypes: map[string]string{}, deletedParts: map[string]bool{}}
+	x.readPartTypes(contentTypes)
+	if x.partTypes[wbPath] != ContentTypeXlsbMain {
+		return nil
+	}
+	for part, contentType := range x.partTypes {
+		if strings.HasPrefix(contentType, "application/vnd.ms-excel.") && !strings.HasSuffix(contentType, "+xml") {
+			x.deletedParts[part] = true
+		}
+	}
+	wbRelsPath := f.getWorkbookRelsPath()
+	wbRels, err := f.relsReader(wbRelsPath)
+	if err != nil {
+		return err
+	}
+	if wbRels == nil {
+		return ErrWorkbookFileFormat
+	}
+	targets := map[string]string{}
+	for _, rel := range wbRels.Relationships {
+		if rel.TargetMode != "External" {
+			targets[rel.ID] = f.getWorksheetPath(rel.Target)
+		}
+	}
+	for _, rel := range wbRels.Relationships {
+		switch x.partTypes[targets[rel.ID]] {
+		case ContentTypeXlsbStyles:
+			err = x.convertPart(targets[rel.ID], x.readStyles)
+		case ContentTypeXlsbSharedStrings:
+			err = x.convertPart(targets[rel.ID], x.readSharedStrings)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err = x.convertPart(wbPath, x.readWorkbook); err != nil {
+		return err
+	}
+	for _, rel := range wbRels.Relationships {
+		if x.partTypes[targets[rel.ID]] == ContentTypeXlsbWorksheet {
+			if err = x.convertPart(targets[rel.ID], x.readWorksheet); err != nil {
+				return err
+			}
+		}
+	}
+	x.updateRelationships(wbRelsPath)
+	if rels, _ := f.relsReader("_rels/.rels"); rels != nil {
+		for i, rel := range rels.Relationships {
+			if rel.Type == SourceRelationshipOfficeDocument {
+				rels.Relationships[i].Target = xlsbXMLPartName(rel.Target)
+			}
+		}
+	}
+	x.updateContentTypes(contentTypes)
+	for part := range x.deletedParts {
+		f.Pkg.Delete(part)
+		if tempFile, ok := f.tempFiles.LoadAndDelete(part); ok {
+			_ = os.Remove(tempFile.(string))
+		}
+		relsPath := filepath.ToSlash(filepath.Join(filepath.Dir(part), "_rels", filepath.Base(part)+".rels"))
+		if _, ok := f.Pkg.Load(relsPath); ok {
+			if _, ok := xlsbPartContentTypes[x.partTypes[part]]; ok {
+				x.updateRelationships(relsPath)
+				rels, _ := f.Relationships.LoadAndDelete(relsPath)
+				f.Pkg.Delete(relsPath)
+				f.Relationships.Store(xlsbXMLPartName(relsPath[:len(relsPath)-5])+".rels", rels)
+				continue
+			}
+			f.Pkg.Delete(relsPath)
+			f.Relationships.Delete(relsPath)
+		}
+	}
+	return nil
+}
+
+// xlsbXMLPartName returns the XML part name of the converted binary part.
+func xlsbXMLPartName(part string) string {
+	return strings.TrimSuffix(part, filepath.Ext(part)) + ".xml"
+}
+
+// readPartTypes provides a function to read the content type of each part in
+// the package.
+func (x *xlsbReader) readPartTypes(contentTypes *xlsxTypes) {
+	defaults := map[string]string{}
+	for _, d := range contentTypes.Defaults {
+		defaults[strings.ToLower(d.Extension)] = d.ContentType
+	}
+	x.f.Pkg.Range(func(k, v interface{}) bool {
+		x.partTypes[k.(string)] = defaults[strings.ToLower(strings.TrimPrefix(filepath.Ext(k.(string)), "."))]
+		return true
+	})
+	x.f.tempFiles.Range(func(k, v interface{}) bool {
+		x.partTypes[k.(string)] = defaults[strings.ToLower(strings.TrimPrefix(filepath.Ext(k.(string)), "."))]
+		return true
+	})
+	for _, o := range contentTypes.Overrides {
+		x.partTypes[strings.TrimPrefix(o.PartName, "/")] = o.ContentType
+	}
+}
+
+// convertPart provides a function to convert the binary part to the XML part
+// by given part name and the reader function of the part.
+func (x *xlsbReader) convertPart(part string, fn func(b []byte) (interface{}, error)) error {
+	v, err := fn(x.f.readBytes(part))
+	if err != nil {
+		return err
+	}
+	output, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	x.f.Pkg.Store(xlsbXMLPartName(part), output)
+	return nil
+}
+
+// updateRelationships provides a function to update the relationships
+// targets of the converted binary parts, and remove the relationships of the
+// removed binary parts.
+func (x *xlsbReader) updateRelationships(relsPath string) {
+	rels, _ := x.f.relsReader(relsPath)
+	if rels == nil {
+		return
+	}
+	dir := filepath.Dir(filepath.Dir(relsPath))
+	var relationships []xlsxRelationship
+	for _, rel := range rels.Relationships {
+		if rel.TargetMode == "External" {
+			relationships = append(relationships, rel)
+			continue
+		}
+		target := filepath.ToSlash(filepath.Clean(filepath.Join(dir, rel.Target)))
+		if strings.HasPrefix(rel.Target, "/") {
+			target = strings.TrimPrefix(rel.Target, "/")
+		}
+		if x.deletedParts[target] {
+			if _, ok := xlsbPartContentTypes[x.partTypes[target]]; !ok {
+				continue
+			}
+			rel.Target = xlsbXMLPartName(rel.Target)
+		}
+		relationships = append(relationships, rel)
+	}
+	rels.Relationships = relationships
+}
+
+// updateContentTypes provides a function to update the content types of the
+// converted binary parts.
+func (x *xlsbReader) updateContentTypes(contentTypes *xlsxTypes) {
+	var overrides []xlsxOverride
+	for _, o := range contentTypes.Overrides {
+		if !x.deletedParts[strings.TrimPrefix(o.PartName, "/")] {
+			overrides = append(overrides, o)
+		}
+	}
+	for part := range x.deletedParts {
+		if _, ok := x.f.Pkg.Load(xlsbXMLPartName(part)); !ok {
+			continue
+		}
+		if contentType, ok := xlsbPartContentTypes[x.partTypes[part]]; ok {
+			overrides = append(overrides, xlsxOverride{PartName: "/" + xlsbXMLPartName(part), ContentType: contentType})
+		}
+	}
+	contentTypes.Overrides = overrides
+	var defaults []xlsxDefault
+	for _, d := range contentTypes.Defaults {
+		if d.ContentType != ContentTypeXlsbMain {
+			defaults = append(defaults, d)
+		}
+	}
+	contentTypes.Defaults = defaults
+}
+
+// readWorkbook provides a function to read the workbook binary part.
+func (x *xlsbReader) readWorkbook(b []byte) (interface{}, error) {
+	wb := &xlsxWorkbook{WorkbookPr: &xlsxWorkbookPr{}}
+	type definedName struct {
+		xlsxDefinedName
+		rgce, rgcb []byte
+	}
+	var (
+		definedNames []definedName
+		supBooks     int
+	)
+	x.formula.selfSupBooks = map[int]bool{}
+	err := readXlsbRecords(b, func(id int, data []byte) error {
+		r := &binaryReader{b: data}
+		switch id {
+		case xlsbWbProp:
+			wb.WorkbookPr.Date1904 = r.u32()&0x01 != 0
+		case xlsbBundleSh:
+			state, sheetID := r.u32(), int(r.u32())
+			sheet := xlsxSheet{SheetID: sheetID, ID: r.wideString(), Name: r.wideString()}
+			sheet.State = map[uint32]string{1: "hidden", 2: "veryHidden"}[state]
+			wb.Sheets.Sheet = append(wb.Sheets.Sheet, sheet)
+			x.formula.sheets = append(x.formula.sheets, sheet.Name)
+		case xlsbSupSelf, xlsbSupSame:
+			x.formula.selfSupBooks[supBooks] = true
+			supBooks++
+		case xlsbExternSheet:
+			for i, n := 0, int(r.u32()); i < n && r.err == nil; i++ {
+				x.formula.externSheets = append(x.formula.externSheets, binaryExternSheet{
+					supBook: int(r.u32()), firstSheet: int(int32(r.u32())), lastSheet: int(int32(r.u32())),
+				})
+			}
+		case xlsbName:
+			flags := r.u32()
+			r.next(1)
+			itab := r.u32()
+			name := definedName{xlsxDefinedName: xlsxDefinedName{Name: r.wideString(), Hidden: flags&0x01 != 0, Function: flags&0x02 != 0}}
+			if itab != math.MaxUint32 {
+				name.LocalSheetID = intPtr(int(itab))
+			}
+			name.rgce = r.next(int(r.u32()))
+			name.rgcb = r.next(int(r.u32()))
+			definedNames = append(definedNames, name)
+			x.formula.names = append(x.formula.names, name.Name)
+		case xlsbSupBookSrc, xlsbSupAddin:
+			supBooks++
+		}
+		return r.err
+	})
+	if err != nil {
+		return wb, err
+	}
+	for _, name := range definedNames {
+		if strings.HasPrefix(name.Name, "_xlfn.") {
+			continue
+		}
+		if name.Data, err = x.formula.parse(name.rgce, name.rgcb, 0, 0); err != nil {
+			continue
+		}
+		if wb.DefinedNames == nil {
+			wb.DefinedNames = &xlsxDefinedNames{}
+		}
+		wb.DefinedNames.DefinedName = append(wb.DefinedNames.DefinedName, name.xlsxDefinedName)
+	}
+	return wb, nil
+}
+
+// readSharedStrings provides a function to read the shared strings table
+// binary part.
+func (x *xlsbReader) readSharedStrings(b []byte) (interface{}, error) {
+	sst := &xlsxSST{}
+	err := readXlsbRecords(b, func(id int, data []byte) error {
+		if id == xlsbSSTItem {
+			r := &binaryReader{b: data}
+			r.next(1)
+			sst.SI = append(sst.SI, xlsxSI{T: &xlsxT{Val: r.wideString()}})
+			return r.err
+		}
+		return nil
+	})
+	sst.Count, sst.UniqueCount = len(sst.SI), len(sst.SI)
+	return sst, err
+}
+
+// readStyles provides a function to read the styles binary part.
+func (x *xlsbReader) readStyles(b []byte) (interface{}, error) {
+	ss := &xlsxStyleSheet{
+		NumFmts: &xlsxNumFmts{}, Fonts: &xlsxFonts{}, Fills: &xlsxFills{}, Borders: &xlsxBorders{},
+		CellStyleXfs: &xlsxCellStyleXfs{}, CellXfs: &xlsxCellXfs{}, CellStyles: &xlsxCellStyles{},
+	}
+	inCellXfs := false
+	err := readXlsbRecords(b, func(id int, data []byte) error {
+		r := &binaryReader{b: data}
+		switch id {
+		case xlsbFmt:
+			ss.NumFmts.NumFmt = append(ss.NumFmts.NumFmt, &xlsxNumFmt{NumFmtID: int(r.u16()), FormatCode: r.wideString()})
+		case xlsbFont:
+			ss.Fonts.Font = append(ss.Fonts.Font, readXlsbFont(r))
+		case xlsbFill:
+			fill := &xlsxPatternFill{PatternType: "none"}
+			if fls := int(r.u32()); fls < len(binaryPatternTypes) {
+				fill.PatternType = binaryPatternTypes[fls]
+			}
+			if fg, bg := readXlsbColor(r), readXlsbColor(r); fill.PatternType != "none" {
+				fill.FgColor, fill.BgColor = fg, bg
+			}
+			ss.Fills.Fill = append(ss.Fills.Fill, &xlsxFill{PatternFill: fill})
+		case xlsbBorder:
+			flags := r.u8()
+			border := &xlsxBorder{DiagonalDown: flags&0x01 != 0, DiagonalUp: flags&0x02 != 0}
+			for _, line := range []*xlsxLine{&border.Top, &border.Bottom, &border.Left, &border.Right, &border.Diagonal} {
+				style := int(r.u8())
+				r.next(1)
+				color := readXlsbColor(r)
+				if style > 0 && style < len(binaryBorderStyles) {
+					line.Style, line.Color = binaryBorderStyles[style], color
+				}
+			}
+			ss.Borders.Border = append(ss.Borders.Border, border)
+		case xlsbBeginCellXFs:
+			inCellXfs = true
+		case xlsbBeginCellStyles:
+			inCellXfs = false
+		case xlsbXF:
+			xf := readXlsbXf(r, inCellXfs)
+			if inCellXfs {
+				ss.CellXfs.Xf = append(ss.CellXfs.Xf, xf)
+				break
+			}
+			ss.CellStyleXfs.Xf = append(ss.CellStyleXfs.Xf, xf)
+		case xlsbStyle:
+			xfID, flags := int(r.u32()), r.u16()
+			builtInID, level := int(r.u8()), int(r.u8())
+			style := &xlsxCellStyle{XfID: xfID, Name: r.wideString()}
+			if flags&0x01 != 0 {
+				style.BuiltInID = intPtr(builtInID)
+				if builtInID == 1 || builtInID == 2 {
+					style.ILevel = intPtr(level)
+				}
+			}
+			ss.CellStyles.CellStyle = append(ss.CellStyles.CellStyle, style)
+		}
+		return r.err
+	})
+	ss.NumFmts.Count, ss.Fonts.Count, ss.Fills.Count = len(ss.NumFmts.NumFmt), len(ss.Fonts.Font), len(ss.Fills.Fill)
+	ss.Borders.Count, ss.CellStyleXfs.Count, ss.CellXfs.Count = len(ss.Borders.Border), len(ss.CellStyleXfs.Xf), len(ss.CellXfs.Xf)
+	ss.CellStyles.Count = len(ss.CellStyles.CellStyle)
+	if ss.NumFmts.Count == 0 {
+		ss.NumFmts = nil
+	}
+	return ss, err
+}
+
+// readXlsbFont returns the font by given font record of the binary workbook.
+func readXlsbFont(r *binaryReader) *xlsxFont {
+	height, flags, weight := r.u16(), r.u16(), r.u16()
+	r.next(2)
+	underline, family, charset := r.u8(), r.u8(), r.u8()
+	r.next(1)
+	font := &xlsxFont{Sz: &attrValFloat{Val: float64Ptr(float64(height) / 20)}, Color: readXlsbColor(r)}
+	scheme := r.u8()
+	font.Name = &attrValString{Val: stringPtr(r.wideString())}
+	for bit, val := range map[uint16]**attrValBool{0x02: &font.I, 0x08: &font.Strike, 0x10: &font.Outline, 0x20: &font.Shadow, 0x40: &font.Condense, 0x80: &font.Extend} {
+		if flags&bit != 0 {
+			*val = &attrValBool{Val: boolPtr(true)}
+		}
+	}
+	if weight >= 700 {
+		font.B = &attrValBool{Val: boolPtr(true)}
+	}
+	if u, ok := binaryUnderlineTypes[underline]; ok {
+		font.U = &attrValString{Val: stringPtr(u)}
+	}
+	if family > 0 {
+		font.Family = &attrValInt{Val: intPtr(int(family))}
+	}
+	if charset > 0 {
+		font.Charset = &attrValInt{Val: intPtr(int(charset))}
+	}
+	if s, ok := map[uint8]string{1: "major", 2: "minor"}[scheme]; ok {
+		font.Scheme = &attrValString{Val: stringPtr(s)}
+	}
+	return font
+}
+
+// readXlsbXf returns the cell format by given format record of the binary
+// workbook.
+func readXlsbXf(r *binaryReader, cellXf bool) xlsxXf {
+	parent, numFmt, font, fill, border := int(r.u16()), int(r.u16()), int(r.u16()), int(r.u16()), int(r.u16())
+	rotation, indent, flags, apply := int(r.u8()), int(r.u8()), r.u16(), r.u8()
+	xf := xlsxXf{NumFmtID: intPtr(numFmt), FontID: intPtr(font), FillID: intPtr(fill), BorderID: intPtr(border)}
+	if cellXf {
+		xf.XfID = intPtr(parent)
+	}
+	alignment := &xlsxAlignment{
+		TextRotation: rotation, Indent: indent, WrapText: flags&0x40 != 0,
+		JustifyLastLine: flags&0x80 != 0, ShrinkToFit: flags&0x100 != 0,
+		ReadingOrder: uint64(flags>>10) & 0x03,
+	}
+	if h := int(flags & 0x07); h < len(binaryHorizontalAlignments) {
+		alignment.Horizontal = binaryHorizontalAlignments[h]
+	}
+	if v := int(flags>>3) & 0x07; v < len(binaryVerticalAlignments) {
+		alignment.Vertical = binaryVerticalAlignments[v]
+	}
+	if *alignment != (xlsxAlignment{}) {
+		xf.Alignment = alignment
+	}
+	if locked, hidden := flags&0x1000 != 0, flags&0x2000 != 0; !locked || hidden {
+		xf.Protection = &xlsxProtection{Locked: boolPtr(locked), Hidden: boolPtr(hidden)}
+	}
+	if flags&0x8000 != 0 {
+		xf.QuotePrefix = boolPtr(true)
+	}
+	for bit, val := range map[uint8]**bool{0x01: &xf.ApplyNumberFormat, 0x02: &xf.ApplyFont, 0x04: &xf.ApplyAlignment, 0x08: &xf.ApplyBorder, 0x10: &xf.ApplyFill, 0x20: &xf.ApplyProtection} {
+		if apply&bit != 0 {
+			*val = boolPtr(true)
+		}
+	}
+	return xf
+}
+
+// readWorksheet provides a function to read the worksheet binary part.
+func (x *xlsbReader) readWorksheet(b []byte) (interface{}, error) {
+	var (
+		ws          = &xlsxWorksheet{}
+		col         int
+		expCells    []binaryFormulaRef
+		sharedCells []binaryFormulaCell
+	)
+	err := readXlsbRecords(b, func(id int, data []byte) error {
+		r := &binaryReader{b: data}
+		switch {
+		case id == xlsbRowHdr:
+			rw, style, height := int(r.u32()), int(r.u32()), float64(r.u16())/20
+			r.next(1)
+			flags := r.u8()
+			row := xlsxRow{R: rw + 1, S: style, Ht: &height, OutlineLevel: flags & 0x07, Collapsed: flags&0x08 != 0,
+				Hidden: flags&0x10 != 0, CustomHeight: flags&0x20 != 0, CustomFormat: flags&0x40 != 0}
+			if !row.CustomFormat {
+				row.S = 0
+			}
+			ws.SheetData.Row, col = append(ws.SheetData.Row, row), -1
+		case id >= xlsbCellBlank && id <= xlsbFmlaError || id >= xlsbShortBlank && id <= xlsbShortIsst:
+			if len(ws.SheetData.Row) == 0 {
+				return ErrWorkbookFileFormat
+			}
+			if col++; id < xlsbShortBlank {
+				col = int(r.u32())
+			}
+			row := &ws.SheetData.Row[len(ws.SheetData.Row)-1]
+			ref, _ := CoordinatesToCellName(col+1, row.R)
+			c := xlsxC{R: ref, S: int(r.u32() & 0xFFFFFF)}
+			if exp := x.readCell(r, id, &c, row.R-1, col); exp != nil {
+				sharedCells = append(sharedCells, binaryFormulaCell{row: row.R - 1, col: col, rowIdx: len(ws.SheetData.Row) - 1, cellIdx: len(row.C)})
+			}
+			row.C = append(row.C, c)
+		case id == xlsbColInfo:
+			first, last, width, style, flags := int(r.u32()), int(r.u32()), float64(r.u32())/256, int(r.u32()), r.u16()
+			if ws.Cols == nil {
+				ws.Cols = &xlsxCols{}
+			}
+			ws.Cols.Col = append(ws.Cols.Col, xlsxCol{
+				Min: first + 1, Max: last + 1, Width: &width, Style: style, Hidden: flags&0x01 != 0,
+				CustomWidth: flags&0x02 != 0, BestFit: flags&0x04 != 0, Phonetic: flags&0x08 != 0,
+				OutlineLevel: uint8(flags>>8) & 0x07, Collapsed: flags&0x1000 != 0,
+			})
+		case id == xlsbMergeCell:
+			ref := readXlsbRef(r)
+			if ws.MergeCells == nil {
+				ws.MergeCells = &xlsxMergeCells{}
+			}
+			ws.MergeCells.Cells = append(ws.MergeCells.Cells, &xlsxMergeCell{Ref: ref})
+			ws.MergeCells.Count = len(ws.MergeCells.Cells)
+		case id == xlsbShrFmla, id == xlsbArrFmla:
+			coordinates := []int{int(r.u32()), int(r.u32()), int(r.u32()), int(r.u32())}
+			if id == xlsbArrFmla {
+				r.next(1)
+			}
+			expCells = append(expCells, binaryFormulaRef{array: id == xlsbArrFmla, coordinates: coordinates, rgce: r.next(int(r.u32())), rgcb: r.next(int(r.u32()))})
+		}
+		return r.err
+	})
+	x.formula.resolveFormulaRefs(ws, expCells, sharedCells)
+	return ws, err
+}
+
+// readXlsbRef returns the range reference by given range structure of the
+// binary workbook.
+func readXlsbRef(r *binaryReader) string {
+	rwFirst, rwLast, colFirst, colLast := int(r.u32()), int(r.u32()), int(r.u32()), int(r.u32())
+	ref, _ := coordinatesToRangeRef([]int{colFirst + 1, rwFirst + 1, colLast + 1, rwLast + 1})
+	return ref
+}
+
+// readCell provides a function to read value and formula of the cell record,
+// returns the formula tokens if the formula of the cell is stored in the
+// shared or array formula record.
+func (x *xlsbReader) readCell(r *binaryReader, id int, c *xlsxC, row, col int) []byte {
+	switch id {
+	case xlsbCellRk, xlsbShortRk:
+		c.V = formatBinaryNumber(decodeXlsbRk(r.u32()))
+	case xlsbCellError, xlsbShortError, xlsbFmlaError:
+		c.T, c.V = "e", binaryErrorCodes[r.u8()]
+	case xlsbCellBool, xlsbShortBool, xlsbFmlaBool:
+		c.T, c.V = "b", map[bool]string{true: "1", false: "0"}[r.u8() != 0]
+	case xlsbCellReal, xlsbShortReal, xlsbFmlaNum:
+		c.V = formatBinaryNumber(r.f64())
+	case xlsbCellSt, xlsbShortSt:
+		c.T, c.IS = "inlineStr", &xlsxSI{T: &xlsxT{Val: r.wideString()}}
+	case xlsbCellIsst, xlsbShortIsst:
+		c.T, c.V = "s", strconv.Itoa(int(r.u32()))
+	case xlsbFmlaString:
+		c.T, c.V = "str", r.wideString()
+	}
+	if id < xlsbFmlaString || id > xlsbFmlaError {
+		return nil
+	}
+	r.next(2)
+	rgce := r.next(int(r.u32()))
+	rgcb := r.next(int(r.u32()))
+	if r.err != nil {
+		return nil
+	}
+	if len(rgce) > 0 && rgce[0] == 0x01 {
+		return rgce
+	}
+	if formula, err := x.formula.parse(rgce, rgcb, row, col); err == nil {
+		c.F = &xlsxF{Content: formula}
+	}
+	return nil
+}
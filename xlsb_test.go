@@ -0,0 +1,270 @@
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"math"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// xlsbTestRecord returns the binary record by given record type and data.
+func xlsbTestRecord(id int, data ...[]byte) []byte {
+	var b []byte
+	for ; ; id >>= 7 {
+		if id < 0x80 {
+			b = append(b, byte(id))
+			break
+		}
+		b = append(b, byte(id&0x7F|0x80))
+	}
+	body := bytes.Join(data, nil)
+	for size := len(body); ; size >>= 7 {
+		if size < 0x80 {
+			b = append(b, byte(size))
+			break
+		}
+		b = append(b, byte(size&0x7F|0x80))
+	}
+	return append(b, body...)
+}
+
+func xlsbTestU8(v uint8) []byte { return []byte{v} }
+
+func xlsbTestU16(v uint16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, v)
+	return b
+}
+
+func xlsbTestU32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
+
+func xlsbTestF64(v float64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, math.Float64bits(v))
+	return b
+}
+
+func xlsbTestString(s string) []byte {
+	u := utf16.Encode([]rune(s))
+	b := xlsbTestU32(uint32(len(u)))
+	for _, c := range u {
+		b = append(b, xlsbTestU16(c)...)
+	}
+	return b
+}
+
+func xlsbTestFormula(rgce []byte, rgcb ...byte) []byte {
+	return bytes.Join([][]byte{xlsbTestU32(uint32(len(rgce))), rgce, xlsbTestU32(uint32(len(rgcb))), rgcb}, nil)
+}
+
+func xlsbTestCell(col, style uint32) []byte {
+	return append(xlsbTestU32(col), xlsbTestU32(style)...)
+}
+
+func xlsbTestRow(rw uint32) []byte {
+	return xlsbTestRecord(xlsbRowHdr, xlsbTestU32(rw), xlsbTestU32(0), xlsbTestU16(300), xlsbTestU8(0), xlsbTestU8(0), xlsbTestU8(0))
+}
+
+// prepareTestXlsb returns the binary workbook with the given workbook
+// binary part for testing.
+func prepareTestXlsb(t *testing.T, workbook []byte) []byte {
+	rgb := func(r, g, b byte) []byte { return []byte{0x05, 0xFF, 0, 0, r, g, b, 0xFF} }
+	font := func(bold uint16, color []byte, name string) []byte {
+		return bytes.Join([][]byte{
+			xlsbTestU16(220), xlsbTestU16(0), xlsbTestU16(bold), xlsbTestU16(0), {0, 2, 0, 0},
+			color, xlsbTestU8(2), xlsbTestString(name),
+		}, nil)
+	}
+	xf := func(numFmt, font uint16, apply uint8) []byte {
+		return bytes.Join([][]byte{
+			xlsbTestU16(0), xlsbTestU16(numFmt), xlsbTestU16(font), xlsbTestU16(0), xlsbTestU16(0),
+			{0, 0}, xlsbTestU16(0x1000 | 0x40), {apply, 0},
+		}, nil)
+	}
+	noBorder := bytes.Repeat(append([]byte{0, 0}, make([]byte, 8)...), 5)
+	styles := bytes.Join([][]byte{
+		xlsbTestRecord(xlsbFmt, xlsbTestU16(164), xlsbTestString("0.000")),
+		xlsbTestRecord(xlsbFont, font(400, make([]byte, 8), "Calibri")),
+		xlsbTestRecord(xlsbFont, font(700, rgb(0xFF, 0, 0), "Arial")),
+		xlsbTestRecord(xlsbFill, xlsbTestU32(0), make([]byte, 16)),
+		xlsbTestRecord(xlsbFill, xlsbTestU32(17), make([]byte, 16)),
+		xlsbTestRecord(xlsbBorder, append([]byte{0}, noBorder...)),
+		xlsbTestRecord(xlsbBeginCellStyles),
+		xlsbTestRecord(xlsbXF, xf(0, 0, 0)),
+		xlsbTestRecord(xlsbBeginCellXFs),
+		xlsbTestRecord(xlsbXF, xf(0, 0, 0)),
+		xlsbTestRecord(xlsbXF, xf(10, 1, 0x03)),
+		xlsbTestRecord(xlsbXF, xf(164, 0, 0x01)),
+		xlsbTestRecord(xlsbStyle, xlsbTestU32(0), xlsbTestU16(1), xlsbTestU8(0), xlsbTestU8(0), xlsbTestString("Normal")),
+	}, nil)
+	sst := bytes.Join([][]byte{
+		xlsbTestRecord(xlsbSSTItem, xlsbTestU8(0), xlsbTestString("Name")),
+		xlsbTestRecord(xlsbSSTItem, xlsbTestU8(0), xlsbTestString("Value")),
+	}, nil)
+	sheet1 := bytes.Join([][]byte{
+		xlsbTestRecord(xlsbColInfo, xlsbTestU32(0), xlsbTestU32(0), xlsbTestU32(20*256), xlsbTestU32(0), xlsbTestU16(0x02)),
+		xlsbTestRow(0),
+		xlsbTestRecord(xlsbCellIsst, xlsbTestCell(0, 0), xlsbTestU32(0)),
+		xlsbTestRecord(xlsbShortIsst, xlsbTestU32(0), xlsbTestU32(1)),
+		xlsbTestRow(1),
+		xlsbTestRecord(xlsbCellSt, xlsbTestCell(0, 0), xlsbTestString("A")),
+		xlsbTestRecord(xlsbCellReal, xlsbTestCell(1, 1), xlsbTestF64(1.5)),
+		xlsbTestRow(2),
+		xlsbTestRecord(xlsbCellSt, xlsbTestCell(0, 0), xlsbTestString("B")),
+		xlsbTestRecord(xlsbShortRk, xlsbTestU32(0), xlsbTestU32(2<<2|0x02)),
+		xlsbTestRecord(xlsbShortRk, xlsbTestU32(2), xlsbTestU32(1234<<2|0x03)),
+		xlsbTestRow(3),
+		// =SUM(B2:B3)
+		xlsbTestRecord(xlsbFmlaNum, xlsbTestCell(1, 0), xlsbTestF64(3.5), xlsbTestU16(0), xlsbTestFormula(
+			bytes.Join([][]byte{{0x25}, xlsbTestU32(1), xlsbTestU32(2), xlsbTestU16(0xC001), xlsbTestU16(0xC001), {0x22, 1}, xlsbTestU16(4)}, nil))),
+		xlsbTestRecord(xlsbShortError, xlsbTestU32(0), xlsbTestU8(0x07)),
+		// ="x"&"y"
+		xlsbTestRecord(xlsbFmlaString, xlsbTestCell(3, 0), xlsbTestString("xy"), xlsbTestU16(0), xlsbTestFormula(
+			bytes.Join([][]byte{{0x17}, xlsbTestU16(1), xlsbTestU16('x'), {0x17}, xlsbTestU16(1), xlsbTestU16('y'), {0x08}}, nil))),
+		xlsbTestRecord(xlsbCellBool, xlsbTestCell(4, 0), xlsbTestU8(1)),
+		xlsbTestRow(4),
+		xlsbTestRecord(xlsbFmlaNum, xlsbTestCell(1, 0), xlsbTestF64(7), xlsbTestU16(0), xlsbTestFormula(append([]byte{0x01}, xlsbTestU32(4)...))),
+		// =B4*2 shared by B5:B6
+		xlsbTestRecord(xlsbShrFmla, xlsbTestU32(4), xlsbTestU32(5), xlsbTestU32(1), xlsbTestU32(1), xlsbTestFormula(
+			bytes.Join([][]byte{{0x2C}, xlsbTestU32(math.MaxUint32), xlsbTestU16(0xC000), {0x1E}, xlsbTestU16(2), {0x05}}, nil))),
+		xlsbTestRow(5),
+		xlsbTestRecord(xlsbFmlaNum, xlsbTestCell(1, 0), xlsbTestF64(14), xlsbTestU16(0), xlsbTestFormula(append([]byte{0x01}, xlsbTestU32(4)...))),
+		xlsbTestRecord(xlsbMergeCell, xlsbTestU32(0), xlsbTestU32(0), xlsbTestU32(2), xlsbTestU32(3)),
+	}, nil)
+	// =Sheet1!A2
+	sheet2 := bytes.Join([][]byte{
+		xlsbTestRow(0),
+		xlsbTestRecord(xlsbFmlaString, xlsbTestCell(0, 0), xlsbTestString("A"), xlsbTestU16(0), xlsbTestFormula(
+			bytes.Join([][]byte{{0x3A}, xlsbTestU16(0), xlsbTestU32(1), xlsbTestU16(0xC000)}, nil))),
+	}, nil)
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, content := range map[string][]byte{
+		"[Content_Types].xml":                     []byte(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="bin" ContentType="application/vnd.ms-excel.sheet.binary.macroEnabled.main"/><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/worksheets/sheet1.bin" ContentType="application/vnd.ms-excel.worksheet"/><Override PartName="/xl/worksheets/sheet2.bin" ContentType="application/vnd.ms-excel.worksheet"/><Override PartName="/xl/sharedStrings.bin" ContentType="application/vnd.ms-excel.sharedStrings"/><Override PartName="/xl/styles.bin" ContentType="application/vnd.ms-excel.styles"/><Override PartName="/xl/calcChain.bin" ContentType="application/vnd.ms-excel.calcChain"/><Override PartName="/xl/printerSettings/printerSettings1.bin" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.printerSettings"/></Types>`),
+		"_rels/.rels":                             []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.bin"/></Relationships>`),
+		"xl/_rels/workbook.bin.rels":              []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.bin"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet2.bin"/><Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.bin"/><Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.bin"/><Relationship Id="rId5" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/calcChain" Target="calcChain.bin"/></Relationships>`),
+		"xl/worksheets/_rels/sheet1.bin.rels":     []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/printerSettings" Target="../printerSettings/printerSettings1.bin"/></Relationships>`),
+		"xl/workbook.bin":                         workbook,
+		"xl/worksheets/sheet1.bin":                sheet1,
+		"xl/worksheets/sheet2.bin":                sheet2,
+		"xl/sharedStrings.bin":                    sst,
+		"xl/styles.bin":                           styles,
+		"xl/calcChain.bin":                        {},
+		"xl/printerSettings/printerSettings1.bin": {0},
+	} {
+		fw, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = fw.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// prepareTestXlsbWorkbook returns the workbook binary part for testing.
+func prepareTestXlsbWorkbook() []byte {
+	return bytes.Join([][]byte{
+		xlsbTestRecord(xlsbWbProp, xlsbTestU32(0), xlsbTestU32(0), xlsbTestString("")),
+		xlsbTestRecord(xlsbBundleSh, xlsbTestU32(0), xlsbTestU32(1), xlsbTestString("rId1"), xlsbTestString("Sheet1")),
+		xlsbTestRecord(xlsbBundleSh, xlsbTestU32(1), xlsbTestU32(2), xlsbTestString("rId2"), xlsbTestString("Sheet2")),
+		xlsbTestRecord(xlsbSupSelf),
+		xlsbTestRecord(xlsbExternSheet, xlsbTestU32(1), xlsbTestU32(0), xlsbTestU32(0), xlsbTestU32(0)),
+		// Total =Sheet1!$B$2:$B$3
+		xlsbTestRecord(xlsbName, xlsbTestU32(0), xlsbTestU8(0), xlsbTestU32(math.MaxUint32), xlsbTestString("Total"), xlsbTestFormula(
+			bytes.Join([][]byte{{0x3B}, xlsbTestU16(0), xlsbTestU32(1), xlsbTestU32(2), xlsbTestU16(1), xlsbTestU16(1)}, nil))),
+		// Unsupported formula will be skipped
+		xlsbTestRecord(xlsbName, xlsbTestU32(0), xlsbTestU8(0), xlsbTestU32(0), xlsbTestString("Invalid"), xlsbTestFormula([]byte{0xFF})),
+	}, nil)
+}
+
+func TestOpenXlsb(t *testing.T) {
+	f, err := OpenReader(bytes.NewReader(prepareTestXlsb(t, prepareTestXlsbWorkbook())))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Sheet2"}, f.GetSheetList())
+	visible, err := f.GetSheetVisible("Sheet2")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	expected := [][]string{
+		{"Name", "Value"},
+		{"A", "150.00%"},
+		{"B", "2", "12.340"},
+		{"", "3.5", "#DIV/0!", "xy", "TRUE"},
+		{"", "7"},
+		{"", "14"},
+	}
+	assert.Equal(t, expected, rows)
+	for cell, formula := range map[string]string{"B4": "SUM(B2:B3)", "D4": `"x"&"y"`, "B5": "B4*2", "B6": "B5*2"} {
+		result, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, formula, result, cell)
+	}
+	result, err := f.CalcCellValue("Sheet1", "B6")
+	assert.NoError(t, err)
+	assert.Equal(t, "14", result)
+	result, err = f.CalcCellValue("Sheet2", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "A", result)
+
+	styleID, err := f.GetCellStyle("Sheet1", "B2")
+	assert.NoError(t, err)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, style.NumFmt)
+	assert.True(t, style.Font.Bold)
+	assert.Equal(t, "Arial", style.Font.Family)
+	assert.Equal(t, "FF0000", style.Font.Color)
+	assert.True(t, style.Alignment.WrapText)
+	width, err := f.GetColWidth("Sheet1", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "C1:D1", mergeCells[0][0])
+	assert.Equal(t, []DefinedName{{Name: "Total", RefersTo: "Sheet1!$B$2:$B$3", Scope: "Workbook"}}, f.GetDefinedName())
+
+	// Test save the binary workbook as XLSX
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestOpenXlsb.xlsx")))
+	assert.NoError(t, f.Close())
+	f, err = OpenFile(filepath.Join("test", "TestOpenXlsb.xlsx"))
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, rows)
+	for _, part := range []string{"xl/workbook.bin", "xl/calcChain.bin", "xl/worksheets/sheet1.bin"} {
+		_, ok := f.Pkg.Load(part)
+		assert.False(t, ok, part)
+	}
+	_, ok := f.Pkg.Load("xl/printerSettings/printerSettings1.bin")
+	assert.True(t, ok)
+	rels, err := f.relsReader("xl/worksheets/_rels/sheet1.xml.rels")
+	assert.NoError(t, err)
+	assert.Len(t, rels.Relationships, 1)
+	assert.NoError(t, f.Close())
+
+	// Test open the binary workbook with invalid record size
+	_, err = OpenReader(bytes.NewReader(prepareTestXlsb(t, []byte{xlsbBundleSh | 0x80, 0x01, 0x7F})))
+	assert.Equal(t, ErrWorkbookFileFormat, err)
+	// Test open the binary workbook with truncated record
+	_, err = OpenReader(bytes.NewReader(prepareTestXlsb(t, xlsbTestRecord(xlsbBundleSh, xlsbTestU32(0)))))
+	assert.Equal(t, ErrWorkbookFileFormat, err)
+}
+
+func TestDecodeXlsbRk(t *testing.T) {
+	assert.Equal(t, 2.0, decodeXlsbRk(2<<2|0x02))
+	assert.Equal(t, -0.05, decodeXlsbRk(0xFFFFFFEC|0x03))
+	assert.Equal(t, 1.5, decodeXlsbRk(uint32(math.Float64bits(1.5)>>32)))
+	assert.Equal(t, 0.015, decodeXlsbRk(uint32(math.Float64bits(1.5)>>32)|0x01))
+}
@@ -0,0 +1,852 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Source namespaces of the XML Spreadsheet 2003 (SpreadsheetML 2003).
+const (
+	NameSpaceSpreadSheetML2003      = "urn:schemas-microsoft-com:office:spreadsheet"
+	NameSpaceSpreadSheetML2003Excel = "urn:schemas-microsoft-com:office:excel"
+)
+
+var (
+	// xml2003RefExp defined the regular expression to match the R1C1
+	// reference style cell, row and column references in the formulas.
+	xml2003RefExp = regexp.MustCompile(`R(\[-?\d+\]|\d+)?C(\[-?\d+\]|\d+)?|R(\[-?\d+\]|\d+)?|C(\[-?\d+\]|\d+)?`)
+	// xml2003NumFmts defined the named number formats in the XML Spreadsheet
+	// 2003 and the corresponding number format codes.
+	xml2003NumFmts = map[string]string{
+		"General":        "General",
+		"General Number": "General",
+		"General Date":   "m/d/yyyy h:mm",
+		"Short Date":     "m/d/yyyy",
+		"Medium Date":    "d-mmm-yy",
+		"Long Date":      "dddd, mmmm d, yyyy",
+		"Short Time":     "h:mm",
+		"Medium Time":    "h:mm AM/PM",
+		"Long Time":      "h:mm:ss AM/PM",
+		"Fixed":          "0.00",
+		"Standard":       "#,##0.00",
+		"Percent":        "0.00%",
+		"Scientific":     "0.00E+00",
+		"Currency":       `"$"#,##0.00_);\("$"#,##0.00\)`,
+		"Euro Currency":  `[$€-2] #,##0.00`,
+		"Yes/No":         `"Yes";"Yes";"No"`,
+		"True/False":     `"True";"True";"False"`,
+		"On/Off":         `"On";"On";"Off"`,
+	}
+	// xml2003BorderStyles defined the border line styles and weights in the
+	// XML Spreadsheet 2003 and the corresponding border style index.
+	xml2003BorderStyles = map[string][]int{
+		"Continuous":   {7, 1, 2, 5},
+		"Dash":         {3, 3, 8, 8},
+		"Dot":          {4, 4, 4, 4},
+		"DashDot":      {9, 9, 10, 10},
+		"DashDotDot":   {11, 11, 12, 12},
+		"SlantDashDot": {13, 13, 13, 13},
+		"Double":       {6, 6, 6, 6},
+	}
+	// xml2003FillPatterns defined the interior patterns in the XML Spreadsheet
+	// 2003 and the corresponding fill pattern index.
+	xml2003FillPatterns = map[string]int{
+		"Solid": 1, "Gray50": 2, "Gray75": 3, "Gray25": 4, "HorzStripe": 5,
+		"VertStripe": 6, "ReverseDiagStripe": 7, "DiagStripe": 8, "DiagCross": 9,
+		"ThickDiagCross": 10, "ThinHorzStripe": 11, "ThinVertStripe": 12,
+		"ThinReverseDiagStripe": 13, "ThinDiagStripe": 14, "ThinHorzCross": 15,
+		"ThinDiagCross": 16, "Gray125": 17, "Gray0625": 18,
+	}
+)
+
+// xml2003Text directly maps the text content of the element, the rich text
+// formatting elements in the content will be ignored.
+type xml2003Text string
+
+// UnmarshalXML implements the xml.Unmarshaler interface to collect the text
+// content of the element and the nested elements.
+func (t *xml2003Text) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var buf strings.Builder
+	for depth := 1; depth > 0; {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch v := token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			buf.Write(v)
+		}
+	}
+	*t = xml2003Text(buf.String())
+	return nil
+}
+
+// xml2003Data directly maps the Data element of the cell in the XML
+// Spreadsheet 2003.
+type xml2003Data struct {
+	Type string
+	Text xml2003Text
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface to get the data type
+// and the text content of the cell.
+func (data *xml2003Data) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "Type" {
+			data.Type = attr.Value
+		}
+	}
+	return data.Text.UnmarshalXML(d, start)
+}
+
+// xml2003Style directly maps the Style element in the XML Spreadsheet 2003.
+type xml2003Style struct {
+	ID        string `xml:"ID,attr"`
+	Parent    string `xml:"Parent,attr"`
+	Alignment *struct {
+		Horizontal   string `xml:"Horizontal,attr"`
+		Vertical     string `xml:"Vertical,attr"`
+		WrapText     string `xml:"WrapText,attr"`
+		ShrinkToFit  string `xml:"ShrinkToFit,attr"`
+		VerticalText string `xml:"VerticalText,attr"`
+		Rotate       string `xml:"Rotate,attr"`
+		Indent       string `xml:"Indent,attr"`
+	} `xml:"Alignment"`
+	Borders []struct {
+		Position  string `xml:"Position,attr"`
+		LineStyle string `xml:"LineStyle,attr"`
+		Weight    string `xml:"Weight,attr"`
+		Color     string `xml:"Color,attr"`
+	} `xml:"Borders>Border"`
+	Font *struct {
+		FontName      string `xml:"FontName,attr"`
+		Size          string `xml:"Size,attr"`
+		Color         string `xml:"Color,attr"`
+		Bold          string `xml:"Bold,attr"`
+		Italic        string `xml:"Italic,attr"`
+		Underline     string `xml:"Underline,attr"`
+		StrikeThrough string `xml:"StrikeThrough,attr"`
+		VerticalAlign string `xml:"VerticalAlign,attr"`
+	} `xml:"Font"`
+	Interior *struct {
+		Color        string `xml:"Color,attr"`
+		Pattern      string `xml:"Pattern,attr"`
+		PatternColor string `xml:"PatternColor,attr"`
+	} `xml:"Interior"`
+	NumberFormat *struct {
+		Format string `xml:"Format,attr"`
+	} `xml:"NumberFormat"`
+	Protection *struct {
+		Protected   string `xml:"Protected,attr"`
+		HideFormula string `xml:"HideFormula,attr"`
+	} `xml:"Protection"`
+}
+
+// xml2003NamedRange directly maps the NamedRange element in the XML
+// Spreadsheet 2003.
+type xml2003NamedRange struct {
+	Name      string `xml:"Name,attr"`
+	RefersTo  string `xml:"RefersTo,attr"`
+	localName string
+}
+
+// xml2003Column directly maps the Column element in the XML Spreadsheet 2003.
+type xml2003Column struct {
+	Index   int     `xml:"Index,attr"`
+	Span    int     `xml:"Span,attr"`
+	Width   float64 `xml:"Width,attr"`
+	Hidden  string  `xml:"Hidden,attr"`
+	StyleID string  `xml:"StyleID,attr"`
+}
+
+// xml2003Row directly maps the Row element in the XML Spreadsheet 2003.
+type xml2003Row struct {
+	Index   int     `xml:"Index,attr"`
+	Span    int     `xml:"Span,attr"`
+	Height  float64 `xml:"Height,attr"`
+	Hidden  string  `xml:"Hidden,attr"`
+	StyleID string  `xml:"StyleID,attr"`
+	Cells   []struct {
+		Index       int          `xml:"Index,attr"`
+		MergeAcross int          `xml:"MergeAcross,attr"`
+		MergeDown   int          `xml:"MergeDown,attr"`
+		StyleID     string       `xml:"StyleID,attr"`
+		Formula     string       `xml:"Formula,attr"`
+		HRef        string       `xml:"HRef,attr"`
+		ScreenTip   string       `xml:"HRefScreenTip,attr"`
+		Data        *xml2003Data `xml:"Data"`
+		Comment     *struct {
+			Author string      `xml:"Author,attr"`
+			Data   xml2003Text `xml:"Data"`
+		} `xml:"Comment"`
+	} `xml:"Cell"`
+}
+
+// xml2003Reader defined the reader for the XML Spreadsheet 2003, which maps
+// the worksheets, cells, styles, merged cells and named ranges onto the
+// workbook.
+type xml2003Reader struct {
+	f        *File
+	sheet    string
+	sheets   int
+	row, col int
+	styles   map[string]*xml2003Style
+	styleIDs map[string]int
+	names    []xml2003NamedRange
+	active   int
+	inSheet  bool
+}
+
+// openXML2003 provides a function to create the workbook by given XML
+// Spreadsheet 2003 document content.
+func openXML2003(content []byte, opts Options) (*File, error) {
+	r := &xml2003Reader{
+		f:        NewFile(opts),
+		styles:   make(map[string]*xml2003Style),
+		styleIDs: make(map[string]int),
+	}
+	d := r.f.xmlNewDecoder(bytes.NewReader(content))
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ee, ok := token.(xml.EndElement); ok && ee.Name.Local == "Worksheet" {
+			r.inSheet = false
+		}
+		se, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch se.Name.Local {
+		case "Style":
+			style := &xml2003Style{}
+			if err = d.DecodeElement(style, &se); err != nil {
+				return nil, err
+			}
+			r.styles[style.ID] = style
+		case "NamedRange":
+			var name xml2003NamedRange
+			if err = d.DecodeElement(&name, &se); err != nil {
+				return nil, err
+			}
+			if r.inSheet {
+				name.localName = r.sheet
+			}
+			r.names = append(r.names, name)
+		case "Worksheet":
+			if err = r.readWorksheet(se); err != nil {
+				return nil, err
+			}
+		case "Table":
+			if err = r.setSheetProps(se); err != nil {
+				return nil, err
+			}
+		case "Column":
+			var col xml2003Column
+			if err = d.DecodeElement(&col, &se); err != nil {
+				return nil, err
+			}
+			if err = r.setColumn(col); err != nil {
+				return nil, err
+			}
+		case "Row":
+			var row xml2003Row
+			if err = d.DecodeElement(&row, &se); err != nil {
+				return nil, err
+			}
+			if err = r.setRow(row); err != nil {
+				return nil, err
+			}
+		case "Visible":
+			var visible string
+			if err = d.DecodeElement(&visible, &se); err != nil {
+				return nil, err
+			}
+			if visible == "SheetHidden" || visible == "SheetVeryHidden" {
+				if err = r.f.SetSheetVisible(r.sheet, false, visible == "SheetVeryHidden"); err != nil {
+					return nil, err
+				}
+			}
+		case "Selected":
+			r.active = r.sheets - 1
+		}
+	}
+	if r.sheets == 0 {
+		return nil, ErrWorkbookFileFormat
+	}
+	r.f.SetActiveSheet(r.active)
+	return r.f, r.setDefinedNames()
+}
+
+// readWorksheet provides a function to create the worksheet by given
+// Worksheet element.
+func (r *xml2003Reader) readWorksheet(se xml.StartElement) error {
+	name := "Sheet" + strconv.Itoa(r.sheets+1)
+	for _, attr := range se.Attr {
+		if attr.Name.Local == "Name" && attr.Value != "" {
+			name = attr.Value
+		}
+	}
+	r.row, r.col, r.inSheet = 0, 0, true
+	r.sheets++
+	if r.sheets == 1 {
+		if font := r.cellFont("Default"); font != nil && font.Family != "" {
+			if err := r.f.SetDefaultFont(font.Family); err != nil {
+				return err
+			}
+		}
+		r.sheet = name
+		return r.f.SetSheetName(r.f.GetSheetName(0), name)
+	}
+	r.sheet = name
+	_, err := r.f.NewSheet(name)
+	return err
+}
+
+// setSheetProps provides a function to set the default column width and row
+// height of the worksheet by given Table element.
+func (r *xml2003Reader) setSheetProps(se xml.StartElement) error {
+	var opts SheetPropsOptions
+	for _, attr := range se.Attr {
+		val, err := strconv.ParseFloat(attr.Value, 64)
+		if err != nil || val <= 0 {
+			continue
+		}
+		switch attr.Name.Local {
+		case "DefaultColumnWidth":
+			opts.DefaultColWidth = float64Ptr(odsPixelsToColWidth(val * 96 / 72))
+		case "DefaultRowHeight":
+			opts.DefaultRowHeight, opts.CustomHeight = float64Ptr(val), boolPtr(true)
+		}
+	}
+	if opts.DefaultColWidth == nil && opts.DefaultRowHeight == nil {
+		return nil
+	}
+	return r.f.SetSheetProps(r.sheet, &opts)
+}
+
+// setColumn provides a function to set the width, visibility and style of
+// the columns by given Column element.
+func (r *xml2003Reader) setColumn(column xml2003Column) error {
+	if column.Index > 0 {
+		r.col = column.Index - 1
+	}
+	start, end := r.col+1, r.col+1+column.Span
+	r.col = end
+	if start > MaxColumns {
+		return nil
+	}
+	if end > MaxColumns {
+		end = MaxColumns
+	}
+	startCol, _ := ColumnNumberToName(start)
+	endCol, _ := ColumnNumberToName(end)
+	if column.Width > 0 {
+		if err := r.f.SetColWidth(r.sheet, startCol, endCol, odsPixelsToColWidth(column.Width*96/72)); err != nil {
+			return err
+		}
+	}
+	if column.Hidden == "1" {
+		if err := r.f.SetColVisible(r.sheet, startCol+":"+endCol, false); err != nil {
+			return err
+		}
+	}
+	if column.StyleID != "" {
+		styleID, err := r.styleID(column.StyleID)
+		if err != nil || styleID == 0 {
+			return err
+		}
+		return r.f.SetColStyle(r.sheet, startCol+":"+endCol, styleID)
+	}
+	return nil
+}
+
+// setRow provides a function to set the height, visibility, style and the
+// cells of the rows by given Row element.
+func (r *xml2003Reader) setRow(row xml2003Row) error {
+	if row.Index > 0 {
+		r.row = row.Index - 1
+	}
+	start := r.row + 1
+	r.row += row.Span + 1
+	if start > TotalRows {
+		return nil
+	}
+	for num := start; num <= r.row && num <= TotalRows; num++ {
+		if row.Height > 0 {
+			if err := r.f.SetRowHeight(r.sheet, num, row.Height); err != nil {
+				return err
+			}
+		}
+		if row.Hidden == "1" {
+			if err := r.f.SetRowVisible(r.sheet, num, false); err != nil {
+				return err
+			}
+		}
+		if row.StyleID != "" {
+			styleID, err := r.styleID(row.StyleID)
+			if err != nil {
+				return err
+			}
+			if styleID != 0 {
+				if err = r.f.SetRowStyle(r.sheet, num, num, styleID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	col := 0
+	for _, cell := range row.Cells {
+		if cell.Index > 0 {
+			col = cell.Index - 1
+		}
+		col++
+		if col > MaxColumns {
+			break
+		}
+		ref, _ := CoordinatesToCellName(col, start)
+		if cell.StyleID != "" {
+			styleID, err := r.styleID(cell.StyleID)
+			if err != nil {
+				return err
+			}
+			if err = r.f.SetCellStyle(r.sheet, ref, ref, styleID); err != nil {
+				return err
+			}
+		}
+		var dataType, text string
+		if cell.Data != nil {
+			dataType, text = cell.Data.Type, string(cell.Data.Text)
+		}
+		if cell.Formula != "" {
+			if err := r.setCellFormula(ref, xml2003ToA1(strings.TrimPrefix(cell.Formula, "="), col, start), dataType, text); err != nil {
+				return err
+			}
+		} else if cell.Data != nil {
+			if err := r.setCellValue(ref, dataType, text); err != nil {
+				return err
+			}
+		}
+		if cell.HRef != "" {
+			link, linkType := cell.HRef, "External"
+			if strings.HasPrefix(link, "#") {
+				link, linkType = link[1:], "Location"
+			}
+			var opts []HyperlinkOpts
+			if cell.ScreenTip != "" {
+				opts = append(opts, HyperlinkOpts{Tooltip: &cell.ScreenTip})
+			}
+			if err := r.f.SetCellHyperLink(r.sheet, ref, link, linkType, opts...); err != nil {
+				return err
+			}
+		}
+		if cell.Comment != nil {
+			if err := r.f.AddComment(r.sheet, Comment{Cell: ref, Author: cell.Comment.Author, Text: string(cell.Comment.Data)}); err != nil {
+				return err
+			}
+		}
+		if cell.MergeAcross > 0 || cell.MergeDown > 0 {
+			bottomRight, err := CoordinatesToCellName(col+cell.MergeAcross, start+cell.MergeDown)
+			if err != nil {
+				return err
+			}
+			if err = r.f.MergeCell(r.sheet, ref, bottomRight); err != nil {
+				return err
+			}
+		}
+		col += cell.MergeAcross
+	}
+	return nil
+}
+
+// setCellValue provides a function to set the cell value by the data type of
+// the cell.
+func (r *xml2003Reader) setCellValue(ref, dataType, text string) error {
+	switch dataType {
+	case "Number":
+		if val, err := strconv.ParseFloat(text, 64); err == nil {
+			return r.f.SetCellFloat(r.sheet, ref, val, -1, 64)
+		}
+	case "DateTime":
+		if val, ok := xml2003DateToExcelTime(text); ok {
+			return r.f.SetCellFloat(r.sheet, ref, val, -1, 64)
+		}
+	case "Boolean":
+		return r.f.SetCellBool(r.sheet, ref, text == "1")
+	}
+	return r.f.SetCellStr(r.sheet, ref, text)
+}
+
+// setCellFormula provides a function to set the formula of the cell, and set
+// the calculated result as the cached cell value.
+func (r *xml2003Reader) setCellFormula(ref, formula, dataType, text string) error {
+	if err := r.f.SetCellFormula(r.sheet, ref, formula); err != nil {
+		return err
+	}
+	ws, err := r.f.workSheetReader(r.sheet)
+	if err != nil {
+		return err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	c, _, _, err := ws.prepareCell(ref)
+	if err != nil {
+		return err
+	}
+	switch dataType {
+	case "Number":
+		if _, err = strconv.ParseFloat(text, 64); err == nil {
+			c.T, c.V = "", text
+			return nil
+		}
+	case "DateTime":
+		if val, ok := xml2003DateToExcelTime(text); ok {
+			c.T, c.V = "", strconv.FormatFloat(val, 'f', -1, 64)
+			return nil
+		}
+	case "Boolean":
+		c.T, c.V = "b", "0"
+		if text == "1" {
+			c.V = "1"
+		}
+		return nil
+	case "Error":
+		c.T, c.V = "e", text
+		return nil
+	}
+	c.setStr(text)
+	return nil
+}
+
+// setDefinedNames provides a function to set the defined names by the named
+// ranges of the workbook and worksheets.
+func (r *xml2003Reader) setDefinedNames() error {
+	for _, name := range r.names {
+		if name.Name == "_FilterDatabase" {
+			continue
+		}
+		if name.Name == "Print_Area" || name.Name == "Print_Titles" {
+			name.Name = "_xlnm." + name.Name
+		}
+		if err := r.f.SetDefinedName(&DefinedName{
+			Name:     name.Name,
+			RefersTo: xml2003ToA1(strings.TrimPrefix(name.RefersTo, "="), 1, 1),
+			Scope:    name.localName,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// styleID provides a function to create the style by given style ID of the
+// XML Spreadsheet 2003, and returns the style index.
+func (r *xml2003Reader) styleID(id string) (int, error) {
+	if styleID, ok := r.styleIDs[id]; ok {
+		return styleID, nil
+	}
+	style, ok := r.cellStyle(id, 0)
+	if !ok || id == "Default" {
+		r.styleIDs[id] = 0
+		return 0, nil
+	}
+	styleID, err := r.f.NewStyle(style)
+	r.styleIDs[id] = styleID
+	return styleID, err
+}
+
+// cellFont provides a function to get the font settings by given style ID.
+func (r *xml2003Reader) cellFont(id string) *Font {
+	if style, ok := r.cellStyle(id, 0); ok {
+		return style.Font
+	}
+	return nil
+}
+
+// cellStyle provides a function to get the cell style by given style ID, the
+// settings of the parent style and the default style will be inherited.
+func (r *xml2003Reader) cellStyle(id string, depth int) (*Style, bool) {
+	s, ok := r.styles[id]
+	if !ok || depth > 16 {
+		return &Style{}, false
+	}
+	parent := "Default"
+	if s.Parent != "" {
+		parent = s.Parent
+	}
+	style := &Style{}
+	if id != "Default" {
+		style, _ = r.cellStyle(parent, depth+1)
+	}
+	if s.Alignment != nil {
+		xml2003SetAlignment(style, s)
+	}
+	for _, border := range s.Borders {
+		weight, _ := strconv.Atoi(border.Weight)
+		styles, ok := xml2003BorderStyles[border.LineStyle]
+		if !ok || weight < 0 || weight > 3 {
+			continue
+		}
+		position := map[string]string{
+			"DiagonalLeft": "diagonalDown", "DiagonalRight": "diagonalUp",
+		}[border.Position]
+		if position == "" {
+			position = strings.ToLower(border.Position)
+		}
+		style.Border = append(style.Border, Border{Type: position, Color: odsColor(border.Color), Style: styles[weight]})
+	}
+	if s.Font != nil {
+		xml2003SetFont(style, s)
+	}
+	if s.Interior != nil {
+		if pattern, ok := xml2003FillPatterns[s.Interior.Pattern]; ok {
+			color := odsColor(s.Interior.Color)
+			if pattern != 1 && s.Interior.PatternColor != "" {
+				color = odsColor(s.Interior.PatternColor)
+			}
+			if color != "" {
+				style.Fill = Fill{Type: "pattern", Pattern: pattern, Color: []string{color}}
+			}
+		}
+	}
+	if s.NumberFormat != nil {
+		code, ok := xml2003NumFmts[s.NumberFormat.Format]
+		if !ok {
+			code = s.NumberFormat.Format
+		}
+		style.NumFmt, style.CustomNumFmt = 0, nil
+		if code != "" && code != "General" {
+			style.CustomNumFmt = stringPtr(code)
+		}
+	}
+	if s.Protection != nil {
+		style.Protection = &Protection{Locked: s.Protection.Protected != "0", Hidden: s.Protection.HideFormula == "1"}
+	}
+	return style, true
+}
+
+// xml2003SetAlignment provides a function to set the alignment of the cell
+// style by given Style element.
+func xml2003SetAlignment(style *Style, s *xml2003Style) {
+	if style.Alignment == nil {
+		style.Alignment = &Alignment{}
+	}
+	if s.Alignment.Horizontal != "" {
+		style.Alignment.Horizontal = map[string]string{
+			"Left": "left", "Center": "center", "Right": "right", "Fill": "fill",
+			"Justify": "justify", "CenterAcrossSelection": "centerContinuous",
+			"Distributed": "distributed", "JustifyDistributed": "distributed",
+		}[s.Alignment.Horizontal]
+	}
+	if s.Alignment.Vertical != "" {
+		style.Alignment.Vertical = map[string]string{
+			"Top": "top", "Center": "center", "Bottom": "bottom",
+			"Justify": "justify", "Distributed": "distributed",
+		}[s.Alignment.Vertical]
+	}
+	if s.Alignment.WrapText != "" {
+		style.Alignment.WrapText = s.Alignment.WrapText == "1"
+	}
+	if s.Alignment.ShrinkToFit != "" {
+		style.Alignment.ShrinkToFit = s.Alignment.ShrinkToFit == "1"
+	}
+	if rotate, err := strconv.Atoi(s.Alignment.Rotate); err == nil && rotate >= -90 && rotate <= 90 {
+		style.Alignment.TextRotation = rotate
+		if rotate < 0 {
+			style.Alignment.TextRotation = 90 - rotate
+		}
+	}
+	if s.Alignment.VerticalText == "1" {
+		style.Alignment.TextRotation = 255
+	}
+	if indent, err := strconv.Atoi(s.Alignment.Indent); err == nil {
+		style.Alignment.Indent = indent
+	}
+}
+
+// xml2003SetFont provides a function to set the font of the cell style by
+// given Style element.
+func xml2003SetFont(style *Style, s *xml2003Style) {
+	font := &Font{}
+	if style.Font != nil {
+		*font = *style.Font
+	}
+	if s.Font.FontName != "" {
+		font.Family = s.Font.FontName
+	}
+	if size, err := strconv.ParseFloat(s.Font.Size, 64); err == nil {
+		font.Size = size
+	}
+	if color := odsColor(s.Font.Color); color != "" {
+		font.Color = color
+	}
+	if s.Font.Bold != "" {
+		font.Bold = s.Font.Bold == "1"
+	}
+	if s.Font.Italic != "" {
+		font.Italic = s.Font.Italic == "1"
+	}
+	if s.Font.StrikeThrough != "" {
+		font.Strike = s.Font.StrikeThrough == "1"
+	}
+	if s.Font.Underline != "" {
+		font.Underline = map[string]string{
+			"Single": "single", "Double": "double",
+			"SingleAccounting": "singleAccounting", "DoubleAccounting": "doubleAccounting",
+		}[s.Font.Underline]
+	}
+	if s.Font.VerticalAlign != "" {
+		font.VertAlign = map[string]string{
+			"Superscript": "superscript", "Subscript": "subscript",
+		}[s.Font.VerticalAlign]
+	}
+	style.Font = font
+}
+
+// xml2003DateToExcelTime converts the DateTime data of the XML Spreadsheet
+// 2003, such as "2024-01-15T10:30:00.000", into the Excel date time serial
+// number. The time without date is stored with the date 1899-12-31.
+func xml2003DateToExcelTime(value string) (float64, bool) {
+	for _, layout := range []string{"2006-01-02T15:04:05.999", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if t.Before(excelMinTime1900.AddDate(0, 0, 1)) {
+				return t.Sub(excelMinTime1900).Hours() / 24, true
+			}
+			val, err := timeToExcelTime(t, false)
+			return val, err == nil
+		}
+	}
+	return 0, false
+}
+
+// xml2003ToA1 converts the R1C1 reference style formula of the XML
+// Spreadsheet 2003 into the A1 reference style formula by given cell
+// coordinates, such as converts "SUM(R[-2]C:R[-1]C)" in the cell A3 into
+// "SUM(A1:A2)". The text in the string literals and quoted sheet names will
+// be kept.
+func xml2003ToA1(formula string, col, row int) string {
+	var (
+		buf   strings.Builder
+		quote byte
+		start int
+	)
+	convert := func(end int) {
+		text := formula[start:end]
+		last := 0
+		for _, loc := range xml2003RefExp.FindAllStringSubmatchIndex(text, -1) {
+			if loc[1] == loc[0] || !xml2003IsRefBoundary(formula, start+loc[0], start+loc[1]) {
+				continue
+			}
+			group := func(i int) string {
+				if loc[2*i] < 0 {
+					return ""
+				}
+				return text[loc[2*i]:loc[2*i+1]]
+			}
+			buf.WriteString(text[last:loc[0]])
+			var ref string
+			switch {
+			case text[loc[0]] == 'R' && strings.Contains(text[loc[0]:loc[1]], "C"):
+				r, rowAbs := xml2003RefIndex(group(1), row)
+				c, colAbs := xml2003RefIndex(group(2), col)
+				colName, _ := ColumnNumberToName(c)
+				ref = colAbs + colName + rowAbs + strconv.Itoa(r)
+			case text[loc[0]] == 'R':
+				r, rowAbs := xml2003RefIndex(group(3), row)
+				ref = rowAbs + strconv.Itoa(r)
+				if !xml2003IsRangePart(formula, start+loc[0], start+loc[1]) {
+					ref += ":" + ref
+				}
+			default:
+				c, colAbs := xml2003RefIndex(group(4), col)
+				colName, _ := ColumnNumberToName(c)
+				ref = colAbs + colName
+				if !xml2003IsRangePart(formula, start+loc[0], start+loc[1]) {
+					ref += ":" + ref
+				}
+			}
+			buf.WriteString(ref)
+			last = loc[1]
+		}
+		buf.WriteString(text[last:])
+	}
+	for i := 0; i < len(formula); i++ {
+		ch := formula[i]
+		if quote != 0 {
+			if ch == quote {
+				quote = 0
+				buf.WriteString(formula[start : i+1])
+				start = i + 1
+			}
+			continue
+		}
+		if ch == '"' || ch == '\'' {
+			convert(i)
+			quote, start = ch, i
+		}
+	}
+	if quote != 0 {
+		buf.WriteString(formula[start:])
+		return buf.String()
+	}
+	convert(len(formula))
+	return buf.String()
+}
+
+// xml2003RefIndex returns the row or column number by given R1C1 reference
+// index, such as "[-1]" for relative reference, "2" for absolute reference,
+// and the empty string for the current row or column. The "$" will be
+// returned for the absolute reference.
+func xml2003RefIndex(index string, current int) (int, string) {
+	if index == "" {
+		return current, ""
+	}
+	if strings.HasPrefix(index, "[") {
+		offset, _ := strconv.Atoi(strings.Trim(index, "[]"))
+		return current + offset, ""
+	}
+	num, _ := strconv.Atoi(index)
+	return num, "$"
+}
+
+// xml2003IsRefBoundary checks if the matched R1C1 reference by given position
+// in the formula is not a part of the function name or defined name.
+func xml2003IsRefBoundary(formula string, start, end int) bool {
+	isNameChar := func(ch byte) bool {
+		return ch == '_' || ch == '.' || ch >= '0' && ch <= '9' || ch >= 'A' && ch <= 'Z' || ch >= 'a' && ch <= 'z'
+	}
+	if start > 0 && (isNameChar(formula[start-1]) || formula[start-1] == '$') {
+		return false
+	}
+	return end >= len(formula) || !isNameChar(formula[end]) && formula[end] != '('
+}
+
+// xml2003IsRangePart checks if the matched R1C1 row or column reference by
+// given position in the formula is a part of the range reference.
+func xml2003IsRangePart(formula string, start, end int) bool {
+	return start > 0 && formula[start-1] == ':' || end < len(formula) && formula[end] == ':'
+}
@@ -0,0 +1,179 @@
+package excelize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenXML2003(t *testing.T) {
+	content := `<?xml version="1.0"?>
+<?mso-application progid="Excel.Sheet"?>
+<Workbook xmlns="urn:schemas-microsoft-com:office:spreadsheet" xmlns:o="urn:schemas-microsoft-com:office:office" xmlns:x="urn:schemas-microsoft-com:office:excel" xmlns:ss="urn:schemas-microsoft-com:office:spreadsheet" xmlns:html="http://www.w3.org/TR/REC-html40">
+ <Styles>
+  <Style ss:ID="Default" ss:Name="Normal"><Alignment ss:Vertical="Bottom"/><Font ss:FontName="Arial" ss:Size="10"/></Style>
+  <Style ss:ID="s1"><Font ss:Bold="1" ss:Color="#FF0000" ss:Underline="Single"/><Interior ss:Color="#FFFF00" ss:Pattern="Solid"/>
+   <Borders><Border ss:Position="Bottom" ss:LineStyle="Continuous" ss:Weight="2"/><Border ss:Position="DiagonalLeft" ss:LineStyle="Dash" ss:Weight="1"/><Border ss:Position="Top" ss:LineStyle="None"/></Borders></Style>
+  <Style ss:ID="s2" ss:Parent="s1"><Alignment ss:Horizontal="Center" ss:Rotate="-45" ss:WrapText="1"/><NumberFormat ss:Format="Short Date"/><Protection ss:Protected="0"/></Style>
+  <Style ss:ID="s3"><NumberFormat ss:Format="0.000"/><Interior ss:Color="#FFFFFF" ss:Pattern="Gray25" ss:PatternColor="#00FF00"/><Alignment ss:VerticalText="1"/></Style>
+ </Styles>
+ <Names><NamedRange ss:Name="Total" ss:RefersTo="=Orders!R2C2:R3C2"/><NamedRange ss:Name="_FilterDatabase" ss:RefersTo="=Orders!R1C1:R3C2" ss:Hidden="1"/></Names>
+ <Worksheet ss:Name="Orders">
+  <Names><NamedRange ss:Name="Print_Area" ss:RefersTo="=Orders!R1C1:R4C3"/></Names>
+  <Table ss:DefaultColumnWidth="60" ss:DefaultRowHeight="15">
+   <Column ss:Width="90" ss:StyleID="s3"/><Column ss:Index="3" ss:Span="1" ss:Hidden="1"/>
+   <Row ss:Height="20" ss:StyleID="s1"><Cell ss:MergeAcross="1" ss:StyleID="s1"><Data ss:Type="String">Order <B>list</B></Data><Comment ss:Author="Tester"><ss:Data><Font>Note</Font></ss:Data></Comment></Cell><Cell ss:Index="4" ss:HRef="https://github.com/xuri/excelize" x:HRefScreenTip="Excelize"><Data ss:Type="String">Link</Data></Cell></Row>
+   <Row><Cell><Data ss:Type="Boolean">1</Data></Cell><Cell><Data ss:Type="Number">1.5</Data></Cell><Cell ss:StyleID="s2"><Data ss:Type="DateTime">2024-01-15T00:00:00.000</Data></Cell><Cell ss:HRef="#Other!A1"><Data ss:Type="DateTime">1899-12-31T12:00:00.000</Data></Cell></Row>
+   <Row ss:Index="3" ss:Span="1" ss:Hidden="1"><Cell ss:Index="2"><Data ss:Type="Number">2</Data></Cell><Cell ss:MergeDown="1"><Data ss:Type="Error">#N/A</Data></Cell></Row>
+   <Row><Cell ss:Formula="=SUM(R[-3]C[1]:R[-2]C[1])+Total"><Data ss:Type="Number">3.5</Data></Cell><Cell ss:Formula="=R2C+COUNT(C[-1],R1)&amp;&quot;R1C1&quot;"><Data ss:Type="String">x</Data></Cell><Cell ss:Formula="=ISNA(R[-1]C)"><Data ss:Type="Boolean">1</Data></Cell><Cell ss:Formula="=NA()"><Data ss:Type="Error">#N/A</Data></Cell><Cell ss:Formula="=DATE(2024,1,1)"><Data ss:Type="DateTime">2024-01-01T00:00:00.000</Data></Cell></Row>
+  </Table>
+  <WorksheetOptions xmlns="urn:schemas-microsoft-com:office:excel"><Selected/></WorksheetOptions>
+ </Worksheet>
+ <Worksheet ss:Name="Other">
+  <Table><Row><Cell><Data ss:Type="String">A</Data></Cell></Row></Table>
+  <WorksheetOptions xmlns="urn:schemas-microsoft-com:office:excel"><Visible>SheetHidden</Visible></WorksheetOptions>
+ </Worksheet>
+</Workbook>`
+	f, err := OpenReader(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Orders", "Other"}, f.GetSheetList())
+	assert.Equal(t, 0, f.GetActiveSheetIndex())
+	rows, err := f.GetRows("Orders", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Order list", "", "", "Link"},
+		{"1", "1.5", "45306", "0.5"},
+		{"", "2", "#N/A"},
+		nil,
+		{"3.5", "x", "1", "#N/A", "45292"},
+	}, rows)
+	for cell, expected := range map[string]string{
+		"A5": "SUM(B2:B3)+Total",
+		"B5": "B$2+COUNT(A:A,$1:$1)&\"R1C1\"",
+		"C5": "ISNA(C4)",
+	} {
+		formula, err := f.GetCellFormula("Orders", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula)
+	}
+	cellType, err := f.GetCellType("Orders", "C5")
+	assert.NoError(t, err)
+	assert.Equal(t, CellTypeBool, cellType)
+	mergeCells, err := f.GetMergeCells("Orders")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 2)
+	assert.Equal(t, "A1:B1", mergeCells[0][0])
+	assert.Equal(t, "C3:C4", mergeCells[1][0])
+	comments, err := f.GetComments("Orders")
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Note", comments[0].Text)
+	link, target, err := f.GetCellHyperLink("Orders", "D1")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://github.com/xuri/excelize", target)
+	link, target, err = f.GetCellHyperLink("Orders", "D2")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "Other!A1", target)
+	width, err := f.GetColWidth("Orders", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, 16.35, width)
+	visible, err := f.GetColVisible("Orders", "C")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	height, err := f.GetRowHeight("Orders", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, height)
+	for _, row := range []int{3, 4} {
+		visible, err = f.GetRowVisible("Orders", row)
+		assert.NoError(t, err)
+		assert.False(t, visible)
+	}
+	props, err := f.GetSheetProps("Orders")
+	assert.NoError(t, err)
+	assert.Equal(t, 15.0, *props.DefaultRowHeight)
+	visible, err = f.GetSheetVisible("Other")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	assert.Equal(t, []DefinedName{
+		{Name: "Total", RefersTo: "Orders!$B$2:$B$3", Scope: "Workbook"},
+		{Name: "_xlnm.Print_Area", RefersTo: "Orders!$A$1:$C$4", Scope: "Orders"},
+	}, f.GetDefinedName())
+	fontName, err := f.GetDefaultFont()
+	assert.NoError(t, err)
+	assert.Equal(t, "Arial", fontName)
+
+	styleID, err := f.GetCellStyle("Orders", "C2")
+	assert.NoError(t, err)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, "m/d/yyyy", *style.CustomNumFmt)
+	assert.Equal(t, &Font{Bold: true, Underline: "single", Color: "FF0000", Family: "Arial", Size: 10}, style.Font)
+	assert.Equal(t, Fill{Type: "pattern", Pattern: 1, Color: []string{"FFFF00"}}, style.Fill)
+	assert.Equal(t, []Border{
+		{Type: "bottom", Color: "000000", Style: 2},
+		{Type: "diagonalDown", Color: "000000", Style: 3},
+	}, style.Border)
+	assert.Equal(t, &Alignment{Horizontal: "center", Vertical: "bottom", TextRotation: 135, WrapText: true}, style.Alignment)
+	assert.Equal(t, &Protection{}, style.Protection)
+	styleID, err = f.GetColStyle("Orders", "A")
+	assert.NoError(t, err)
+	style, err = f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.000", *style.CustomNumFmt)
+	assert.Equal(t, Fill{Type: "pattern", Pattern: 4, Color: []string{"00FF00"}}, style.Fill)
+	assert.Equal(t, 255, style.Alignment.TextRotation)
+	assert.NoError(t, f.Close())
+
+	// Test open the XML Spreadsheet 2003 without worksheet
+	_, err = OpenReader(strings.NewReader(`<Workbook xmlns="urn:schemas-microsoft-com:office:spreadsheet"/>`))
+	assert.Equal(t, ErrWorkbookFileFormat, err)
+	// Test open the XML Spreadsheet 2003 with invalid content
+	for _, content := range []string{
+		`<Workbook xmlns="urn:schemas-microsoft-com:office:spreadsheet">`,
+		`<Workbook xmlns="urn:schemas-microsoft-com:office:spreadsheet"><Styles><Style ss:ID="s1"><Font></Style>`,
+		`<Workbook xmlns="urn:schemas-microsoft-com:office:spreadsheet"><NamedRange><Worksheet>`,
+		`<Workbook xmlns="urn:schemas-microsoft-com:office:spreadsheet"><Worksheet><Table><Column><Row>`,
+		`<Workbook xmlns="urn:schemas-microsoft-com:office:spreadsheet"><Worksheet><Table><Row><Cell>`,
+		`<Workbook xmlns="urn:schemas-microsoft-com:office:spreadsheet"><Worksheet><Visible><Row>`,
+	} {
+		_, err = OpenReader(strings.NewReader(content))
+		assert.Error(t, err, content)
+	}
+	// Test open the XML Spreadsheet 2003 with invalid sheet name
+	_, err = OpenReader(strings.NewReader(`<Workbook xmlns="urn:schemas-microsoft-com:office:spreadsheet"><Worksheet ss:Name="a:b"/></Workbook>`))
+	assert.Equal(t, ErrSheetNameInvalid, err)
+	// Test open the XML Spreadsheet 2003 with invalid defined name
+	_, err = OpenReader(strings.NewReader(`<Workbook xmlns="urn:schemas-microsoft-com:office:spreadsheet"><Names><NamedRange ss:Name="1a" ss:RefersTo="=1"/></Names><Worksheet/></Workbook>`))
+	assert.EqualError(t, err, newInvalidNameError("1a").Error())
+}
+
+func TestXML2003ToA1(t *testing.T) {
+	for formula, expected := range map[string]string{
+		"RC+R[1]C[-1]+R3C4":          "B2+A3+$D$3",
+		"SUM(R1:R[1],C2:C)":          "SUM($1:3,$B:B)",
+		"CHAR(65)&ROUND(RC,0)&ROW()": "CHAR(65)&ROUND(B2,0)&ROW()",
+		"'R1C1 sheet'!RC[1]&\"RC\"":  "'R1C1 sheet'!C2&\"RC\"",
+		"R_1+RC_NAME+Sheet1!C":       "R_1+RC_NAME+Sheet1!B:B",
+		"\"unclosed RC":              "\"unclosed RC",
+	} {
+		assert.Equal(t, expected, xml2003ToA1(formula, 2, 2), formula)
+	}
+}
+
+func TestXML2003DateToExcelTime(t *testing.T) {
+	for value, expected := range map[string]float64{
+		"2024-01-15T06:00:00.000": 45306.25,
+		"2024-01-15T06:00:00":     45306.25,
+		"2024-01-15":              45306,
+		"1899-12-31T18:00:00.000": 0.75,
+	} {
+		val, ok := xml2003DateToExcelTime(value)
+		assert.True(t, ok)
+		assert.Equal(t, expected, val)
+	}
+	_, ok := xml2003DateToExcelTime("x")
+	assert.False(t, ok)
+}
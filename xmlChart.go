@@ -616,3 +616,141 @@ type ChartSeries struct {
 	Marker            ChartMarker
 	DataLabelPosition ChartDataLabelPositionType
 }
+
+// decodeChartSpace defines the structure used to parse the chartSpace element
+// for rendering the chart.
+type decodeChartSpace struct {
+	XMLName xml.Name         `xml:"chartSpace"`
+	Chart   decodeChart      `xml:"chart"`
+	SpPr    *decodeChartSpPr `xml:"spPr"`
+}
+
+// decodeChart defines the structure used to parse the chart element for
+// rendering the chart.
+type decodeChart struct {
+	Title            *decodeChartTitle  `xml:"title"`
+	AutoTitleDeleted *attrValBool       `xml:"autoTitleDeleted"`
+	PlotArea         decodePlotArea     `xml:"plotArea"`
+	Legend           *decodeChartLegend `xml:"legend"`
+}
+
+// decodeChartTitle defines the structure used to parse the text of the title
+// element.
+type decodeChartTitle struct {
+	T      []string `xml:"tx>rich>p>r>t"`
+	StrRef *cStrRef `xml:"tx>strRef"`
+}
+
+// decodeChartLegend defines the structure used to parse the legend element.
+type decodeChartLegend struct {
+	LegendPos *attrValString `xml:"legendPos"`
+}
+
+// decodePlotArea defines the structure used to parse the plotArea element,
+// the chart groups are the elements with the "Chart" suffix, such as
+// barChart and lineChart.
+type decodePlotArea struct {
+	Charts []decodeChartGroup `xml:",any"`
+	CatAx  []decodeChartAxis  `xml:"catAx"`
+	ValAx  []decodeChartAxis  `xml:"valAx"`
+	SpPr   *decodeChartSpPr   `xml:"spPr"`
+}
+
+// decodeChartGroup defines the structure used to parse the chart group
+// element in the plot area, such as barChart and lineChart.
+type decodeChartGroup struct {
+	XMLName     xml.Name
+	BarDir      *attrValString      `xml:"barDir"`
+	Grouping    *attrValString      `xml:"grouping"`
+	RadarStyle  *attrValString      `xml:"radarStyle"`
+	VaryColors  *attrValBool        `xml:"varyColors"`
+	Wireframe   *attrValBool        `xml:"wireframe"`
+	HoleSize    *attrValInt         `xml:"holeSize"`
+	BubbleScale *attrValFloat       `xml:"bubbleScale"`
+	Ser         []decodeChartSeries `xml:"ser"`
+}
+
+// decodeChartAxis defines the structure used to parse the catAx and valAx
+// element.
+type decodeChartAxis struct {
+	Delete         *attrValBool  `xml:"delete"`
+	Scaling        *cScaling     `xml:"scaling"`
+	MajorGridlines *xlsxInnerXML `xml:"majorGridlines"`
+	NumFmt         *cNumFmt      `xml:"numFmt"`
+	MajorUnit      *attrValFloat `xml:"majorUnit"`
+}
+
+// decodeChartSeries defines the structure used to parse the ser element.
+type decodeChartSeries struct {
+	Tx         *decodeChartData   `xml:"tx"`
+	SpPr       *decodeChartSpPr   `xml:"spPr"`
+	DPt        []decodeChartDPt   `xml:"dPt"`
+	Marker     *decodeChartMarker `xml:"marker"`
+	Cat        *decodeChartData   `xml:"cat"`
+	Val        *decodeChartData   `xml:"val"`
+	XVal       *decodeChartData   `xml:"xVal"`
+	YVal       *decodeChartData   `xml:"yVal"`
+	BubbleSize *decodeChartData   `xml:"bubbleSize"`
+}
+
+// decodeChartData defines the structure used to parse the data source of the
+// series, such as the tx, cat and val element.
+type decodeChartData struct {
+	NumRef *cNumRef `xml:"numRef"`
+	StrRef *cStrRef `xml:"strRef"`
+	V      string   `xml:"v"`
+}
+
+// decodeChartDPt defines the structure used to parse the dPt element.
+type decodeChartDPt struct {
+	IDx  *attrValInt      `xml:"idx"`
+	SpPr *decodeChartSpPr `xml:"spPr"`
+}
+
+// decodeChartMarker defines the structure used to parse the marker element.
+type decodeChartMarker struct {
+	Symbol *attrValString   `xml:"symbol"`
+	Size   *attrValInt      `xml:"size"`
+	SpPr   *decodeChartSpPr `xml:"spPr"`
+}
+
+// decodeChartSpPr defines the structure used to parse the fill and outline
+// of the spPr element.
+type decodeChartSpPr struct {
+	NoFill    *xlsxInnerXML     `xml:"noFill"`
+	SolidFill *decodeChartColor `xml:"solidFill"`
+	Ln        *decodeChartLn    `xml:"ln"`
+}
+
+// decodeChartLn defines the structure used to parse the ln element.
+type decodeChartLn struct {
+	W         int               `xml:"w,attr"`
+	NoFill    *xlsxInnerXML     `xml:"noFill"`
+	SolidFill *decodeChartColor `xml:"solidFill"`
+}
+
+// decodeChartColor defines the structure used to parse the RGB color and
+// scheme color of the solidFill element.
+type decodeChartColor struct {
+	SrgbClr   *attrValString        `xml:"srgbClr"`
+	SchemeClr *decodeChartSchemeClr `xml:"schemeClr"`
+}
+
+// decodeChartSchemeClr defines the structure used to parse the schemeClr
+// element.
+type decodeChartSchemeClr struct {
+	Val    string      `xml:"val,attr"`
+	LumMod *attrValInt `xml:"lumMod"`
+	LumOff *attrValInt `xml:"lumOff"`
+}
+
+// decodeChartAnchor defines the structure used to parse the starting and
+// ending anchors and the chart relationship ID of the graphic frame in the
+// cell anchor.
+type decodeChartAnchor struct {
+	From  *decodeFrom `xml:"from"`
+	To    *decodeTo   `xml:"to"`
+	Chart *struct {
+		RID string `xml:"id,attr"`
+	} `xml:"graphicFrame>graphic>graphicData>chart"`
+}